@@ -6,6 +6,8 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 )
 
+var driftTimeVerifierLogger = syscore.NewLogger("drift-time-verifier")
+
 // DriftTimeVerifier checks the timestamp difference between local and device UNIX time.
 type DriftTimeVerifier struct {
 	clock            syscore.SystemClock
@@ -32,7 +34,7 @@ func (v *DriftTimeVerifier) VerifyTime(deviceTs int64) bool {
 
 	localTs, err := v.clock.GetTimestamp()
 	if err != nil {
-		syscore.LogErr.Printf("failed to get local time: %v", err)
+		driftTimeVerifierLogger.Error("failed to get local time", "err", err)
 
 		return false
 	}
@@ -0,0 +1,53 @@
+package devcore
+
+import (
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var driftTrackerLogger = syscore.NewLogger("drift-tracker")
+
+// DriftHandler handles the device clock drift.
+type DriftHandler interface {
+	// HandleDrift handles the difference between local and device UNIX time, in seconds.
+	HandleDrift(drift int64)
+}
+
+// DriftTracker measures the difference between local and device UNIX time.
+//
+// Remarks:
+//   - Drift is measured regardless of the time verification result.
+type DriftTracker struct {
+	clock   syscore.SystemClock
+	handler DriftHandler
+}
+
+// NewDriftTracker is an initialization of DriftTracker.
+//
+// Parameters:
+//   - clock to get the local UNIX time.
+//   - handler to handle the measured drift.
+func NewDriftTracker(clock syscore.SystemClock, handler DriftHandler) *DriftTracker {
+	return &DriftTracker{
+		clock:   clock,
+		handler: handler,
+	}
+}
+
+// TrackTime measures the drift for the provided device UNIX time.
+//
+// Remarks:
+//   - Invalid device UNIX time is ignored.
+func (t *DriftTracker) TrackTime(deviceTs int64) {
+	if deviceTs < 0 {
+		return
+	}
+
+	localTs, err := t.clock.GetTimestamp()
+	if err != nil {
+		driftTrackerLogger.Error("failed to get local time", "err", err)
+
+		return
+	}
+
+	t.handler.HandleDrift(localTs - deviceTs)
+}
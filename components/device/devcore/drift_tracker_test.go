@@ -0,0 +1,43 @@
+package devcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestDriftTrackerTrackTime(t *testing.T) {
+	clock := &testDriftTimeVerifierTestClock{
+		timestamp: 200,
+	}
+
+	stats := NewTimeStatsTracker(NewIDHolder(&testDataHandler{}))
+	tracker := NewDriftTracker(clock, stats)
+
+	tracker.TrackTime(-1)
+	require.Equal(t, uint64(0), stats.Get().Drift.Samples)
+
+	tracker.TrackTime(190)
+	tracker.TrackTime(205)
+	tracker.TrackTime(100)
+
+	drift := stats.Get().Drift
+	require.Equal(t, uint64(3), drift.Samples)
+	require.Equal(t, int64(100), drift.Current)
+	require.Equal(t, int64(-5), drift.Min)
+	require.Equal(t, int64(100), drift.Max)
+}
+
+func TestDriftTrackerFailedToGetTimestamp(t *testing.T) {
+	clock := &testDriftTimeVerifierTestClock{
+		err: status.StatusTimeout,
+	}
+
+	stats := NewTimeStatsTracker(NewIDHolder(&testDataHandler{}))
+	tracker := NewDriftTracker(clock, stats)
+
+	tracker.TrackTime(100)
+	require.Equal(t, uint64(0), stats.Get().Drift.Samples)
+}
@@ -0,0 +1,42 @@
+package devcore
+
+import "errors"
+
+// FanoutDataHandler passes device data to all the underlying handlers.
+//
+// Remarks:
+//   - Data is passed to all handlers even if some of them fail, errors are combined.
+type FanoutDataHandler struct {
+	handlers []DataHandler
+}
+
+// HandleTelemetry passes telemetry data to all the underlying handlers.
+func (h *FanoutDataHandler) HandleTelemetry(deviceID string, js JSON) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if err := handler.HandleTelemetry(deviceID, js); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// HandleRegistration passes registration data to all the underlying handlers.
+func (h *FanoutDataHandler) HandleRegistration(deviceID string, js JSON) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if err := handler.HandleRegistration(deviceID, js); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Add adds handler to be notified when device data is received.
+func (h *FanoutDataHandler) Add(handler DataHandler) {
+	h.handlers = append(h.handlers, handler)
+}
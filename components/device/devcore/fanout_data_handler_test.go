@@ -0,0 +1,40 @@
+package devcore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanoutDataHandler(t *testing.T) {
+	first := testDataHandler{}
+	second := testDataHandler{}
+
+	handler := FanoutDataHandler{}
+	handler.Add(&first)
+	handler.Add(&second)
+
+	require.Nil(t, handler.HandleRegistration("0xABCD",
+		JSON{"timestamp": float64(13), "device_id": "0xABCD"}))
+	require.Equal(t, "0xABCD", first.registration.DeviceID)
+	require.Equal(t, "0xABCD", second.registration.DeviceID)
+
+	require.Nil(t, handler.HandleTelemetry("0xABCD",
+		JSON{"timestamp": float64(13), "status": "foo"}))
+	require.Equal(t, "foo", first.telemetry.Status)
+	require.Equal(t, "foo", second.telemetry.Status)
+}
+
+func TestFanoutDataHandlerFailed(t *testing.T) {
+	first := testDataHandler{err: errors.New("failed to handle")}
+	second := testDataHandler{}
+
+	handler := FanoutDataHandler{}
+	handler.Add(&first)
+	handler.Add(&second)
+
+	err := handler.HandleTelemetry("0xABCD", JSON{"timestamp": float64(13), "status": "foo"})
+	require.True(t, errors.Is(err, first.err))
+	require.Equal(t, "foo", second.telemetry.Status)
+}
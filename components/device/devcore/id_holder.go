@@ -37,6 +37,24 @@ func (h *IDHolder) Get() string {
 //   - Should be used by a single goroutine.
 //   - Device ID is changed very rarely, rw-lock is used to reduce contention.
 func (h *IDHolder) HandleRegistration(deviceID string, js JSON) error {
+	h.Set(deviceID)
+
+	return h.handler.HandleRegistration(deviceID, js)
+}
+
+// HandleTelemetry propagates call to the underlying data handler.
+func (h *IDHolder) HandleTelemetry(deviceID string, js JSON) error {
+	return h.handler.HandleTelemetry(deviceID, js)
+}
+
+// Set updates the device ID.
+//
+// Remarks:
+//   - Should be used by a single goroutine.
+//   - Allows to update the device ID if the device doesn't provide the registration data.
+//   - Device metrics of the previous device ID are removed, see DeleteDeviceMetrics().
+//     HTTP fetch metrics are handled by the fetchers themselves.
+func (h *IDHolder) Set(deviceID string) {
 	h.mu.RLock()
 	id := h.id
 	h.mu.RUnlock()
@@ -45,12 +63,7 @@ func (h *IDHolder) HandleRegistration(deviceID string, js JSON) error {
 		h.mu.Lock()
 		h.id = deviceID
 		h.mu.Unlock()
-	}
-
-	return h.handler.HandleRegistration(deviceID, js)
-}
 
-// HandleTelemetry propagates call to the underlying data handler.
-func (h *IDHolder) HandleTelemetry(deviceID string, js JSON) error {
-	return h.handler.HandleTelemetry(deviceID, js)
+		DeleteDeviceMetrics(id)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"maps"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,3 +73,23 @@ func TestIDHolderGetIDChanged(t *testing.T) {
 	require.Nil(t, holder.HandleRegistration(newDeviceID, testRegistration))
 	require.Equal(t, newDeviceID, holder.Get())
 }
+
+func TestIDHolderSetDeleteMetrics(t *testing.T) {
+	holder := NewIDHolder(&testIDHolderDataHandler{})
+	holder.Set("0xIDHOLDERPREV")
+
+	count := testutil.CollectAndCount(clockDrift)
+
+	tracker := NewTimeStatsTracker(holder)
+	tracker.HandleDrift(2)
+	require.Equal(t, count+1, testutil.CollectAndCount(clockDrift))
+
+	holder.Set("0xIDHOLDERNEXT")
+	require.Equal(t, count, testutil.CollectAndCount(clockDrift))
+
+	tracker.HandleDrift(2)
+	require.Equal(t, count+1, testutil.CollectAndCount(clockDrift))
+
+	DeleteDeviceMetrics("0xIDHOLDERNEXT")
+	require.Equal(t, count, testutil.CollectAndCount(clockDrift))
+}
@@ -0,0 +1,83 @@
+package devcore
+
+import (
+	"errors"
+	"log/slog"
+)
+
+func parseTimestamp(js JSON) (int64, error) {
+	ts, ok := js["timestamp"]
+	if !ok {
+		return -1, errors.New("missing timestamp field")
+	}
+
+	timestamp, ok := ts.(float64)
+	if !ok {
+		return -1, errors.New("invalid type for timestamp")
+	}
+
+	return int64(timestamp), nil
+}
+
+// correctTimestamp replaces the data timestamp with the corrected one and adds
+// the timestamp source field.
+func correctTimestamp(
+	corrector TimeCorrector,
+	js JSON,
+	timestamp int64,
+) (int64, string, error) {
+	corrected, source, err := corrector.CorrectTime(timestamp)
+	if err != nil {
+		return -1, "", err
+	}
+
+	js["timestamp"] = float64(corrected)
+	js[TimestampSourceKey] = source
+
+	return corrected, source, nil
+}
+
+// correctDeviceTimestamp corrects the data timestamp and reports the correction,
+// returns true if the timestamp is corrected.
+//
+// Remarks:
+//   - The timestamp isn't corrected if the corrector isn't set.
+func correctDeviceTimestamp(
+	corrector TimeCorrector,
+	logger *slog.Logger,
+	deviceID string,
+	js JSON,
+	timestamp int64,
+) bool {
+	if corrector == nil {
+		return false
+	}
+
+	corrected, source, err := correctTimestamp(corrector, js, timestamp)
+	if err != nil {
+		logger.Warn("failed to correct device timestamp", "timestamp", timestamp, "err", err)
+
+		return false
+	}
+
+	timestampCorrectionsTotal.WithLabelValues(metricsDeviceID(deviceID), source).Inc()
+
+	logger.Debug("device timestamp corrected",
+		"timestamp", timestamp, "corrected", corrected, "source", source)
+
+	return true
+}
+
+func parseDeviceID(js JSON) (string, error) {
+	id, ok := js["device_id"]
+	if !ok {
+		return "", errors.New("missing device_id field")
+	}
+
+	deviceID, ok := id.(string)
+	if !ok {
+		return "", errors.New("invalid type for device_id")
+	}
+
+	return deviceID, nil
+}
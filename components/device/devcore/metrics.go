@@ -0,0 +1,172 @@
+package devcore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var (
+	pollDuration = promauto.With(sysmetrics.Registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "poll_duration_seconds",
+			Help:      "Duration of the single device polling.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"device_id"},
+	)
+
+	pollTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "poll_total",
+			Help:      "Number of device polls by result.",
+		},
+		[]string{"device_id", "result"},
+	)
+
+	validationFailuresTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "validation_failures_total",
+			Help:      "Number of device data validation failures by reason.",
+		},
+		[]string{"device_id", "reason"},
+	)
+
+	timeSyncTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "time_sync_total",
+			Help:      "Number of device UNIX time synchronization attempts by result.",
+		},
+		[]string{"device_id", "result"},
+	)
+
+	timestampCorrectionsTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "timestamp_corrections_total",
+			Help:      "Number of device data samples with corrected timestamp by source.",
+		},
+		[]string{"device_id", "source"},
+	)
+
+	timeSyncRefusalsTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "time_sync_refusals_total",
+			Help:      "Number of refused device UNIX time synchronizations by reason.",
+		},
+		[]string{"device_id", "reason"},
+	)
+
+	timeSyncLastSuccess = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "time_sync_last_success_timestamp_seconds",
+			Help:      "UNIX time of the last successful device UNIX time synchronization.",
+		},
+		[]string{"device_id"},
+	)
+
+	clockDrift = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "clock_drift_seconds",
+			Help:      "Latest difference between local and device UNIX time.",
+		},
+		[]string{"device_id"},
+	)
+
+	clockDriftMin = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "clock_drift_min_seconds",
+			Help:      "Minimum difference between local and device UNIX time.",
+		},
+		[]string{"device_id"},
+	)
+
+	clockDriftMax = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "clock_drift_max_seconds",
+			Help:      "Maximum difference between local and device UNIX time.",
+		},
+		[]string{"device_id"},
+	)
+
+	clockDriftAvg = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "device",
+			Name:      "clock_drift_avg_seconds",
+			Help:      "Average difference between local and device UNIX time.",
+		},
+		[]string{"device_id"},
+	)
+)
+
+const (
+	validationReasonMalformed        = "malformed"
+	validationReasonInvalidTimestamp = "invalid_timestamp"
+	validationReasonDeviceIDMismatch = "device_id_mismatch"
+)
+
+// metricsUnknownDeviceID labels the metrics until the registration data is received.
+const metricsUnknownDeviceID = "unknown"
+
+// metricsDeviceID returns the device ID label value, the device ID is unknown
+// until the registration data is received.
+func metricsDeviceID(deviceID string) string {
+	if deviceID == "" {
+		return metricsUnknownDeviceID
+	}
+
+	return deviceID
+}
+
+// DeleteDeviceMetrics removes the polling and time metrics of the device.
+//
+// Remarks:
+//   - Should be called when the device is removed, to not expose stale series.
+//   - Metrics of the previous device ID are removed automatically by IDHolder when
+//     the device ID is changed.
+func DeleteDeviceMetrics(deviceID string) {
+	if deviceID == "" || deviceID == metricsUnknownDeviceID {
+		return
+	}
+
+	labels := prometheus.Labels{"device_id": deviceID}
+
+	for _, vec := range []interface {
+		DeletePartialMatch(labels prometheus.Labels) int
+	}{
+		pollDuration,
+		pollTotal,
+		validationFailuresTotal,
+		timeSyncTotal,
+		timestampCorrectionsTotal,
+		timeSyncRefusalsTotal,
+		timeSyncLastSuccess,
+		clockDrift,
+		clockDriftMin,
+		clockDriftMax,
+		clockDriftAvg,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+}
@@ -3,9 +3,12 @@ package devcore
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/open-control-systems/device-hub/components/status"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
 )
 
 // PollDevice actively fetches telemetry and registration data.
@@ -15,6 +18,9 @@ type PollDevice struct {
 	dataHandler         DataHandler
 	timeSynchronizer    TimeSynchronizer
 	timeVerifier        TimeVerifier
+	timeCorrector       TimeCorrector
+	driftTracker        *DriftTracker
+	logger              *slog.Logger
 	deviceID            string
 }
 
@@ -25,6 +31,10 @@ type PollDevice struct {
 //   - telemetryFetcher to fetch device telemetry data.
 //   - dataHandler to handle fetched telemetry and registration data.
 //   - timeSynchronizer to synchronize the UNIX time for a device.
+//
+// Remarks:
+//   - registrationFetcher can be nil if the device registration isn't polled,
+//     the device ID is taken from the telemetry data then.
 func NewPollDevice(
 	registrationFetcher Fetcher,
 	telemetryFetcher Fetcher,
@@ -38,35 +48,93 @@ func NewPollDevice(
 		dataHandler:         dataHandler,
 		timeSynchronizer:    timeSynchronizer,
 		timeVerifier:        timeVerifier,
+		logger:              syscore.NewLogger("poll-device"),
 	}
 }
 
+// SetLogger sets the logger for the device events.
+//
+// Remarks:
+//   - Should be called before Run().
+func (d *PollDevice) SetLogger(logger *slog.Logger) {
+	d.logger = logger
+}
+
+// SetTimeCorrector sets the corrector for the data with invalid timestamp.
+//
+// Remarks:
+//   - Data with invalid timestamp is rejected if the corrector isn't set.
+//   - Should be called before Run().
+func (d *PollDevice) SetTimeCorrector(corrector TimeCorrector) {
+	d.timeCorrector = corrector
+}
+
+// SetDriftTracker sets the tracker to measure the device clock drift on each data.
+//
+// Remarks:
+//   - Drift is measured regardless of the time verifier.
+//   - Should be called before Run().
+func (d *PollDevice) SetDriftTracker(tracker *DriftTracker) {
+	d.driftTracker = tracker
+}
+
 // Run fetches telemetry and registration data and pass them to the underlying handlers.
+//
+// Remarks:
+//   - Wrapped status.StatusError is returned on failure.
 func (d *PollDevice) Run() error {
+	start := time.Now()
+	err := d.run()
+	duration := time.Since(start)
+
+	pollDuration.WithLabelValues(metricsDeviceID(d.deviceID)).Observe(duration.Seconds())
+	pollTotal.WithLabelValues(metricsDeviceID(d.deviceID), sysmetrics.ResultLabel(err)).Inc()
+
+	if err == nil {
+		d.logger.Debug("device polled", "duration", duration)
+	}
+
+	return err
+}
+
+func (d *PollDevice) run() error {
+	if d.registrationFetcher == nil {
+		return d.runTelemetry()
+	}
+
 	registrationData, err := d.fetchRegistration()
 	if err != nil {
-		syscore.LogErr.Printf("fetch registration failed: %v", err)
-
-		return status.StatusError
+		return fmt.Errorf("%w: fetch registration failed: %v", status.StatusError, err)
 	}
 
 	telemetryData, err := d.fetchTelemetry()
 	if err != nil {
-		syscore.LogErr.Printf("fetch telemetry failed: %v", err)
-
-		return status.StatusError
+		return fmt.Errorf("%w: fetch telemetry failed: %v", status.StatusError, err)
 	}
 
 	if err := d.dataHandler.HandleRegistration(d.deviceID, registrationData); err != nil {
-		syscore.LogErr.Printf("handle registration failed: %v", err)
-
-		return status.StatusError
+		return fmt.Errorf("%w: handle registration failed: %v", status.StatusError, err)
 	}
 
 	if err := d.dataHandler.HandleTelemetry(d.deviceID, telemetryData); err != nil {
-		syscore.LogErr.Printf("handle telemetry failed: %v", err)
+		return fmt.Errorf("%w: handle telemetry failed: %v", status.StatusError, err)
+	}
 
-		return status.StatusError
+	return nil
+}
+
+func (d *PollDevice) runTelemetry() error {
+	telemetryData, err := d.fetchTelemetry()
+	if err != nil {
+		return fmt.Errorf("%w: fetch telemetry failed: %v", status.StatusError, err)
+	}
+
+	if err := d.parseDeviceID(telemetryData); err != nil {
+		return fmt.Errorf("%w: fetch telemetry failed: %v", status.StatusError, err)
+	}
+
+	if err := d.dataHandler.HandleTelemetry(d.deviceID, telemetryData); err != nil {
+		return fmt.Errorf("%w: handle telemetry failed: %v", status.StatusError, err)
 	}
 
 	return nil
@@ -81,6 +149,8 @@ func (d *PollDevice) fetchRegistration() (JSON, error) {
 	var js JSON
 	err = json.Unmarshal(buf, &js)
 	if err != nil {
+		d.reportValidationFailure(validationReasonMalformed)
+
 		return nil, err
 	}
 
@@ -106,6 +176,8 @@ func (d *PollDevice) fetchTelemetry() (JSON, error) {
 
 	err = json.Unmarshal(buf, &js)
 	if err != nil {
+		d.reportValidationFailure(validationReasonMalformed)
+
 		return nil, err
 	}
 
@@ -117,20 +189,37 @@ func (d *PollDevice) fetchTelemetry() (JSON, error) {
 }
 
 func (d *PollDevice) validateTimestamp(js JSON) error {
-	ts, ok := js["timestamp"]
-	if !ok {
-		return fmt.Errorf("poll-device: failed to fetch data: missing timestamp field")
+	timestamp, err := parseTimestamp(js)
+	if err != nil {
+		d.reportValidationFailure(validationReasonMalformed)
+
+		return fmt.Errorf("poll-device: failed to fetch data: %w", err)
 	}
 
-	timestamp, ok := ts.(float64)
-	if !ok {
-		return fmt.Errorf("poll-device: failed to fetch data: invalid type for timestamp")
+	if d.driftTracker != nil {
+		d.driftTracker.TrackTime(timestamp)
 	}
 
-	if !d.timeVerifier.VerifyTime(int64(timestamp)) {
-		syscore.LogInf.Printf("start syncing time for device: ID=%v", d.deviceID)
+	if !d.timeVerifier.VerifyTime(timestamp) {
+		d.reportValidationFailure(validationReasonInvalidTimestamp)
 
-		if err := d.timeSynchronizer.SyncTime(); err != nil {
+		// The timestamp is corrected before the synchronization, since the device
+		// UNIX time is changed after the synchronization.
+		corrected := correctDeviceTimestamp(
+			d.timeCorrector, d.logger, d.deviceID, js, timestamp)
+
+		d.logger.Info("invalid device timestamp, start syncing time",
+			"timestamp", timestamp)
+
+		err := d.timeSynchronizer.SyncTime()
+		timeSyncTotal.WithLabelValues(metricsDeviceID(d.deviceID),
+			sysmetrics.ResultLabel(err)).Inc()
+
+		if corrected {
+			return nil
+		}
+
+		if err != nil {
 			return fmt.Errorf("failed to sync device time: %v", err)
 		}
 
@@ -141,19 +230,16 @@ func (d *PollDevice) validateTimestamp(js JSON) error {
 }
 
 func (d *PollDevice) parseDeviceID(js JSON) error {
-	id, ok := js["device_id"]
-	if !ok {
-		return fmt.Errorf(
-			"poll-device: failed to fetch registration: missing device_id field")
-	}
+	deviceID, err := parseDeviceID(js)
+	if err != nil {
+		d.reportValidationFailure(validationReasonMalformed)
 
-	deviceID, ok := id.(string)
-	if !ok {
-		return fmt.Errorf(
-			"poll-device: failed to fetch registration: invalid type for device_id")
+		return fmt.Errorf("poll-device: failed to fetch registration: %w", err)
 	}
 
 	if d.deviceID != "" && d.deviceID != deviceID {
+		d.reportValidationFailure(validationReasonDeviceIDMismatch)
+
 		return fmt.Errorf(
 			"poll-device: failed to fetch registration: device ID mismatch: want=%s got=%s",
 			d.deviceID, deviceID,
@@ -161,10 +247,14 @@ func (d *PollDevice) parseDeviceID(js JSON) error {
 	}
 
 	if d.deviceID == "" {
-		syscore.LogInf.Printf("device ID received: %s", deviceID)
+		d.logger.Info("device ID received", "device_id", deviceID)
 
 		d.deviceID = deviceID
 	}
 
 	return nil
 }
+
+func (d *PollDevice) reportValidationFailure(reason string) {
+	validationFailuresTotal.WithLabelValues(metricsDeviceID(d.deviceID), reason).Inc()
+}
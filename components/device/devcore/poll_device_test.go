@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-control-systems/device-hub/components/status"
@@ -29,14 +30,16 @@ func (f *testFetcher[T]) Fetch() ([]byte, error) {
 }
 
 type testRegistrationData struct {
-	DeviceID  string  `json:"device_id"`
-	Timestamp float64 `json:"timestamp"`
+	DeviceID        string  `json:"device_id"`
+	Timestamp       float64 `json:"timestamp"`
+	TimestampSource string  `json:"timestamp_source,omitempty"`
 }
 
 type testTelemetryData struct {
-	Timestamp   float64 `json:"timestamp"`
-	Temperature float64 `json:"temperature"`
-	Status      string  `json:"status"`
+	Timestamp       float64 `json:"timestamp"`
+	Temperature     float64 `json:"temperature"`
+	Status          string  `json:"status"`
+	TimestampSource string  `json:"timestamp_source,omitempty"`
 }
 
 type testDataHandler struct {
@@ -94,6 +97,22 @@ func (s *testTimeSynchronizer) SyncTime() error {
 	return nil
 }
 
+type testTimeCorrector struct {
+	timestamp int64
+	err       error
+	callCount int
+}
+
+func (c *testTimeCorrector) CorrectTime(_ int64) (int64, string, error) {
+	c.callCount++
+
+	if c.err != nil {
+		return -1, "", c.err
+	}
+
+	return c.timestamp, TimestampSourceReceive, nil
+}
+
 func TestPollDeviceRun(t *testing.T) {
 	deviceID := "0xABCD"
 	testTimestamp := 13
@@ -137,6 +156,39 @@ func TestPollDeviceRun(t *testing.T) {
 	require.Equal(t, telemetryData, dataHandler.telemetry)
 }
 
+func TestPollDeviceRunNoRegistration(t *testing.T) {
+	deviceID := "0xABCD"
+
+	telemetryFetcher := testFetcher[testRegistrationData]{
+		data: testRegistrationData{
+			DeviceID:  deviceID,
+			Timestamp: float64(13),
+		},
+	}
+
+	dataHandler := &testIDHolderDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+
+	device := NewPollDevice(
+		nil,
+		&telemetryFetcher,
+		dataHandler,
+		&timeSynchronizer,
+		&BasicTimeVerifier{},
+	)
+
+	require.Nil(t, device.Run())
+	require.Equal(t, deviceID, dataHandler.deviceID)
+	require.Equal(t, deviceID, dataHandler.telemetry["device_id"])
+	require.Nil(t, dataHandler.registration)
+
+	telemetryFetcher.data.DeviceID = "0xBCDE"
+
+	err := device.Run()
+	require.NotNil(t, err)
+	require.True(t, errors.Is(err, status.StatusError))
+}
+
 func TestPollDeviceRunFetchRegistrationError(t *testing.T) {
 	deviceID := "0xABCD"
 	testTimestamp := 13
@@ -667,3 +719,131 @@ func TestPollDeviceSynchronizeTimeError(t *testing.T) {
 	require.Equal(t, float64(0), dataHandler.registration.Timestamp)
 	require.Equal(t, float64(0), dataHandler.telemetry.Timestamp)
 }
+
+func TestPollDeviceMetrics(t *testing.T) {
+	deviceID := "0xMETRICS"
+
+	registrationFetcher := testFetcher[testRegistrationData]{
+		data: testRegistrationData{
+			DeviceID:  deviceID,
+			Timestamp: -1,
+		},
+	}
+
+	telemetryFetcher := testFetcher[testTelemetryData]{
+		data: testTelemetryData{
+			Timestamp: 13,
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+
+	device := NewPollDevice(
+		&registrationFetcher,
+		&telemetryFetcher,
+		&dataHandler,
+		&timeSynchronizer,
+		&BasicTimeVerifier{},
+	)
+
+	require.NotNil(t, device.Run())
+
+	registrationFetcher.data.Timestamp = 13
+	require.Nil(t, device.Run())
+
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(pollTotal.WithLabelValues(deviceID, "failure")))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(pollTotal.WithLabelValues(deviceID, "success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(
+		validationFailuresTotal.WithLabelValues(deviceID, validationReasonInvalidTimestamp)))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(timeSyncTotal.WithLabelValues(deviceID, "success")))
+}
+
+func TestPollDeviceCorrectTimestamp(t *testing.T) {
+	deviceID := "0xCORRECT"
+
+	registrationFetcher := testFetcher[testRegistrationData]{
+		data: testRegistrationData{
+			DeviceID:  deviceID,
+			Timestamp: -1,
+		},
+	}
+
+	telemetryFetcher := testFetcher[testTelemetryData]{
+		data: testTelemetryData{
+			Timestamp:   -1,
+			Temperature: 42.135,
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{err: status.StatusTimeout}
+	timeCorrector := testTimeCorrector{timestamp: 100}
+
+	device := NewPollDevice(
+		&registrationFetcher,
+		&telemetryFetcher,
+		&dataHandler,
+		&timeSynchronizer,
+		&BasicTimeVerifier{},
+	)
+	device.SetTimeCorrector(&timeCorrector)
+
+	require.Nil(t, device.Run())
+	require.Equal(t, 2, timeCorrector.callCount)
+	require.Equal(t, 2, timeSynchronizer.callCount)
+
+	require.Equal(t, testRegistrationData{
+		DeviceID:        deviceID,
+		Timestamp:       100,
+		TimestampSource: TimestampSourceReceive,
+	}, dataHandler.registration)
+
+	require.Equal(t, testTelemetryData{
+		Timestamp:       100,
+		Temperature:     42.135,
+		TimestampSource: TimestampSourceReceive,
+	}, dataHandler.telemetry)
+
+	require.Equal(t, float64(2), testutil.ToFloat64(
+		timestampCorrectionsTotal.WithLabelValues(deviceID, TimestampSourceReceive)))
+
+	// Valid timestamp isn't corrected.
+	registrationFetcher.data.Timestamp = 13
+	telemetryFetcher.data.Timestamp = 13
+	dataHandler = testDataHandler{}
+
+	require.Nil(t, device.Run())
+	require.Equal(t, 2, timeCorrector.callCount)
+	require.Equal(t, float64(13), dataHandler.telemetry.Timestamp)
+	require.Equal(t, "", dataHandler.telemetry.TimestampSource)
+}
+
+func TestPollDeviceCorrectTimestampFailed(t *testing.T) {
+	telemetryFetcher := testFetcher[testTelemetryData]{
+		data: testTelemetryData{
+			Timestamp: -1,
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	timeCorrector := testTimeCorrector{err: status.StatusInvalidState}
+
+	device := NewPollDevice(
+		nil,
+		&telemetryFetcher,
+		&dataHandler,
+		&timeSynchronizer,
+		&BasicTimeVerifier{},
+	)
+	device.SetTimeCorrector(&timeCorrector)
+
+	require.NotNil(t, device.Run())
+	require.Equal(t, 1, timeCorrector.callCount)
+	require.Equal(t, 1, timeSynchronizer.callCount)
+	require.Equal(t, float64(0), dataHandler.telemetry.Timestamp)
+}
@@ -0,0 +1,192 @@
+package devcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// PushDevice handles telemetry and registration data sent by the device itself.
+//
+// Remarks:
+//   - Data with invalid timestamp is rejected, the UNIX time synchronization is started
+//     for the device in that case. The timestamp is corrected instead of rejecting the
+//     data if the time corrector is set.
+type PushDevice struct {
+	dataHandler      DataHandler
+	timeSynchronizer TimeSynchronizer
+	timeVerifier     TimeVerifier
+	timeCorrector    TimeCorrector
+	driftTracker     *DriftTracker
+	logger           *slog.Logger
+
+	mu       sync.Mutex
+	deviceID string
+}
+
+// NewPushDevice initializes push device.
+//
+// Parameters:
+//   - dataHandler to handle received telemetry and registration data.
+//   - timeSynchronizer to synchronize the UNIX time for a device.
+//   - timeVerifier to verify the UNIX time of the received data.
+func NewPushDevice(
+	dataHandler DataHandler,
+	timeSynchronizer TimeSynchronizer,
+	timeVerifier TimeVerifier,
+) *PushDevice {
+	return &PushDevice{
+		dataHandler:      dataHandler,
+		timeSynchronizer: timeSynchronizer,
+		timeVerifier:     timeVerifier,
+		logger:           syscore.NewLogger("push-device"),
+	}
+}
+
+// SetLogger sets the logger for the device events.
+//
+// Remarks:
+//   - Should be called before the data is handled.
+func (d *PushDevice) SetLogger(logger *slog.Logger) {
+	d.logger = logger
+}
+
+// SetTimeCorrector sets the corrector for the data with invalid timestamp.
+//
+// Remarks:
+//   - Data with invalid timestamp is rejected if the corrector isn't set.
+//   - Should be called before the data is handled.
+func (d *PushDevice) SetTimeCorrector(corrector TimeCorrector) {
+	d.timeCorrector = corrector
+}
+
+// SetDriftTracker sets the tracker to measure the device clock drift on each data.
+//
+// Remarks:
+//   - Drift is measured regardless of the time verifier.
+//   - Should be called before the data is handled.
+func (d *PushDevice) SetDriftTracker(tracker *DriftTracker) {
+	d.driftTracker = tracker
+}
+
+// SetDeviceID sets the device ID known from the previous registration.
+//
+// Remarks:
+//   - Allows to handle telemetry without waiting for the registration data again.
+//   - Empty ID is ignored.
+func (d *PushDevice) SetDeviceID(deviceID string) {
+	if deviceID == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deviceID = deviceID
+}
+
+// HandleRegistration validates the registration data and passes it to the underlying handler.
+//
+// Remarks:
+//   - status.StatusInvalidArg is returned if the data is malformed.
+func (d *PushDevice) HandleRegistration(buf []byte) error {
+	js, err := d.parseData(buf)
+	if err != nil {
+		return err
+	}
+
+	deviceID, err := parseDeviceID(js)
+	if err != nil {
+		return fmt.Errorf("%w: push-device: failed to handle registration: %v",
+			status.StatusInvalidArg, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.deviceID != "" && d.deviceID != deviceID {
+		return fmt.Errorf(
+			"%w: push-device: failed to handle registration: device ID mismatch:"+
+				" want=%s got=%s", status.StatusInvalidArg, d.deviceID, deviceID)
+	}
+
+	if d.deviceID == "" {
+		d.logger.Info("device ID received", "device_id", deviceID)
+
+		d.deviceID = deviceID
+	}
+
+	return d.dataHandler.HandleRegistration(deviceID, js)
+}
+
+// HandleTelemetry validates the telemetry data and passes it to the underlying handler.
+//
+// Remarks:
+//   - status.StatusInvalidArg is returned if the data is malformed.
+//   - status.StatusInvalidState is returned if the registration data wasn't received yet.
+func (d *PushDevice) HandleTelemetry(buf []byte) error {
+	js, err := d.parseData(buf)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	deviceID := d.deviceID
+	d.mu.Unlock()
+
+	if deviceID == "" {
+		return fmt.Errorf("%w: push-device: failed to handle telemetry: unknown device ID",
+			status.StatusInvalidState)
+	}
+
+	return d.dataHandler.HandleTelemetry(deviceID, js)
+}
+
+func (d *PushDevice) parseData(buf []byte) (JSON, error) {
+	var js JSON
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return nil, fmt.Errorf("%w: push-device: failed to parse data: %v",
+			status.StatusInvalidArg, err)
+	}
+
+	timestamp, err := parseTimestamp(js)
+	if err != nil {
+		return nil, fmt.Errorf("%w: push-device: failed to parse data: %v",
+			status.StatusInvalidArg, err)
+	}
+
+	if d.driftTracker != nil {
+		d.driftTracker.TrackTime(timestamp)
+	}
+
+	if !d.timeVerifier.VerifyTime(timestamp) {
+		// The timestamp is corrected before the synchronization, since the device
+		// UNIX time is changed after the synchronization.
+		d.mu.Lock()
+		deviceID := d.deviceID
+		d.mu.Unlock()
+
+		corrected := correctDeviceTimestamp(
+			d.timeCorrector, d.logger, deviceID, js, timestamp)
+
+		err := d.timeSynchronizer.SyncTime()
+
+		if corrected {
+			return js, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: push-device: invalid timestamp: value=%v"+
+				" sync_err=%v", status.StatusInvalidArg, timestamp, err)
+		}
+
+		return nil, fmt.Errorf("%w: push-device: invalid timestamp: value=%v",
+			status.StatusInvalidArg, timestamp)
+	}
+
+	return js, nil
+}
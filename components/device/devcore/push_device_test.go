@@ -0,0 +1,159 @@
+package devcore
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestPushDeviceHandle(t *testing.T) {
+	deviceID := "0xABCD"
+	testTimestamp := 13
+
+	registrationData := testRegistrationData{
+		DeviceID:  deviceID,
+		Timestamp: float64(testTimestamp),
+	}
+
+	telemetryData := testTelemetryData{
+		Timestamp:   float64(testTimestamp),
+		Temperature: 42.135,
+		Status:      "foo",
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	registrationBuf, err := json.Marshal(registrationData)
+	require.Nil(t, err)
+
+	telemetryBuf, err := json.Marshal(telemetryData)
+	require.Nil(t, err)
+
+	require.Nil(t, device.HandleRegistration(registrationBuf))
+	require.Equal(t, registrationData, dataHandler.registration)
+
+	require.Nil(t, device.HandleTelemetry(telemetryBuf))
+	require.Equal(t, telemetryData, dataHandler.telemetry)
+}
+
+func TestPushDeviceHandleTelemetryNoRegistration(t *testing.T) {
+	telemetryData := testTelemetryData{
+		Timestamp:   13,
+		Temperature: 42.135,
+		Status:      "foo",
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	buf, err := json.Marshal(telemetryData)
+	require.Nil(t, err)
+
+	err = device.HandleTelemetry(buf)
+	require.True(t, errors.Is(err, status.StatusInvalidState))
+	require.Empty(t, dataHandler.telemetry.Status)
+}
+
+func TestPushDeviceSetDeviceID(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	device.SetDeviceID("0xABCD")
+
+	require.Nil(t, device.HandleTelemetry([]byte(`{"timestamp":13,"status":"foo"}`)))
+	require.Equal(t, "foo", dataHandler.telemetry.Status)
+
+	err := device.HandleRegistration([]byte(`{"timestamp":13,"device_id":"0xCBDE"}`))
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+}
+
+func TestPushDeviceHandleInvalidData(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	for _, buf := range []string{
+		``,
+		`foo`,
+		`{}`,
+		`{"timestamp":"13","device_id":"0xABCD"}`,
+		`{"timestamp":-1,"device_id":"0xABCD"}`,
+		`{"timestamp":13}`,
+		`{"timestamp":13,"device_id":13}`,
+	} {
+		err := device.HandleRegistration([]byte(buf))
+		require.True(t, errors.Is(err, status.StatusInvalidArg), buf)
+	}
+
+	require.Empty(t, dataHandler.registration.DeviceID)
+}
+
+func TestPushDeviceHandleRegistrationDeviceIDChanged(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	require.Nil(t, device.HandleRegistration([]byte(`{"timestamp":13,"device_id":"0xABCD"}`)))
+	require.Equal(t, "0xABCD", dataHandler.registration.DeviceID)
+
+	err := device.HandleRegistration([]byte(`{"timestamp":13,"device_id":"0xCBDE"}`))
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+	require.Equal(t, "0xABCD", dataHandler.registration.DeviceID)
+}
+
+func TestPushDeviceHandleDataHandlerFailed(t *testing.T) {
+	dataHandler := testDataHandler{
+		err: errors.New("failed to handle"),
+	}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	err := device.HandleRegistration([]byte(`{"timestamp":13,"device_id":"0xABCD"}`))
+	require.Equal(t, dataHandler.err, err)
+}
+
+func TestPushDeviceSynchronizeTime(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	err := device.HandleRegistration([]byte(`{"timestamp":-1,"device_id":"0xABCD"}`))
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+	require.Equal(t, 1, timeSynchronizer.callCount)
+
+	require.Nil(t, device.HandleRegistration([]byte(`{"timestamp":13,"device_id":"0xABCD"}`)))
+	require.Equal(t, 1, timeSynchronizer.callCount)
+	require.Equal(t, "0xABCD", dataHandler.registration.DeviceID)
+}
+
+func TestPushDeviceCorrectTimestamp(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{err: status.StatusNotSupported}
+	timeCorrector := testTimeCorrector{timestamp: 100}
+
+	device := NewPushDevice(&dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+	device.SetTimeCorrector(&timeCorrector)
+
+	require.Nil(t, device.HandleRegistration([]byte(`{"timestamp":-1,"device_id":"0xABCD"}`)))
+	require.Equal(t, 1, timeSynchronizer.callCount)
+	require.Equal(t, testRegistrationData{
+		DeviceID:        "0xABCD",
+		Timestamp:       100,
+		TimestampSource: TimestampSourceReceive,
+	}, dataHandler.registration)
+
+	require.Nil(t, device.HandleTelemetry([]byte(`{"timestamp":13,"status":"foo"}`)))
+	require.Equal(t, 1, timeCorrector.callCount)
+	require.Equal(t, testTelemetryData{
+		Timestamp: 13,
+		Status:    "foo",
+	}, dataHandler.telemetry)
+}
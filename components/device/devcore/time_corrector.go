@@ -0,0 +1,112 @@
+package devcore
+
+import (
+	"fmt"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+const (
+	// TimestampSourceKey is the data field, which describes how the timestamp of the
+	// corrected data was estimated.
+	TimestampSourceKey = "timestamp_source"
+
+	// TimestampSourceReceive means that the timestamp is the local UNIX time when
+	// the data was received.
+	TimestampSourceReceive = "receive"
+
+	// TimestampSourceOffset means that the device timestamp is adjusted by the offset
+	// between local and device UNIX time.
+	TimestampSourceOffset = "offset"
+)
+
+// TimeCorrector corrects the invalid UNIX timestamp of the device data.
+type TimeCorrector interface {
+	// CorrectTime returns the corrected UNIX timestamp and how it was estimated,
+	// one of the TimestampSource values.
+	CorrectTime(timestamp int64) (int64, string, error)
+}
+
+// ReceiveTimeCorrector replaces the device timestamp with the local UNIX time.
+type ReceiveTimeCorrector struct {
+	clock syscore.SystemClock
+}
+
+// NewReceiveTimeCorrector is an initialization of ReceiveTimeCorrector.
+//
+// Parameters:
+//   - clock to get the local UNIX time.
+func NewReceiveTimeCorrector(clock syscore.SystemClock) *ReceiveTimeCorrector {
+	return &ReceiveTimeCorrector{
+		clock: clock,
+	}
+}
+
+// CorrectTime returns the current local UNIX time.
+func (c *ReceiveTimeCorrector) CorrectTime(_ int64) (int64, string, error) {
+	timestamp, err := getValidTimestamp(c.clock)
+	if err != nil {
+		return -1, "", fmt.Errorf("failed to get local time: %w", err)
+	}
+
+	return timestamp, TimestampSourceReceive, nil
+}
+
+// OffsetTimeCorrector adjusts the device timestamp by the offset between local and
+// device UNIX time.
+//
+// Remarks:
+//   - The offset is estimated each time the timestamp is corrected, the device
+//     UNIX time should be read before the device time is synchronized.
+//   - The local UNIX time is used if the offset can't be estimated, e.g. the device
+//     isn't reachable or its UNIX time is unknown.
+type OffsetTimeCorrector struct {
+	local  syscore.SystemClock
+	remote syscore.SystemClock
+}
+
+// NewOffsetTimeCorrector is an initialization of OffsetTimeCorrector.
+//
+// Parameters:
+//   - local to get the local UNIX time.
+//   - remote to get the current device UNIX time.
+func NewOffsetTimeCorrector(local, remote syscore.SystemClock) *OffsetTimeCorrector {
+	return &OffsetTimeCorrector{
+		local:  local,
+		remote: remote,
+	}
+}
+
+// CorrectTime adds the offset between local and device UNIX time to the timestamp.
+func (c *OffsetTimeCorrector) CorrectTime(timestamp int64) (int64, string, error) {
+	localTs, err := getValidTimestamp(c.local)
+	if err != nil {
+		return -1, "", fmt.Errorf("failed to get local time: %w", err)
+	}
+
+	if timestamp < 0 {
+		return localTs, TimestampSourceReceive, nil
+	}
+
+	remoteTs, err := getValidTimestamp(c.remote)
+	if err != nil {
+		return localTs, TimestampSourceReceive, nil
+	}
+
+	return timestamp + localTs - remoteTs, TimestampSourceOffset, nil
+}
+
+func getValidTimestamp(clock syscore.SystemClock) (int64, error) {
+	timestamp, err := clock.GetTimestamp()
+	if err != nil {
+		return -1, err
+	}
+
+	if timestamp < 0 {
+		return -1, fmt.Errorf("%w: invalid timestamp: %v", status.StatusInvalidState,
+			timestamp)
+	}
+
+	return timestamp, nil
+}
@@ -0,0 +1,59 @@
+package devcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestReceiveTimeCorrector(t *testing.T) {
+	clock := &testDriftTimeVerifierTestClock{timestamp: 200}
+	corrector := NewReceiveTimeCorrector(clock)
+
+	timestamp, source, err := corrector.CorrectTime(-1)
+	require.Nil(t, err)
+	require.Equal(t, int64(200), timestamp)
+	require.Equal(t, TimestampSourceReceive, source)
+
+	clock.timestamp = -1
+
+	_, _, err = corrector.CorrectTime(-1)
+	require.ErrorIs(t, err, status.StatusInvalidState)
+
+	clock.err = status.StatusTimeout
+
+	_, _, err = corrector.CorrectTime(-1)
+	require.ErrorIs(t, err, status.StatusTimeout)
+}
+
+func TestOffsetTimeCorrector(t *testing.T) {
+	local := &testDriftTimeVerifierTestClock{timestamp: 1000}
+	remote := &testDriftTimeVerifierTestClock{timestamp: 50}
+	corrector := NewOffsetTimeCorrector(local, remote)
+
+	timestamp, source, err := corrector.CorrectTime(40)
+	require.Nil(t, err)
+	require.Equal(t, int64(990), timestamp)
+	require.Equal(t, TimestampSourceOffset, source)
+
+	// Device timestamp is unknown.
+	timestamp, source, err = corrector.CorrectTime(-1)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), timestamp)
+	require.Equal(t, TimestampSourceReceive, source)
+
+	// Device UNIX time can't be requested.
+	remote.err = status.StatusTimeout
+
+	timestamp, source, err = corrector.CorrectTime(40)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), timestamp)
+	require.Equal(t, TimestampSourceReceive, source)
+
+	local.err = status.StatusTimeout
+
+	_, _, err = corrector.CorrectTime(40)
+	require.ErrorIs(t, err, status.StatusTimeout)
+}
@@ -0,0 +1,219 @@
+package devcore
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+const (
+	timeSyncResultSuccess = "success"
+	timeSyncResultFailure = "failure"
+
+	// Refusal reasons, the synchronization is refused if the local UNIX time can't
+	// be trusted or the device UNIX time is ahead of the local UNIX time.
+	timeSyncReasonRemoteAhead     = "remote_ahead_of_local"
+	timeSyncReasonLastRemoteAhead = "last_remote_ahead"
+)
+
+// TimeStats contains the device clock drift and UNIX time synchronization statistics.
+//
+// Remarks:
+//   - Time is formatted according to RFC1123, empty if the event hasn't happened yet.
+type TimeStats struct {
+	// Drift - difference between local and device UNIX time.
+	Drift DriftStats `json:"drift"`
+
+	// Sync - results of the device UNIX time synchronization.
+	Sync TimeSyncStats `json:"sync"`
+}
+
+// DriftStats contains the device clock drift, local minus device UNIX time, in seconds.
+//
+// Remarks:
+//   - Negative drift means the device clock is ahead of the local clock.
+type DriftStats struct {
+	// Current - the most recent drift.
+	Current int64 `json:"current"`
+
+	// Min - the minimum drift.
+	Min int64 `json:"min"`
+
+	// Max - the maximum drift.
+	Max int64 `json:"max"`
+
+	// Avg - the average drift.
+	Avg float64 `json:"avg"`
+
+	// Samples - number of drift samples.
+	Samples uint64 `json:"samples"`
+
+	// LastSampleAt - when the drift was measured last time.
+	LastSampleAt string `json:"last_sample_at"`
+}
+
+// TimeSyncStats contains the results of the device UNIX time synchronization.
+type TimeSyncStats struct {
+	// Attempts - number of synchronization attempts.
+	Attempts uint64 `json:"attempts"`
+
+	// Successes - number of successful synchronizations.
+	Successes uint64 `json:"successes"`
+
+	// Refusals - number of refused synchronizations by reason:
+	//   - remote_ahead_of_local - the device UNIX time is ahead of the local UNIX time.
+	//   - last_remote_ahead - the last known device UNIX time is ahead of the local
+	//     UNIX time, the local UNIX time is likely invalid.
+	Refusals map[string]uint64 `json:"refusals"`
+
+	// Failures - number of synchronizations failed due to other errors,
+	// e.g. the device isn't reachable.
+	Failures uint64 `json:"failures"`
+
+	// LastAttemptAt - when the synchronization was attempted last time.
+	LastAttemptAt string `json:"last_attempt_at"`
+
+	// LastResult - "success", "failure" or the refusal reason of the last attempt.
+	LastResult string `json:"last_result"`
+
+	// LastSuccessAt - when the device UNIX time was synchronized last time.
+	LastSuccessAt string `json:"last_success_at"`
+}
+
+// TimeStatsTracker collects the device clock drift and UNIX time synchronization
+// statistics and exposes them as metrics.
+//
+// Remarks:
+//   - Can be used by multiple goroutines.
+type TimeStatsTracker struct {
+	holder *IDHolder
+
+	mu       sync.Mutex
+	stats    TimeStats
+	driftSum float64
+}
+
+// NewTimeStatsTracker is an initialization of TimeStatsTracker.
+//
+// Parameters:
+//   - holder to get the device ID for the metrics.
+func NewTimeStatsTracker(holder *IDHolder) *TimeStatsTracker {
+	return &TimeStatsTracker{
+		holder: holder,
+	}
+}
+
+// Get returns the collected statistics.
+func (t *TimeStatsTracker) Get() TimeStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.stats
+
+	if t.stats.Sync.Refusals != nil {
+		stats.Sync.Refusals = make(map[string]uint64, len(t.stats.Sync.Refusals))
+		for reason, count := range t.stats.Sync.Refusals {
+			stats.Sync.Refusals[reason] = count
+		}
+	}
+
+	return stats
+}
+
+// Set replaces the collected statistics, e.g. when the device is recreated.
+//
+// Remarks:
+//   - Metrics aren't changed.
+func (t *TimeStatsTracker) Set(stats TimeStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats = stats
+	t.driftSum = stats.Drift.Avg * float64(stats.Drift.Samples)
+
+	if stats.Sync.Refusals != nil {
+		t.stats.Sync.Refusals = make(map[string]uint64, len(stats.Sync.Refusals))
+		for reason, count := range stats.Sync.Refusals {
+			t.stats.Sync.Refusals[reason] = count
+		}
+	}
+}
+
+// HandleDrift records the difference between local and device UNIX time, in seconds.
+func (t *TimeStatsTracker) HandleDrift(drift int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d := &t.stats.Drift
+
+	if d.Samples == 0 || drift < d.Min {
+		d.Min = drift
+	}
+	if d.Samples == 0 || drift > d.Max {
+		d.Max = drift
+	}
+
+	d.Samples++
+	d.Current = drift
+	d.LastSampleAt = time.Now().Format(time.RFC1123)
+
+	t.driftSum += float64(drift)
+	d.Avg = t.driftSum / float64(d.Samples)
+
+	deviceID := metricsDeviceID(t.holder.Get())
+
+	clockDrift.WithLabelValues(deviceID).Set(float64(d.Current))
+	clockDriftMin.WithLabelValues(deviceID).Set(float64(d.Min))
+	clockDriftMax.WithLabelValues(deviceID).Set(float64(d.Max))
+	clockDriftAvg.WithLabelValues(deviceID).Set(d.Avg)
+}
+
+// HandleSync records the result of the device UNIX time synchronization.
+func (t *TimeStatsTracker) HandleSync(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &t.stats.Sync
+	now := time.Now()
+
+	s.Attempts++
+	s.LastAttemptAt = now.Format(time.RFC1123)
+
+	deviceID := metricsDeviceID(t.holder.Get())
+
+	switch reason := timeSyncRefusalReason(err); {
+	case err == nil:
+		s.Successes++
+		s.LastResult = timeSyncResultSuccess
+		s.LastSuccessAt = s.LastAttemptAt
+
+		timeSyncLastSuccess.WithLabelValues(deviceID).Set(float64(now.Unix()))
+
+	case reason != "":
+		if s.Refusals == nil {
+			s.Refusals = make(map[string]uint64)
+		}
+
+		s.Refusals[reason]++
+		s.LastResult = reason
+
+		timeSyncRefusalsTotal.WithLabelValues(deviceID, reason).Inc()
+
+	default:
+		s.Failures++
+		s.LastResult = timeSyncResultFailure
+	}
+}
+
+func timeSyncRefusalReason(err error) string {
+	switch {
+	case errors.Is(err, syscore.ErrRemoteAhead):
+		return timeSyncReasonRemoteAhead
+	case errors.Is(err, syscore.ErrLastRemoteAhead):
+		return timeSyncReasonLastRemoteAhead
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,110 @@
+package devcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+func TestTimeStatsTrackerDrift(t *testing.T) {
+	deviceID := "0xTIMESTATSDRIFT"
+
+	holder := NewIDHolder(&testDataHandler{})
+	holder.Set(deviceID)
+
+	tracker := NewTimeStatsTracker(holder)
+
+	drift := tracker.Get().Drift
+	require.Equal(t, uint64(0), drift.Samples)
+	require.Equal(t, "", drift.LastSampleAt)
+
+	for _, d := range []int64{10, -2, 4} {
+		tracker.HandleDrift(d)
+	}
+
+	drift = tracker.Get().Drift
+	require.Equal(t, uint64(3), drift.Samples)
+	require.Equal(t, int64(4), drift.Current)
+	require.Equal(t, int64(-2), drift.Min)
+	require.Equal(t, int64(10), drift.Max)
+	require.Equal(t, float64(4), drift.Avg)
+	require.NotEqual(t, "", drift.LastSampleAt)
+
+	require.Equal(t, float64(4), testutil.ToFloat64(clockDrift.WithLabelValues(deviceID)))
+	require.Equal(t, float64(-2), testutil.ToFloat64(clockDriftMin.WithLabelValues(deviceID)))
+	require.Equal(t, float64(10), testutil.ToFloat64(clockDriftMax.WithLabelValues(deviceID)))
+	require.Equal(t, float64(4), testutil.ToFloat64(clockDriftAvg.WithLabelValues(deviceID)))
+}
+
+func TestTimeStatsTrackerSync(t *testing.T) {
+	deviceID := "0xTIMESTATSSYNC"
+
+	holder := NewIDHolder(&testDataHandler{})
+	holder.Set(deviceID)
+
+	tracker := NewTimeStatsTracker(holder)
+
+	tracker.HandleSync(fmt.Errorf("sync failed: %w", syscore.ErrRemoteAhead))
+	tracker.HandleSync(syscore.ErrRemoteAhead)
+	tracker.HandleSync(syscore.ErrLastRemoteAhead)
+	tracker.HandleSync(status.StatusTimeout)
+
+	sync := tracker.Get().Sync
+	require.Equal(t, uint64(4), sync.Attempts)
+	require.Equal(t, uint64(0), sync.Successes)
+	require.Equal(t, uint64(1), sync.Failures)
+	require.Equal(t, map[string]uint64{
+		timeSyncReasonRemoteAhead:     2,
+		timeSyncReasonLastRemoteAhead: 1,
+	}, sync.Refusals)
+	require.Equal(t, timeSyncResultFailure, sync.LastResult)
+	require.NotEqual(t, "", sync.LastAttemptAt)
+	require.Equal(t, "", sync.LastSuccessAt)
+
+	tracker.HandleSync(nil)
+
+	sync = tracker.Get().Sync
+	require.Equal(t, uint64(5), sync.Attempts)
+	require.Equal(t, uint64(1), sync.Successes)
+	require.Equal(t, timeSyncResultSuccess, sync.LastResult)
+	require.Equal(t, sync.LastAttemptAt, sync.LastSuccessAt)
+
+	require.Equal(t, float64(2), testutil.ToFloat64(
+		timeSyncRefusalsTotal.WithLabelValues(deviceID, timeSyncReasonRemoteAhead)))
+	require.Equal(t, float64(1), testutil.ToFloat64(
+		timeSyncRefusalsTotal.WithLabelValues(deviceID, timeSyncReasonLastRemoteAhead)))
+	require.NotEqual(t, float64(0),
+		testutil.ToFloat64(timeSyncLastSuccess.WithLabelValues(deviceID)))
+}
+
+func TestTimeStatsTrackerSet(t *testing.T) {
+	holder := NewIDHolder(&testDataHandler{})
+
+	tracker := NewTimeStatsTracker(holder)
+	tracker.HandleDrift(2)
+	tracker.HandleDrift(4)
+	tracker.HandleSync(syscore.ErrRemoteAhead)
+
+	stats := tracker.Get()
+
+	// Returned statistics aren't changed by the tracker.
+	tracker.HandleSync(syscore.ErrRemoteAhead)
+	require.Equal(t, uint64(1), stats.Sync.Refusals[timeSyncReasonRemoteAhead])
+
+	restored := NewTimeStatsTracker(holder)
+	restored.Set(stats)
+	require.Equal(t, stats, restored.Get())
+
+	restored.HandleDrift(9)
+
+	drift := restored.Get().Drift
+	require.Equal(t, uint64(3), drift.Samples)
+	require.Equal(t, float64(5), drift.Avg)
+	require.Equal(t, int64(2), drift.Min)
+	require.Equal(t, int64(9), drift.Max)
+}
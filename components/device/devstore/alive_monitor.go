@@ -10,4 +10,13 @@ type AliveMonitor interface {
 	//	- If the device isn't marked as alive with the returned alive notifier,
 	//	  it can be considered as inactive.
 	Monitor(uri string) syssched.AliveNotifier
+
+	// IsOffline returns true if the device is inactive but is still kept in the store.
+	IsOffline(uri string) bool
+}
+
+// OfflineHandler handles the devices known to be offline.
+type OfflineHandler interface {
+	// HandleOffline marks the device associated with the provided URI as offline.
+	HandleOffline(uri string)
 }
@@ -17,8 +17,8 @@ func NewAwakeStore(a syssched.Awakener, s Store) *AwakeStore {
 }
 
 // Add adds the device and notifies the awakener.
-func (s *AwakeStore) Add(uri string, typ string, desc string) error {
-	err := s.store.Add(uri, typ, desc)
+func (s *AwakeStore) Add(uri string, typ string, desc string, params DeviceParams) error {
+	err := s.store.Add(uri, typ, desc, params)
 	if err == nil {
 		s.awakener.Awake()
 	}
@@ -31,6 +31,11 @@ func (s *AwakeStore) Remove(uri string) error {
 	return s.store.Remove(uri)
 }
 
+// Update updates the device associated with the provided URI.
+func (s *AwakeStore) Update(uri string, typ string, desc string) error {
+	return s.store.Update(uri, typ, desc)
+}
+
 // GetDesc returns descriptions for registered devices.
 func (s *AwakeStore) GetDesc() []StoreItem {
 	return s.store.GetDesc()
@@ -2,14 +2,22 @@ package devstore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/open-control-systems/device-hub/components/device/devcore"
 	"github.com/open-control-systems/device-hub/components/http/htcore"
+	"github.com/open-control-systems/device-hub/components/http/httransport"
+	"github.com/open-control-systems/device-hub/components/mqtt/mqcore"
 	"github.com/open-control-systems/device-hub/components/status"
 	"github.com/open-control-systems/device-hub/components/storage/stcore"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
@@ -17,14 +25,49 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syssched"
 )
 
+var cacheStoreLogger = syscore.NewLogger("cache-store")
+
 // CacheStoreParams represents various configuration options for a cache store.
 type CacheStoreParams struct {
+	// Profiles to lookup the HTTP device profile by the device type, nil to use
+	// the default profile for all devices.
+	Profiles *DeviceProfileRegistry
+
+	// Scheduler to run HTTP device polling on the shared pool of goroutines, nil to
+	// use a standalone goroutine for each device.
+	//
+	// Remarks:
+	//  - Scheduler lifecycle isn't managed by the store.
+	Scheduler *syssched.PoolScheduler
+
 	HTTP struct {
 		// FetchInterval - how often to fetch data from the device.
 		FetchInterval time.Duration
 
 		// FetchTimeout - how long to wait for the response from the device.
 		FetchTimeout time.Duration
+
+		// OfflineMaxFetchInterval - maximum interval to fetch data from the offline
+		// device, the interval grows exponentially from FetchInterval on each failure.
+		//
+		// Remarks:
+		//  - Backoff is disabled if the interval doesn't exceed FetchInterval.
+		OfflineMaxFetchInterval time.Duration
+
+		// ErrorMaxFetchInterval - maximum interval to fetch data from the device after
+		// consecutive failures, the interval grows exponentially from FetchInterval on
+		// each failure and is reset on success.
+		//
+		// Remarks:
+		//  - Backoff is disabled if the interval doesn't exceed FetchInterval.
+		ErrorMaxFetchInterval time.Duration
+
+		// FetchJitter - how much the fetch interval is randomized, in the [0, 1] range,
+		// to spread fetches of different devices over time.
+		//
+		// Remarks:
+		//  - The first fetch is delayed randomly within FetchInterval if set.
+		FetchJitter float64
 	}
 
 	TimeSync struct {
@@ -37,6 +80,12 @@ type CacheStoreParams struct {
 		// MaxDriftInterval is a maximum allowed time difference between local
 		// and device UNIX time.
 		MaxDriftInterval time.Duration
+
+		// Correction defines how the device data with invalid timestamp is handled.
+		//
+		// Remarks:
+		//  - Corrected data is marked with the devcore.TimestampSourceKey field.
+		Correction TimeCorrection
 	}
 }
 
@@ -49,6 +98,9 @@ type CacheStore struct {
 	resolveStore    *sysnet.ResolveStore
 	aliveMonitor    AliveMonitor
 	params          CacheStoreParams
+	httpClient      *htcore.HTTPClient
+	resolveClient   *htcore.HTTPClient
+	mqttClients     *mqttClientPool
 
 	mu    sync.Mutex
 	db    stcore.DB
@@ -82,6 +134,9 @@ func NewCacheStore(
 		params:          params,
 		db:              db,
 		resolveStore:    resolveStore,
+		httpClient:      htcore.NewDefaultClient(),
+		resolveClient:   htcore.NewResolveClient(resolveStore),
+		mqttClients:     newMQTTClientPool(),
 		nodes:           make(map[string]*storeNode),
 	}
 
@@ -91,10 +146,59 @@ func NewCacheStore(
 }
 
 // SetAliveMonitor sets the device inactivity monitor.
+//
+// Remarks:
+//   - Should be called before Start().
+//   - Monitor is applied to the already restored devices as well.
 func (s *CacheStore) SetAliveMonitor(monitor AliveMonitor) {
 	s.aliveMonitor = monitor
 }
 
+// SetParams changes the store configuration.
+//
+// Remarks:
+//   - Should be called after Start().
+//   - Scheduler isn't changed.
+//   - Data processing is restarted only for the devices whose effective configuration
+//     is changed, the device registration, ID and status are preserved.
+//   - HTTP options and device profiles affect only HTTP devices.
+func (s *CacheStore) SetParams(params CacheStoreParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if params.HTTP == s.params.HTTP && params.TimeSync == s.params.TimeSync &&
+		equalDeviceProfiles(params.Profiles, s.params.Profiles) {
+		return
+	}
+
+	prevParams := s.params
+
+	params.Scheduler = s.params.Scheduler
+	s.params = params
+
+	restarted := 0
+
+	for uri, node := range s.nodes {
+		if !nodeParamsChanged(node, prevParams, params) {
+			continue
+		}
+
+		newNode, err := s.restartNode(node)
+		if err != nil {
+			node.logger.Error("failed to restart device", "err", err)
+
+			continue
+		}
+
+		s.nodes[uri] = newNode
+
+		restarted++
+	}
+
+	cacheStoreLogger.Info("store configuration updated", "devices", len(s.nodes),
+		"restarted", restarted)
+}
+
 // Start starts data processing for cached devices.
 func (s *CacheStore) Start() error {
 	s.mu.Lock()
@@ -116,7 +220,7 @@ func (s *CacheStore) Stop() error {
 
 	for _, node := range s.nodes {
 		if err := node.stop(); err != nil {
-			syscore.LogErr.Printf("failed to stop device: uri=%s err=%v", node.uri, err)
+			node.logger.Error("failed to stop device", "err", err)
 		}
 	}
 
@@ -126,7 +230,7 @@ func (s *CacheStore) Stop() error {
 }
 
 // Add caches the device information in the persistent storage.
-func (s *CacheStore) Add(uri string, typ string, desc string) error {
+func (s *CacheStore) Add(uri string, typ string, desc string, params DeviceParams) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -136,33 +240,27 @@ func (s *CacheStore) Add(uri string, typ string, desc string) error {
 
 	now := time.Now()
 
-	node, err := s.makeNode(uri, typ, desc, now)
+	node, err := s.makeNode(uri, typ, desc, params, now)
 	if err != nil {
 		return err
 	}
 
-	item := StorageItem{
-		Desc:      desc,
-		Timestamp: now.Unix(),
-		Type:      typ,
-	}
+	if err := s.persistNode(node); err != nil {
+		// Release the resources acquired by the node, e.g. the shared MQTT client.
+		if err := node.stop(); err != nil {
+			node.logger.Error("failed to stop device", "err", err)
+		}
 
-	buf, err := item.MarshalBinary()
-	if err != nil {
 		return err
 	}
 
-	if err := s.db.Write(uri, buf); err != nil {
-		return fmt.Errorf("failed to persist device information: uri=%s err=%v", uri, err)
-	}
-
 	if err := node.start(); err != nil {
 		return err
 	}
 
 	s.nodes[uri] = node
 
-	syscore.LogInf.Printf("device added: uri=%s type=%s desc=%s", uri, typ, desc)
+	node.logger.Info("device added")
 
 	return nil
 }
@@ -187,7 +285,56 @@ func (s *CacheStore) Remove(uri string) error {
 
 	delete(s.nodes, uri)
 
-	syscore.LogInf.Printf("device removed: uri=%s", uri)
+	devcore.DeleteDeviceMetrics(node.holder.Get())
+	for _, fetchURL := range node.fetchURLs {
+		htcore.DeleteFetchMetrics(fetchURL)
+	}
+
+	node.logger.Info("device removed")
+
+	return nil
+}
+
+// Update updates the type and description of the device in the persistent storage.
+//
+// Remarks:
+//   - Device data processing is restarted only for the HTTP device whose profile
+//     is changed with the type, the device registration, ID and status are preserved.
+func (s *CacheStore) Update(uri string, typ string, desc string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[uri]
+	if !ok {
+		return status.StatusNoData
+	}
+
+	prevTyp, prevDesc := node.typ, node.desc
+
+	node.typ = typ
+	node.desc = desc
+
+	if err := s.persistNode(node); err != nil {
+		node.typ = prevTyp
+		node.desc = prevDesc
+
+		return err
+	}
+
+	node.logInfo.update(typ, desc)
+
+	if node.kind == deviceTypeHTTP && !reflect.DeepEqual(s.getProfile(prevTyp),
+		s.getProfile(typ)) {
+		newNode, err := s.restartNode(node)
+		if err != nil {
+			node.logger.Error("failed to restart device", "err", err)
+		} else {
+			s.nodes[uri] = newNode
+			node = newNode
+		}
+	}
+
+	node.logger.Info("device updated")
 
 	return nil
 }
@@ -206,18 +353,77 @@ func (s *CacheStore) GetDesc() []StoreItem {
 			Desc:      node.desc,
 			ID:        node.holder.Get(),
 			CreatedAt: node.createdAt,
+			Params:    node.params,
+			Status:    node.tracker.get(),
 		})
 	}
 
 	return items
 }
 
+// GetPushHandler returns the handler for the push device associated with the provided ID.
+func (s *CacheStore) GetPushHandler(id string) (PushHandler, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[pushScheme+"://"+id]
+	if !ok || node.pushHandler == nil {
+		return nil, status.StatusNoData
+	}
+
+	return node.pushHandler, nil
+}
+
+// persistNode saves the device information in the persistent storage.
+func (s *CacheStore) persistNode(node *storeNode) error {
+	item := StorageItem{
+		Desc:             node.desc,
+		Timestamp:        node.timestamp,
+		Type:             node.typ,
+		FetchInterval:    int64(node.params.FetchInterval),
+		FetchTimeout:     int64(node.params.FetchTimeout),
+		TimeSync:         uint8(node.params.TimeSync),
+		MaxDriftInterval: int64(node.params.MaxDriftInterval),
+		DeviceID:         node.holder.Get(),
+	}
+
+	buf, err := item.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(node.uri, buf); err != nil {
+		return fmt.Errorf("failed to persist device information: uri=%s err=%v",
+			node.uri, err)
+	}
+
+	return nil
+}
+
+// persistDeviceID saves the device ID received by the node with the provided holder.
+//
+// Remarks:
+//   - Nothing is saved if the node is removed or restarted.
+func (s *CacheStore) persistDeviceID(uri string, holder *devcore.IDHolder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[uri]
+	if !ok || node.holder != holder {
+		return
+	}
+
+	if err := s.persistNode(node); err != nil {
+		node.logger.Error("failed to persist device ID", "err", err)
+	}
+}
+
 func (s *CacheStore) restoreNodes() {
 	var unrestoredURIs []string
 
 	err := s.db.ForEach(func(uri string, buf []byte) error {
 		if err := s.restoreNode(uri, buf); err != nil {
-			syscore.LogErr.Printf("failed to restore device: uri=%s err=%v", uri, err)
+			cacheStoreLogger.Error("failed to restore device", "uri", uri, "err", err)
 
 			unrestoredURIs = append(unrestoredURIs, uri)
 		}
@@ -234,10 +440,10 @@ func (s *CacheStore) restoreNodes() {
 
 	for _, uri := range unrestoredURIs {
 		if err := s.db.Remove(uri); err != nil {
-			syscore.LogErr.Printf("failed to remove unrestored device:"+
-				" uri=%s err=%v", uri, err)
+			cacheStoreLogger.Error("failed to remove unrestored device", "uri", uri,
+				"err", err)
 		} else {
-			syscore.LogErr.Printf("unrestored device removed: uri=%s", uri)
+			cacheStoreLogger.Error("unrestored device removed", "uri", uri)
 		}
 	}
 }
@@ -248,37 +454,135 @@ func (s *CacheStore) restoreNode(uri string, buf []byte) error {
 		return err
 	}
 
-	node, err := s.makeNode(uri, item.Type, item.Desc, time.Unix(item.Timestamp, 0))
+	params := DeviceParams{
+		FetchInterval:    time.Duration(item.FetchInterval),
+		FetchTimeout:     time.Duration(item.FetchTimeout),
+		TimeSync:         DeviceTimeSync(item.TimeSync),
+		MaxDriftInterval: time.Duration(item.MaxDriftInterval),
+	}
+
+	node, err := s.makeNode(uri, item.Type, item.Desc, params, time.Unix(item.Timestamp, 0))
 	if err != nil {
 		return err
 	}
 
+	node.setDeviceID(item.DeviceID)
+
 	s.nodes[uri] = node
 
-	syscore.LogInf.Printf("device restored: uri=%s type=%s desc=%s", uri, item.Type, item.Desc)
+	node.logger.Info("device restored")
 
 	return nil
 }
 
+// restartNode replaces the node with the one made with the current store configuration.
+//
+// Remarks:
+//   - The old node is kept running if the new one can't be made.
+func (s *CacheStore) restartNode(node *storeNode) (*storeNode, error) {
+	newNode, err := s.makeNode(node.uri, node.typ, node.desc, node.params, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	newNode.createdAt = node.createdAt
+	newNode.timestamp = node.timestamp
+	newNode.setDeviceID(node.holder.Get())
+	newNode.tracker.set(node.tracker.get())
+
+	if err := node.stop(); err != nil {
+		node.logger.Error("failed to stop device", "err", err)
+	}
+
+	// Profile paths could be changed.
+	for _, fetchURL := range node.fetchURLs {
+		if !slices.Contains(newNode.fetchURLs, fetchURL) {
+			htcore.DeleteFetchMetrics(fetchURL)
+		}
+	}
+
+	if err := newNode.start(); err != nil {
+		newNode.logger.Error("failed to start device", "err", err)
+	}
+
+	return newNode, nil
+}
+
 func (s *CacheStore) makeNode(
 	uri string,
 	typ string,
 	desc string,
+	params DeviceParams,
 	now time.Time,
 ) (*storeNode, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	u, err := url.Parse(uri)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", status.StatusInvalidArg, err)
 	}
 
-	deviceType := parseDeviceType(u.Scheme)
+	nodeParams := makeNodeParams(s.params, params)
+
+	holder := devcore.NewIDHolder(s.dataHandler)
+	logInfo := &deviceLogInfo{uri: uri, holder: holder, typ: typ, desc: desc}
+	logger := newDeviceLogger(logInfo)
+
+	var node *storeNode
 
-	switch deviceType {
+	switch parseDeviceType(u.Scheme) {
 	case deviceTypeHTTP:
-		return s.makeNodeHTTP(u, uri, typ, desc, now)
+		node, err = s.makeNodeHTTP(u, uri, typ, desc, nodeParams, holder, logger)
+	case deviceTypePush:
+		node, err = s.makeNodePush(u, uri, nodeParams, holder, logger)
+	case deviceTypeMQTT:
+		node, err = s.makeNodeMQTT(u, uri, desc, nodeParams, holder, logger)
 	default:
 		return nil, status.StatusNotSupported
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	node.uri = uri
+	node.kind = parseDeviceType(u.Scheme)
+	node.typ = typ
+	node.desc = desc
+	node.createdAt = now.Format(time.RFC1123)
+	node.timestamp = now.Unix()
+	node.params = params
+	node.holder = holder
+	node.logInfo = logInfo
+	node.logger = logger
+
+	return node, nil
+}
+
+// makeNodeParams applies the per-device options on top of the store configuration.
+func makeNodeParams(storeParams CacheStoreParams, params DeviceParams) CacheStoreParams {
+	ret := storeParams
+
+	if params.FetchInterval != 0 {
+		ret.HTTP.FetchInterval = params.FetchInterval
+	}
+	if params.FetchTimeout != 0 {
+		ret.HTTP.FetchTimeout = params.FetchTimeout
+	}
+
+	switch params.TimeSync {
+	case DeviceTimeSyncEnable:
+		ret.TimeSync.Disable = false
+	case DeviceTimeSyncDisable:
+		ret.TimeSync.Disable = true
+	}
+
+	if params.MaxDriftInterval != 0 {
+		ret.TimeSync.MaxDriftInterval = params.MaxDriftInterval
+	}
+
+	return ret
 }
 
 func (s *CacheStore) makeNodeHTTP(
@@ -286,110 +590,428 @@ func (s *CacheStore) makeNodeHTTP(
 	uri string,
 	typ string,
 	desc string,
-	now time.Time,
+	params CacheStoreParams,
+	holder *devcore.IDHolder,
+	logger *slog.Logger,
 ) (*storeNode, error) {
 	if u.Port() == "" {
-		return nil, fmt.Errorf("HTTP port is missed")
+		return nil, fmt.Errorf("%w: HTTP port is missed", status.StatusInvalidArg)
 	}
 
 	ctx, cancelFunc := context.WithCancel(s.ctx)
 	stopper := &syssched.FanoutStopper{}
 
-	holder := devcore.NewIDHolder(s.dataHandler)
+	tracker := newStatusTracker(holder)
+	profile := s.getProfile(typ)
 
-	runner := syssched.NewAsyncTaskRunner(
+	runner := s.makeHTTPRunner(
 		ctx,
 		s.newHTTPDevice(
 			ctx,
 			stopper,
+			params,
+			profile,
 			holder,
-			s.localClock,
-			s.remoteLastClock,
+			tracker,
+			logger,
 			uri,
 			desc,
 			u.Hostname(),
 		),
-		&logErrorHandler{uri: uri, typ: typ, desc: desc},
-		syssched.AsyncTaskRunnerParams{
-			UpdateInterval: s.params.HTTP.FetchInterval,
-		},
+		&logErrorHandler{logger: logger},
+		makeHTTPRunnerParams(uri, params),
 	)
 
 	stopper.Add(desc, runner)
 
 	return &storeNode{
-		uri:        uri,
-		typ:        typ,
-		desc:       desc,
-		createdAt:  now.Format(time.RFC1123),
 		cancelFunc: cancelFunc,
 		stopper:    stopper,
-		holder:     holder,
-		runner:     runner,
+		starter:    runner,
+		tracker:    tracker,
+		fetchURLs:  []string{uri + profile.RegistrationPath, uri + profile.TelemetryPath},
 	}, nil
 }
 
 func (s *CacheStore) newHTTPDevice(
 	ctx context.Context,
 	stopper *syssched.FanoutStopper,
-	dataHandler devcore.DataHandler,
-	localClock syscore.SystemClock,
-	remoteLastClock syscore.SystemClock,
+	params CacheStoreParams,
+	profile DeviceProfile,
+	holder *devcore.IDHolder,
+	tracker *statusTracker,
+	logger *slog.Logger,
 	uri string,
 	desc string,
 	hostname string,
 ) syssched.Task {
-	var clockSynchronizer devcore.TimeSynchronizer
-	if s.params.TimeSync.Disable {
-		clockSynchronizer = devcore.FuncSynchronizer(func() error {
-			return status.StatusNotSupported
-		})
+	client := s.makeProfileHTTPClient(profile, stopper, uri, desc, hostname)
+
+	remoteCurrClock := htcore.NewSystemClock(
+		ctx,
+		client,
+		uri+profile.TimePath,
+		params.HTTP.FetchTimeout,
+	)
+
+	var (
+		registrationFetcher devcore.Fetcher
+		dataHandler         devcore.DataHandler = holder
+	)
+
+	if profile.DisableRegistration {
+		dataHandler = &telemetryIDHandler{holder: holder}
 	} else {
-		remoteCurrClock := htcore.NewSystemClock(
+		fetcher := htcore.NewURLFetcher(
 			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/system/time",
-			s.params.HTTP.FetchTimeout,
+			client,
+			profile.Method,
+			uri+profile.RegistrationPath,
+			params.HTTP.FetchTimeout,
 		)
+		fetcher.SetDeviceIDProvider(holder)
 
-		clockSynchronizer = syscore.NewSystemClockSynchronizer(
-			localClock, remoteLastClock, remoteCurrClock)
+		registrationFetcher = fetcher
 	}
 
-	var clockVerifier devcore.TimeVerifier
-	if maxDriftInterval := s.params.TimeSync.MaxDriftInterval; maxDriftInterval == 0 {
-		clockVerifier = &devcore.BasicTimeVerifier{}
-	} else {
-		clockVerifier = devcore.NewDriftTimeVerifier(localClock, maxDriftInterval)
-	}
+	telemetryFetcher := htcore.NewURLFetcher(
+		ctx,
+		client,
+		profile.Method,
+		uri+profile.TelemetryPath,
+		params.HTTP.FetchTimeout,
+	)
+	telemetryFetcher.SetDeviceIDProvider(holder)
 
-	task := devcore.NewPollDevice(
-		htcore.NewURLFetcher(
-			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/registration",
-			s.params.HTTP.FetchTimeout,
-		),
-		htcore.NewURLFetcher(
-			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/telemetry",
-			s.params.HTTP.FetchTimeout,
-		),
+	pollDevice := devcore.NewPollDevice(
+		registrationFetcher,
+		telemetryFetcher,
 		dataHandler,
-		clockSynchronizer,
-		clockVerifier,
+		s.makeTimeSynchronizer(params, remoteCurrClock, tracker, logger),
+		s.makeTimeVerifier(params),
 	)
+	pollDevice.SetLogger(logger)
+	pollDevice.SetDriftTracker(devcore.NewDriftTracker(s.localClock, tracker.timeStats))
 
-	if s.aliveMonitor != nil {
-		notifier := s.aliveMonitor.Monitor(uri)
+	pollDevice.SetTimeCorrector(s.makeTimeCorrector(params, remoteCurrClock))
 
-		return syssched.NewTaskAliveNotifier(task, notifier)
+	var task syssched.Task = &statusTask{
+		task:    pollDevice,
+		tracker: tracker,
+	}
+
+	task = syssched.NewTaskAliveNotifier(task, &cacheStoreAliveNotifier{
+		store: s,
+		uri:   uri,
+	})
+
+	if params.HTTP.OfflineMaxFetchInterval > params.HTTP.FetchInterval {
+		task = &offlineTask{
+			task:  task,
+			clock: &syscore.LocalMonotonicClock{},
+			isOffline: func() bool {
+				return s.aliveMonitor != nil && s.aliveMonitor.IsOffline(uri)
+			},
+			interval:    params.HTTP.FetchInterval,
+			maxInterval: params.HTTP.OfflineMaxFetchInterval,
+		}
 	}
 
 	return task
 }
 
+func (s *CacheStore) makeNodePush(
+	u *url.URL,
+	uri string,
+	params CacheStoreParams,
+	holder *devcore.IDHolder,
+	logger *slog.Logger,
+) (*storeNode, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: push device ID is missed", status.StatusInvalidArg)
+	}
+	if u.Path != "" || u.RawQuery != "" {
+		return nil, fmt.Errorf("%w: push device URI should contain only device ID",
+			status.StatusInvalidArg)
+	}
+
+	tracker := newStatusTracker(holder)
+
+	pushDevice := devcore.NewPushDevice(
+		s.makePushDataHandler(uri, holder),
+		devcore.FuncSynchronizer(func() error {
+			return status.StatusNotSupported
+		}),
+		s.makeTimeVerifier(params),
+	)
+	pushDevice.SetLogger(logger)
+	pushDevice.SetDriftTracker(devcore.NewDriftTracker(s.localClock, tracker.timeStats))
+
+	// Only the data timestamp is known, the device UNIX time can't be requested.
+	pushDevice.SetTimeCorrector(s.makeTimeCorrector(params, nil))
+
+	return &storeNode{
+		cancelFunc:  func() {},
+		stopper:     &syssched.FanoutStopper{},
+		tracker:     tracker,
+		pushDevice:  pushDevice,
+		pushHandler: s.makePushHandler(uri, tracker, pushDevice),
+	}, nil
+}
+
+func (s *CacheStore) makeNodeMQTT(
+	u *url.URL,
+	uri string,
+	desc string,
+	params CacheStoreParams,
+	holder *devcore.IDHolder,
+	logger *slog.Logger,
+) (*storeNode, error) {
+	prefix := strings.Trim(u.Path, "/")
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: MQTT topic prefix is missed", status.StatusInvalidArg)
+	}
+
+	brokerHost := u.Host
+	if u.Port() == "" {
+		brokerHost = net.JoinHostPort(u.Hostname(), "1883")
+	}
+
+	password, _ := u.User.Password()
+
+	ctx, cancelFunc := context.WithCancel(s.ctx)
+	stopper := &syssched.FanoutStopper{}
+
+	key := mqttClientKey{
+		brokerURL: "tcp://" + brokerHost,
+		username:  u.User.Username(),
+		password:  password,
+	}
+	client := s.mqttClients.acquire(key)
+
+	remoteCurrClock := mqcore.NewSystemClock(
+		ctx,
+		client,
+		prefix+"/system/time/set",
+		mqttPublishTimeout,
+	)
+
+	tracker := newStatusTracker(holder)
+
+	pushDevice := devcore.NewPushDevice(
+		s.makePushDataHandler(uri, holder),
+		s.makeTimeSynchronizer(params, remoteCurrClock, tracker, logger),
+		s.makeTimeVerifier(params),
+	)
+	pushDevice.SetLogger(logger)
+	pushDevice.SetDriftTracker(devcore.NewDriftTracker(s.localClock, tracker.timeStats))
+
+	// Only the data timestamp is known, the device UNIX time can't be requested.
+	pushDevice.SetTimeCorrector(s.makeTimeCorrector(params, nil))
+
+	pushHandler := s.makePushHandler(uri, tracker, pushDevice)
+
+	subscriber := &mqttSubscriber{
+		pool:   s.mqttClients,
+		key:    key,
+		client: client,
+		handlers: map[string]mqcore.MessageHandler{
+			prefix + "/registration": mqcore.FuncMessageHandler(func(buf []byte) error {
+				handleMQTTTimestamp(remoteCurrClock, buf)
+
+				return pushHandler.HandleRegistration(buf)
+			}),
+			prefix + "/telemetry": mqcore.FuncMessageHandler(func(buf []byte) error {
+				handleMQTTTimestamp(remoteCurrClock, buf)
+
+				return pushHandler.HandleTelemetry(buf)
+			}),
+		},
+	}
+	stopper.Add("mqtt-subscriber-"+desc, subscriber)
+
+	return &storeNode{
+		cancelFunc: cancelFunc,
+		stopper:    stopper,
+		starter:    subscriber,
+		tracker:    tracker,
+		pushDevice: pushDevice,
+	}, nil
+}
+
+// makePushDataHandler persists the device ID once it's received, since the push
+// device may send the registration data only once, e.g. on boot.
+func (s *CacheStore) makePushDataHandler(
+	uri string,
+	holder *devcore.IDHolder,
+) devcore.DataHandler {
+	return &persistIDHandler{
+		holder: holder,
+		persist: func() {
+			s.persistDeviceID(uri, holder)
+		},
+	}
+}
+
+func (s *CacheStore) makePushHandler(
+	uri string,
+	tracker *statusTracker,
+	handler PushHandler,
+) PushHandler {
+	return &alivePushHandler{
+		handler: &statusPushHandler{
+			handler: handler,
+			tracker: tracker,
+		},
+		notifier: &cacheStoreAliveNotifier{
+			store: s,
+			uri:   uri,
+		},
+	}
+}
+
+func (s *CacheStore) makeTimeSynchronizer(
+	params CacheStoreParams,
+	remoteCurrClock syscore.SystemClock,
+	tracker *statusTracker,
+	logger *slog.Logger,
+) devcore.TimeSynchronizer {
+	if params.TimeSync.Disable {
+		return devcore.FuncSynchronizer(func() error {
+			return status.StatusNotSupported
+		})
+	}
+
+	synchronizer := syscore.NewSystemClockSynchronizer(
+		s.localClock, s.remoteLastClock, remoteCurrClock)
+	synchronizer.SetLogger(logger)
+
+	return &statusTimeSynchronizer{
+		synchronizer: synchronizer,
+		tracker:      tracker,
+	}
+}
+
+func (s *CacheStore) makeTimeCorrector(
+	params CacheStoreParams,
+	remoteCurrClock syscore.SystemClock,
+) devcore.TimeCorrector {
+	switch params.TimeSync.Correction {
+	case TimeCorrectionReceive:
+		return devcore.NewReceiveTimeCorrector(s.localClock)
+
+	case TimeCorrectionOffset:
+		if remoteCurrClock == nil {
+			return devcore.NewReceiveTimeCorrector(s.localClock)
+		}
+
+		return devcore.NewOffsetTimeCorrector(s.localClock, remoteCurrClock)
+
+	default:
+		return nil
+	}
+}
+
+func (s *CacheStore) makeTimeVerifier(params CacheStoreParams) devcore.TimeVerifier {
+	if maxDriftInterval := params.TimeSync.MaxDriftInterval; maxDriftInterval != 0 {
+		return devcore.NewDriftTimeVerifier(s.localClock, maxDriftInterval)
+	}
+
+	return &devcore.BasicTimeVerifier{}
+}
+
+// httpRunner periodically polls the HTTP device.
+type httpRunner interface {
+	syssched.Starter
+	syssched.Stopper
+}
+
+func (s *CacheStore) makeHTTPRunner(
+	ctx context.Context,
+	task syssched.Task,
+	handler syssched.ErrorHandler,
+	params syssched.AsyncTaskRunnerParams,
+) httpRunner {
+	if s.params.Scheduler != nil {
+		return s.params.Scheduler.Add(ctx, task, handler, params)
+	}
+
+	return syssched.NewAsyncTaskRunner(ctx, task, handler, params)
+}
+
+func makeHTTPRunnerParams(uri string, params CacheStoreParams) syssched.AsyncTaskRunnerParams {
+	runnerParams := syssched.AsyncTaskRunnerParams{
+		Name:           uri,
+		UpdateInterval: params.HTTP.FetchInterval,
+		Jitter:         params.HTTP.FetchJitter,
+	}
+
+	if params.HTTP.ErrorMaxFetchInterval > params.HTTP.FetchInterval {
+		runnerParams.BackoffInitial = params.HTTP.FetchInterval
+		runnerParams.BackoffMax = params.HTTP.ErrorMaxFetchInterval
+	}
+
+	if params.HTTP.FetchJitter > 0 {
+		runnerParams.FirstRunDelay = params.HTTP.FetchInterval
+	}
+
+	return runnerParams
+}
+
+func (s *CacheStore) getProfile(typ string) DeviceProfile {
+	return getDeviceProfile(s.params.Profiles, typ)
+}
+
+// nodeParamsChanged returns true if the node should be restarted to apply the new
+// store configuration.
+func nodeParamsChanged(node *storeNode, prev CacheStoreParams, next CacheStoreParams) bool {
+	prevParams := makeNodeParams(prev, node.params)
+	nextParams := makeNodeParams(next, node.params)
+
+	if prevParams.TimeSync != nextParams.TimeSync {
+		return true
+	}
+
+	// Push and MQTT devices aren't polled.
+	if node.kind != deviceTypeHTTP {
+		return false
+	}
+
+	return prevParams.HTTP != nextParams.HTTP ||
+		!reflect.DeepEqual(getDeviceProfile(prev.Profiles, node.typ),
+			getDeviceProfile(next.Profiles, node.typ))
+}
+
+func (s *CacheStore) makeProfileHTTPClient(
+	profile DeviceProfile,
+	stopper *syssched.FanoutStopper,
+	uri string,
+	desc string,
+	hostname string,
+) *htcore.HTTPClient {
+	client := s.makeHTTPClient(stopper, uri, desc, hostname)
+
+	if len(profile.Headers) == 0 {
+		return client
+	}
+
+	header := make(http.Header)
+	for key, value := range profile.Headers {
+		header.Set(key, value)
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &htcore.HTTPClient{
+		Client: http.Client{
+			Transport: httransport.NewHeaderRoundTripper(header, transport),
+		},
+	}
+}
+
 func (s *CacheStore) makeHTTPClient(
 	stopper *syssched.FanoutStopper,
 	uri string,
@@ -397,7 +1019,7 @@ func (s *CacheStore) makeHTTPClient(
 	hostname string,
 ) *htcore.HTTPClient {
 	if !strings.Contains(uri, ".local") {
-		return htcore.NewDefaultClient()
+		return s.httpClient
 	}
 
 	s.resolveStore.Add(hostname)
@@ -408,37 +1030,152 @@ func (s *CacheStore) makeHTTPClient(
 		return nil
 	}))
 
-	return htcore.NewResolveClient(s.resolveStore)
+	return s.resolveClient
 }
 
+const pushScheme = "push"
+
+// MQTT connection options, independent of the HTTP polling configuration.
+const (
+	// mqttConnectTimeout - how long to wait for the connection to the MQTT broker.
+	mqttConnectTimeout = 10 * time.Second
+
+	// mqttConnectRetryInterval - how long to wait between the connection attempts.
+	mqttConnectRetryInterval = 10 * time.Second
+
+	// mqttPublishTimeout - how long to wait for the device UNIX time to be published.
+	mqttPublishTimeout = 5 * time.Second
+)
+
 type deviceType int
 
 const (
 	deviceTypeUnsupported deviceType = iota
 	deviceTypeHTTP
+	deviceTypePush
+	deviceTypeMQTT
 )
 
 func parseDeviceType(scheme string) deviceType {
 	if scheme == "http" || scheme == "https" {
 		return deviceTypeHTTP
 	}
+	if scheme == pushScheme {
+		return deviceTypePush
+	}
+	if scheme == "mqtt" {
+		return deviceTypeMQTT
+	}
 
 	return deviceTypeUnsupported
 }
 
+func handleMQTTTimestamp(clock *mqcore.SystemClock, buf []byte) {
+	var data struct {
+		Timestamp *float64 `json:"timestamp"`
+	}
+
+	if err := json.Unmarshal(buf, &data); err == nil && data.Timestamp != nil {
+		clock.HandleTimestamp(int64(*data.Timestamp))
+	}
+}
+
+type alivePushHandler struct {
+	handler  PushHandler
+	notifier syssched.AliveNotifier
+}
+
+func (h *alivePushHandler) HandleRegistration(buf []byte) error {
+	return h.handler.HandleRegistration(buf)
+}
+
+func (h *alivePushHandler) HandleTelemetry(buf []byte) error {
+	if err := h.handler.HandleTelemetry(buf); err != nil {
+		return err
+	}
+
+	h.notifier.NotifyAlive()
+
+	return nil
+}
+
+// cacheStoreAliveNotifier resolves the alive monitor on each notification, since
+// the cached devices are restored before the monitor is set.
+type cacheStoreAliveNotifier struct {
+	store *CacheStore
+	uri   string
+}
+
+func (n *cacheStoreAliveNotifier) NotifyAlive() {
+	if n.store.aliveMonitor != nil {
+		n.store.aliveMonitor.Monitor(n.uri).NotifyAlive()
+	}
+}
+
+// telemetryIDHandler updates the device ID from the telemetry data, used if the device
+// registration data isn't fetched.
+type telemetryIDHandler struct {
+	holder *devcore.IDHolder
+}
+
+func (h *telemetryIDHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.holder.HandleRegistration(deviceID, js)
+}
+
+func (h *telemetryIDHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	h.holder.Set(deviceID)
+
+	return h.holder.HandleTelemetry(deviceID, js)
+}
+
+// persistIDHandler persists the device ID each time it's changed by the registration.
+type persistIDHandler struct {
+	holder  *devcore.IDHolder
+	persist func()
+}
+
+func (h *persistIDHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	prevID := h.holder.Get()
+
+	err := h.holder.HandleRegistration(deviceID, js)
+
+	if prevID != deviceID {
+		h.persist()
+	}
+
+	return err
+}
+
+func (h *persistIDHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.holder.HandleTelemetry(deviceID, js)
+}
+
 type storeNode struct {
-	uri        string
-	typ        string
-	desc       string
-	createdAt  string
-	cancelFunc context.CancelFunc
-	stopper    *syssched.FanoutStopper
-	holder     *devcore.IDHolder
-	runner     *syssched.AsyncTaskRunner
+	uri         string
+	kind        deviceType
+	typ         string
+	desc        string
+	createdAt   string
+	timestamp   int64
+	params      DeviceParams
+	cancelFunc  context.CancelFunc
+	stopper     *syssched.FanoutStopper
+	starter     syssched.Starter
+	holder      *devcore.IDHolder
+	tracker     *statusTracker
+	pushDevice  *devcore.PushDevice
+	pushHandler PushHandler
+	fetchURLs   []string
+	logInfo     *deviceLogInfo
+	logger      *slog.Logger
 }
 
 func (s *storeNode) start() error {
-	return s.runner.Start()
+	if s.starter == nil {
+		return nil
+	}
+
+	return s.starter.Start()
 }
 
 func (s *storeNode) stop() error {
@@ -446,3 +1183,20 @@ func (s *storeNode) stop() error {
 
 	return s.stopper.Stop()
 }
+
+// setDeviceID sets the device ID known from the previous registration.
+//
+// Remarks:
+//   - The push device doesn't accept telemetry until the device ID is known.
+//   - Empty ID is ignored.
+func (s *storeNode) setDeviceID(deviceID string) {
+	if deviceID == "" {
+		return
+	}
+
+	s.holder.Set(deviceID)
+
+	if s.pushDevice != nil {
+		s.pushDevice.SetDeviceID(deviceID)
+	}
+}
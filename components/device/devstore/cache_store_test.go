@@ -3,18 +3,27 @@ package devstore
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"log/slog"
 	"maps"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-control-systems/device-hub/components/device/devcore"
 	"github.com/open-control-systems/device-hub/components/status"
 	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
 	"github.com/open-control-systems/device-hub/components/system/sysnet"
+	"github.com/open-control-systems/device-hub/components/system/syssched"
 )
 
 type testCacheStoreDB struct {
@@ -253,7 +262,7 @@ func TestCacheStoreAddURIUnsupportedScheme(t *testing.T) {
 	}()
 
 	require.Equal(t, status.StatusNotSupported,
-		store.Add("foo-bar-baz", "test-type", "foo-bar-baz"))
+		store.Add("foo-bar-baz", "test-type", "foo-bar-baz", DeviceParams{}))
 }
 
 func TestCacheStoreAddRemoveResourceNoResponse(t *testing.T) {
@@ -297,7 +306,7 @@ func TestCacheStoreAddRemoveResourceNoResponse(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		require.Nil(t, store.Add(test.uri, test.typ, test.desc))
+		require.Nil(t, store.Add(test.uri, test.typ, test.desc, DeviceParams{}))
 	}
 
 	<-ctx.Done()
@@ -324,6 +333,67 @@ func TestCacheStoreAddRemoveResourceNoResponse(t *testing.T) {
 	require.Equal(t, 0, db.count())
 }
 
+func countTestCacheStoreFetchSeries(t *testing.T, url string) int {
+	families, err := sysmetrics.Registry.Gather()
+	require.Nil(t, err)
+
+	count := 0
+
+	for _, family := range families {
+		if family.GetName() != "device_hub_http_fetch_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "url" && label.GetValue() == url {
+					count++
+				}
+			}
+		}
+	}
+
+	return count
+}
+
+func TestCacheStoreRemoveFetchMetrics(t *testing.T) {
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 50
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		newTestCacheStoreDataHandler(),
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	// Device never responds, so its ID is never known.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fetchURL := server.URL + "/registration"
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo", DeviceParams{}))
+
+	require.Eventually(t, func() bool {
+		return countTestCacheStoreFetchSeries(t, fetchURL) > 0
+	}, time.Second*5, time.Millisecond*10)
+
+	require.Nil(t, store.Remove(server.URL))
+	require.Equal(t, 0, countTestCacheStoreFetchSeries(t, fetchURL))
+}
+
 func TestCacheStoreAddRemove(t *testing.T) {
 	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
@@ -366,10 +436,62 @@ func TestCacheStoreAddRemove(t *testing.T) {
 	server := httptest.NewServer(mux)
 	defer server.Close()
 
-	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz"))
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceParams{}))
+
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+}
+
+func TestCacheStoreScheduler(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	scheduler := syssched.NewPoolScheduler(syssched.PoolSchedulerParams{Workers: 2})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	storeParams := CacheStoreParams{Scheduler: scheduler}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceParams{}))
 
 	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
 	require.True(t, maps.Equal(registrationData, <-handler.registration))
+
+	require.Nil(t, store.Remove(server.URL))
+	require.Equal(t, 0, db.count())
 }
 
 func TestCacheStoreRestore(t *testing.T) {
@@ -422,7 +544,7 @@ func TestCacheStoreRestore(t *testing.T) {
 	deviceDesc := "foo-bar-baz"
 	deviceType := "test-type"
 
-	require.Nil(t, store1.Add(deviceURI, deviceType, deviceDesc))
+	require.Nil(t, store1.Add(deviceURI, deviceType, deviceDesc, DeviceParams{}))
 
 	require.True(t, maps.Equal(telemetryData, <-handler1.telemetry))
 	require.True(t, maps.Equal(registrationData, <-handler1.registration))
@@ -442,7 +564,7 @@ func TestCacheStoreRestore(t *testing.T) {
 
 	require.Nil(t, store2.Start())
 
-	require.NotNil(t, store2.Add(deviceURI, deviceType, deviceDesc))
+	require.NotNil(t, store2.Add(deviceURI, deviceType, deviceDesc, DeviceParams{}))
 	require.True(t, maps.Equal(telemetryData, <-handler2.telemetry))
 	require.True(t, maps.Equal(registrationData, <-handler2.registration))
 
@@ -451,12 +573,12 @@ func TestCacheStoreRestore(t *testing.T) {
 	handler3 := newTestCacheStoreDataHandler()
 	store3 := makeStore(db, handler3)
 
-	require.Nil(t, store3.Add(deviceURI, deviceType, deviceDesc))
+	require.Nil(t, store3.Add(deviceURI, deviceType, deviceDesc, DeviceParams{}))
 	require.True(t, maps.Equal(telemetryData, <-handler3.telemetry))
 	require.True(t, maps.Equal(registrationData, <-handler3.registration))
 }
 
-func TestCacheStoreAddSameDevice(t *testing.T) {
+func TestCacheStoreUpdate(t *testing.T) {
 	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
 	handler := newTestCacheStoreDataHandler()
@@ -478,20 +600,144 @@ func TestCacheStoreAddSameDevice(t *testing.T) {
 		require.Nil(t, store.Stop())
 	}()
 
-	require.Nil(t, store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com"))
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
 
-	require.Equal(t, ErrDeviceExist,
-		store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com"))
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Equal(t, status.StatusNoData, store.Update(server.URL, "test-type", "foo"))
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo", DeviceParams{}))
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	createdAt := descs[0].CreatedAt
+
+	require.Nil(t, store.Update(server.URL, "new-type", "bar"))
+
+	descs = store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "new-type", descs[0].Type)
+	require.Equal(t, "bar", descs[0].Desc)
+	require.Equal(t, createdAt, descs[0].CreatedAt)
+
+	var item StorageItem
+	_, err := item.Unmarshal(db.data[server.URL])
+	require.Nil(t, err)
+	require.Equal(t, "new-type", item.Type)
+	require.Equal(t, "bar", item.Desc)
+
+	// Device is still polled after the update.
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
 }
 
-func TestCacheStoreNoopDB(t *testing.T) {
-	db := &stcore.NoopDB{}
+func TestCacheStoreDeviceParams(t *testing.T) {
+	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
-	handler := newTestCacheStoreDataHandler()
 
 	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Hour
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	makeStore := func(h devcore.DataHandler) *CacheStore {
+		return NewCacheStore(
+			context.Background(),
+			clock,
+			clock,
+			h,
+			db,
+			sysnet.NewResolveStore(),
+			storeParams,
+		)
+	}
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	params := DeviceParams{
+		FetchInterval:    time.Millisecond * 50,
+		TimeSync:         DeviceTimeSyncDisable,
+		MaxDriftInterval: time.Second * 10,
+	}
+
+	handler1 := newTestCacheStoreDataHandler()
+	store1 := makeStore(handler1)
+
+	require.NotNil(t, store1.Add(server.URL, "test-type", "foo",
+		DeviceParams{FetchInterval: -time.Second}))
+	require.Equal(t, 0, db.count())
+
+	require.Nil(t, store1.Add(server.URL, "test-type", "foo", params))
+
+	// Device is fetched more often than configured for the store.
+	for n := 0; n < 3; n++ {
+		require.True(t, maps.Equal(telemetryData, <-handler1.telemetry))
+	}
+
+	descs := store1.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, params, descs[0].Params)
+
+	require.Nil(t, store1.Stop())
+
+	handler2 := newTestCacheStoreDataHandler()
+	store2 := makeStore(handler2)
+	defer func() {
+		require.Nil(t, store2.Stop())
+	}()
+
+	descs = store2.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, params, descs[0].Params)
+
+	require.Nil(t, store2.Start())
+
+	for n := 0; n < 3; n++ {
+		require.True(t, maps.Equal(telemetryData, <-handler2.telemetry))
+	}
+}
+
+func TestCacheStoreDeviceProfile(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	profiles := NewDeviceProfileRegistry()
+	require.Nil(t, profiles.Add(DeviceProfile{
+		Type:                "custom-type",
+		TelemetryPath:       "/data",
+		Method:              "post",
+		Headers:             map[string]string{"X-Api-Key": "secret"},
+		DisableRegistration: true,
+	}))
+
+	storeParams := CacheStoreParams{Profiles: profiles}
 	storeParams.HTTP.FetchInterval = time.Millisecond * 100
 	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.Disable = true
 
 	store := NewCacheStore(
 		context.Background(),
@@ -506,29 +752,54 @@ func TestCacheStoreNoopDB(t *testing.T) {
 		require.Nil(t, store.Stop())
 	}()
 
-	deviceURI := "http://foo.bar.com:123"
-	deviceDesc := "foo-bar-com"
-	deviceType := "test-type"
+	deviceID := "0xABCD"
 
-	require.Nil(t, store.Add(deviceURI, deviceType, deviceDesc))
-	require.Nil(t, store.Remove(deviceURI))
-}
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["device_id"] = deviceID
 
-func TestCacheStoreRestoreInvalidFormat(t *testing.T) {
-	deviceURI := "http://foo.bar.com:123"
-	deviceDesc := "foo-bar-com"
+	telemetryHandler := newTestCacheStoreHTTPDataHandler(telemetryData)
 
-	db := newTestCacheStoreDB()
-	require.Nil(t, db.Write(deviceURI, []byte(deviceDesc)))
-	_, err := db.Read(deviceURI)
-	require.Nil(t, err)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+
+			return
+		}
+
+		telemetryHandler.ServeHTTP(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "custom-type", "foo-bar-baz", DeviceParams{}))
+
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, deviceID, descs[0].ID)
+}
 
+func TestCacheStoreUpdateDeviceProfile(t *testing.T) {
+	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
 	handler := newTestCacheStoreDataHandler()
 
-	storeParams := CacheStoreParams{}
-	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	profiles := NewDeviceProfileRegistry()
+	require.Nil(t, profiles.Add(DeviceProfile{
+		Type:                "custom-type",
+		TelemetryPath:       "/data",
+		DisableRegistration: true,
+	}))
+
+	storeParams := CacheStoreParams{Profiles: profiles}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 50
 	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.Disable = true
 
 	store := NewCacheStore(
 		context.Background(),
@@ -543,33 +814,71 @@ func TestCacheStoreRestoreInvalidFormat(t *testing.T) {
 		require.Nil(t, store.Stop())
 	}()
 
-	_, err = db.Read(deviceURI)
-	require.Equal(t, status.StatusNoData, err)
-}
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["device_id"] = "0xABCD"
 
-func TestCacheStoreRestoreUnsupportedScheme(t *testing.T) {
-	deviceURI := "ftp://foo.bar.com:123"
-	deviceDesc := "foo-bar-com"
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
 
-	storageItem := StorageItem{
-		Desc:      deviceDesc,
-		Timestamp: time.Now().Unix(),
+	paths := make(chan string, 64)
+
+	dataHandler := newTestCacheStoreHTTPDataHandler(telemetryData)
+	pathHandler := func(h http.Handler) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case paths <- r.URL.Path:
+			default:
+			}
+
+			h.ServeHTTP(w, r)
+		}
 	}
-	buf, err := storageItem.MarshalBinary()
-	require.Nil(t, err)
-	require.NotNil(t, buf)
 
-	db := newTestCacheStoreDB()
-	require.Nil(t, db.Write(deviceURI, buf))
-	_, err = db.Read(deviceURI)
-	require.Nil(t, err)
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", pathHandler(dataHandler))
+	mux.Handle("/data", pathHandler(dataHandler))
+	mux.Handle("/registration",
+		pathHandler(newTestCacheStoreHTTPDataHandler(registrationData)))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	waitPath := func(path string) {
+		for <-paths != path {
+		}
+	}
 
+	require.Nil(t, store.Add(server.URL, "test-type", "foo", DeviceParams{}))
+	waitPath("/telemetry")
+
+	require.Nil(t, store.Update(server.URL, "custom-type", "foo"))
+
+	// Only the new profile path is fetched once the restarted device is polled.
+	waitPath("/data")
+	for len(paths) > 0 {
+		<-paths
+	}
+	for n := 0; n < 3; n++ {
+		require.Equal(t, "/data", <-paths)
+	}
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "custom-type", descs[0].Type)
+	require.Equal(t, "0xABCD", descs[0].ID)
+}
+
+func TestCacheStoreSetParams(t *testing.T) {
+	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
 	handler := newTestCacheStoreDataHandler()
 
 	storeParams := CacheStoreParams{}
-	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchInterval = time.Hour
 	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.Disable = true
 
 	store := NewCacheStore(
 		context.Background(),
@@ -584,6 +893,598 @@ func TestCacheStoreRestoreUnsupportedScheme(t *testing.T) {
 		require.Nil(t, store.Stop())
 	}()
 
-	_, err = db.Read(deviceURI)
-	require.Equal(t, status.StatusNoData, err)
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add(server.URL, "test-type", "foo", DeviceParams{}))
+
+	require.Eventually(t, func() bool {
+		descs := store.GetDesc()
+
+		return len(descs) == 1 && descs[0].Status.LastSuccessAt != ""
+	}, time.Second*5, time.Millisecond*10)
+
+	prevDescs := store.GetDesc()
+	require.Equal(t, "0xABCD", prevDescs[0].ID)
+
+	storeParams.HTTP.FetchInterval = time.Millisecond * 50
+	store.SetParams(storeParams)
+
+	// Device is fetched with the new interval.
+	for n := 0; n < 3; n++ {
+		require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	}
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, prevDescs[0].ID, descs[0].ID)
+	require.Equal(t, prevDescs[0].CreatedAt, descs[0].CreatedAt)
+	require.Equal(t, 1, db.count())
+}
+
+func TestCacheStoreSetParamsPushDevice(t *testing.T) {
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Hour
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		newTestCacheStoreDataHandler(),
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add("push://foo", "test-type", "foo", DeviceParams{}))
+
+	pushHandler, err := store.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.Nil(t, pushHandler.HandleRegistration(
+		[]byte(`{"timestamp":123,"device_id":"0xABCD"}`)))
+
+	// HTTP options don't affect push devices.
+	storeParams.HTTP.FetchInterval = time.Minute
+	store.SetParams(storeParams)
+
+	handler, err := store.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.Same(t, pushHandler, handler)
+
+	storeParams.TimeSync.MaxDriftInterval = time.Hour
+	store.SetParams(storeParams)
+
+	handler, err = store.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.NotSame(t, pushHandler, handler)
+
+	// Device ID is preserved, telemetry is accepted without the registration.
+	require.Nil(t, handler.HandleTelemetry(
+		[]byte(`{"timestamp":123,"temperature":123.222}`)))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "0xABCD", descs[0].ID)
+}
+
+func TestCacheStoreRestorePushDevice(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Hour
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	makeStore := func() *CacheStore {
+		return NewCacheStore(
+			context.Background(),
+			clock,
+			clock,
+			newTestCacheStoreDataHandler(),
+			db,
+			sysnet.NewResolveStore(),
+			storeParams,
+		)
+	}
+
+	store1 := makeStore()
+	require.Nil(t, store1.Start())
+	require.Nil(t, store1.Add("push://foo", "test-type", "foo", DeviceParams{}))
+
+	pushHandler, err := store1.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.Nil(t, pushHandler.HandleRegistration(
+		[]byte(`{"timestamp":123,"device_id":"0xABCD"}`)))
+
+	var item StorageItem
+	_, err = item.Unmarshal(db.data["push://foo"])
+	require.Nil(t, err)
+	require.Equal(t, "0xABCD", item.DeviceID)
+
+	require.Nil(t, store1.Stop())
+
+	store2 := makeStore()
+	defer func() {
+		require.Nil(t, store2.Stop())
+	}()
+	require.Nil(t, store2.Start())
+
+	descs := store2.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "0xABCD", descs[0].ID)
+
+	// Device ID is restored, telemetry is accepted without the registration.
+	pushHandler, err = store2.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.Nil(t, pushHandler.HandleTelemetry(
+		[]byte(`{"timestamp":123,"temperature":123.222}`)))
+}
+
+func TestCacheStoreDriftBasicTimeVerifier(t *testing.T) {
+	clock := &testCacheStoreClock{timestamp: 200}
+
+	// Drift isn't verified, but it's still recorded.
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Hour
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		newTestCacheStoreDataHandler(),
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add("push://foo", "test-type", "foo", DeviceParams{}))
+
+	pushHandler, err := store.GetPushHandler("foo")
+	require.Nil(t, err)
+	require.Nil(t, pushHandler.HandleRegistration(
+		[]byte(`{"timestamp":190,"device_id":"0xABCD"}`)))
+	require.Nil(t, pushHandler.HandleTelemetry(
+		[]byte(`{"timestamp":100,"temperature":123.222}`)))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+
+	drift := descs[0].Status.Time.Drift
+	require.Equal(t, uint64(2), drift.Samples)
+	require.Equal(t, int64(100), drift.Current)
+	require.Equal(t, int64(10), drift.Min)
+}
+
+func TestCacheStoreAddSameDevice(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com",
+		DeviceParams{}))
+
+	require.Equal(t, ErrDeviceExist,
+		store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com", DeviceParams{}))
+}
+
+func TestCacheStoreNoopDB(t *testing.T) {
+	db := &stcore.NoopDB{}
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceURI := "http://foo.bar.com:123"
+	deviceDesc := "foo-bar-com"
+	deviceType := "test-type"
+
+	require.Nil(t, store.Add(deviceURI, deviceType, deviceDesc, DeviceParams{}))
+	require.Nil(t, store.Remove(deviceURI))
+}
+
+func TestCacheStoreNoopDBUpdate(t *testing.T) {
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Hour
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		newTestCacheStoreDataHandler(),
+		&stcore.NoopDB{},
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceURI := "http://foo.bar.com:123"
+
+	require.Nil(t, store.Add(deviceURI, "test-type", "foo-bar-com", DeviceParams{}))
+	require.Nil(t, store.Update(deviceURI, "new-type", "bar"))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "new-type", descs[0].Type)
+	require.Equal(t, "bar", descs[0].Desc)
+}
+
+func TestCacheStoreRestoreInvalidFormat(t *testing.T) {
+	deviceURI := "http://foo.bar.com:123"
+	deviceDesc := "foo-bar-com"
+
+	db := newTestCacheStoreDB()
+	require.Nil(t, db.Write(deviceURI, []byte(deviceDesc)))
+	_, err := db.Read(deviceURI)
+	require.Nil(t, err)
+
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	_, err = db.Read(deviceURI)
+	require.Equal(t, status.StatusNoData, err)
+}
+
+func TestCacheStoreRestoreUnsupportedScheme(t *testing.T) {
+	deviceURI := "ftp://foo.bar.com:123"
+	deviceDesc := "foo-bar-com"
+
+	storageItem := StorageItem{
+		Desc:      deviceDesc,
+		Timestamp: time.Now().Unix(),
+	}
+	buf, err := storageItem.MarshalBinary()
+	require.Nil(t, err)
+	require.NotNil(t, buf)
+
+	db := newTestCacheStoreDB()
+	require.Nil(t, db.Write(deviceURI, buf))
+	_, err = db.Read(deviceURI)
+	require.Nil(t, err)
+
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	_, err = db.Read(deviceURI)
+	require.Equal(t, status.StatusNoData, err)
+}
+
+func newTestCacheStoreBroker(t *testing.T) (*mqttserver.Server, string) {
+	server := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.Nil(t, server.AddHook(new(auth.AllowHook), nil))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	require.Nil(t, server.AddListener(listeners.NewNet("test", ln)))
+	require.Nil(t, server.Serve())
+
+	return server, ln.Addr().String()
+}
+
+func countTestCacheStoreBrokerClients(broker *mqttserver.Server) int {
+	count := 0
+
+	for _, client := range broker.Clients.GetAll() {
+		if !client.Net.Inline && !client.Closed() {
+			count++
+		}
+	}
+
+	return count
+}
+
+func publishTestCacheStoreData(
+	ctx context.Context,
+	broker *mqttserver.Server,
+	topic string,
+	js devcore.JSON,
+) {
+	buf, err := json.Marshal(js)
+	if err != nil {
+		panic(err)
+	}
+
+	ticker := time.NewTicker(time.Millisecond * 50)
+	defer ticker.Stop()
+
+	for {
+		_ = broker.Publish(topic, buf, false, 0)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func TestCacheStoreAddMQTT(t *testing.T) {
+	broker, brokerAddr := newTestCacheStoreBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Add("mqtt://"+brokerAddr+"/devices/soil-sensor-1",
+		"test-type", "foo-bar-baz", DeviceParams{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	go publishTestCacheStoreData(ctx, broker, "devices/soil-sensor-1/registration",
+		registrationData)
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+
+	go publishTestCacheStoreData(ctx, broker, "devices/soil-sensor-1/telemetry",
+		telemetryData)
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "0xABCD", descs[0].ID)
+}
+
+func TestCacheStoreMQTTSharedClient(t *testing.T) {
+	broker, brokerAddr := newTestCacheStoreBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	fooURI := "mqtt://" + brokerAddr + "/devices/foo"
+	barURI := "mqtt://" + brokerAddr + "/devices/bar"
+
+	require.Nil(t, store.Add(fooURI, "test-type", "foo", DeviceParams{}))
+	require.Nil(t, store.Add(barURI, "test-type", "bar", DeviceParams{}))
+
+	require.Eventually(t, func() bool {
+		return countTestCacheStoreBrokerClients(broker) == 1
+	}, time.Second*5, time.Millisecond*10)
+
+	// Remaining device still receives data via the shared client.
+	require.Nil(t, store.Remove(fooURI))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = "0xBAR"
+
+	go publishTestCacheStoreData(ctx, broker, "devices/bar/registration",
+		registrationData)
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+
+	require.Nil(t, store.Remove(barURI))
+
+	require.Eventually(t, func() bool {
+		return countTestCacheStoreBrokerClients(broker) == 0
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestCacheStoreAddMQTTSyncTime(t *testing.T) {
+	broker, brokerAddr := newTestCacheStoreBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	timeCh := make(chan []byte, 1)
+
+	require.Nil(t, broker.Subscribe("devices/soil-sensor-1/system/time/set", 1,
+		func(_ *mqttserver.Client, _ packets.Subscription, pk packets.Packet) {
+			select {
+			case timeCh <- pk.Payload:
+			default:
+			}
+		}))
+
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{timestamp: 1000}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Add("mqtt://"+brokerAddr+"/devices/soil-sensor-1",
+		"test-type", "foo-bar-baz", DeviceParams{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(-1)
+	registrationData["device_id"] = "0xABCD"
+
+	go publishTestCacheStoreData(ctx, broker, "devices/soil-sensor-1/registration",
+		registrationData)
+	require.Equal(t, []byte("1000"), <-timeCh)
+}
+
+func TestCacheStoreAddMQTTInvalidURI(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+	handler := newTestCacheStoreDataHandler()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.NotNil(t, store.Add("mqtt://127.0.0.1:1883", "test-type", "foo-bar-baz",
+		DeviceParams{}))
+	require.NotNil(t, store.Add("mqtt://127.0.0.1:1883/", "test-type", "foo-bar-baz",
+		DeviceParams{}))
+	require.Equal(t, 0, db.count())
 }
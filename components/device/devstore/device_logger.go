@@ -0,0 +1,103 @@
+package devstore
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// deviceLogInfo holds the device attributes added to each log record.
+//
+// Remarks:
+//   - Type and description can be changed while the device is running.
+//   - Device ID is taken from the holder when the record is logged.
+type deviceLogInfo struct {
+	uri    string
+	holder *devcore.IDHolder
+
+	mu   sync.Mutex
+	typ  string
+	desc string
+}
+
+func (i *deviceLogInfo) update(typ string, desc string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.typ = typ
+	i.desc = desc
+}
+
+func (i *deviceLogInfo) attrs() []slog.Attr {
+	i.mu.Lock()
+	typ, desc := i.typ, i.desc
+	i.mu.Unlock()
+
+	attrs := []slog.Attr{slog.String("uri", i.uri)}
+
+	if deviceID := i.holder.Get(); deviceID != "" {
+		attrs = append(attrs, slog.String("device_id", deviceID))
+	}
+
+	return append(attrs, slog.String("type", typ), slog.String("desc", desc))
+}
+
+// newDeviceLogger returns the logger which annotates each record with the device
+// uri, device_id, type and desc.
+func newDeviceLogger(info *deviceLogInfo) *slog.Logger {
+	return slog.New(&deviceLogHandler{
+		handler: syscore.NewLogger("device").Handler(),
+		info:    info,
+	})
+}
+
+type deviceLogHandler struct {
+	handler slog.Handler
+	info    *deviceLogInfo
+}
+
+func (h *deviceLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *deviceLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	keys := make(map[string]bool)
+	record.Attrs(func(attr slog.Attr) bool {
+		keys[attr.Key] = true
+
+		return true
+	})
+
+	deviceRecord := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	for _, attr := range h.info.attrs() {
+		if !keys[attr.Key] {
+			deviceRecord.AddAttrs(attr)
+		}
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		deviceRecord.AddAttrs(attr)
+
+		return true
+	})
+
+	return h.handler.Handle(ctx, deviceRecord)
+}
+
+func (h *deviceLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &deviceLogHandler{
+		handler: h.handler.WithAttrs(attrs),
+		info:    h.info,
+	}
+}
+
+func (h *deviceLogHandler) WithGroup(name string) slog.Handler {
+	return &deviceLogHandler{
+		handler: h.handler.WithGroup(name),
+		info:    h.info,
+	}
+}
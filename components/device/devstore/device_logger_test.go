@@ -0,0 +1,56 @@
+package devstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+)
+
+func TestDeviceLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	holder := devcore.NewIDHolder(newTestCacheStoreDataHandler())
+	info := &deviceLogInfo{uri: "push://foo", holder: holder, typ: "foo-type", desc: "foo"}
+
+	logger := slog.New(&deviceLogHandler{
+		handler: slog.NewJSONHandler(&buf, nil),
+		info:    info,
+	})
+
+	readRecord := func() map[string]any {
+		var record map[string]any
+		require.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+		buf.Reset()
+
+		return record
+	}
+
+	logger.Info("first")
+
+	record := readRecord()
+	require.Equal(t, "push://foo", record["uri"])
+	require.Equal(t, "foo-type", record["type"])
+	require.Equal(t, "foo", record["desc"])
+	require.NotContains(t, record, "device_id")
+
+	holder.Set("0xABCD")
+	info.update("bar-type", "bar")
+
+	logger.Info("second", "err", "failed")
+
+	record = readRecord()
+	require.Equal(t, "0xABCD", record["device_id"])
+	require.Equal(t, "bar-type", record["type"])
+	require.Equal(t, "bar", record["desc"])
+	require.Equal(t, "failed", record["err"])
+
+	logger.Info("third", "device_id", "0xDCBA")
+
+	record = readRecord()
+	require.Equal(t, "0xDCBA", record["device_id"])
+}
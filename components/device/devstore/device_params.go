@@ -0,0 +1,161 @@
+package devstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// DeviceTimeSync defines whether the device UNIX time is synchronized automatically.
+type DeviceTimeSync uint8
+
+const (
+	// DeviceTimeSyncDefault uses the store configuration.
+	DeviceTimeSyncDefault DeviceTimeSync = iota
+
+	// DeviceTimeSyncEnable enables the device UNIX time synchronization.
+	DeviceTimeSyncEnable
+
+	// DeviceTimeSyncDisable disables the device UNIX time synchronization.
+	DeviceTimeSyncDisable
+)
+
+// DeviceParams represents per-device configuration options.
+//
+// Remarks:
+//   - Zero values mean that the store configuration is used.
+type DeviceParams struct {
+	// FetchInterval - how often to fetch data from the HTTP device.
+	FetchInterval time.Duration
+
+	// FetchTimeout - how long to wait for the response from the device.
+	FetchTimeout time.Duration
+
+	// TimeSync - whether the device UNIX time is synchronized automatically.
+	TimeSync DeviceTimeSync
+
+	// MaxDriftInterval - maximum allowed time difference between local and device
+	// UNIX time.
+	MaxDriftInterval time.Duration
+}
+
+type deviceParamsJSON struct {
+	FetchInterval    string `json:"fetch_interval,omitempty"`
+	FetchTimeout     string `json:"fetch_timeout,omitempty"`
+	TimeSync         *bool  `json:"time_sync,omitempty"`
+	MaxDriftInterval string `json:"max_drift_interval,omitempty"`
+}
+
+// Validate checks if the configuration options are valid.
+func (p DeviceParams) Validate() error {
+	if p.FetchInterval < 0 || (p.FetchInterval > 0 && p.FetchInterval < time.Millisecond) {
+		return fmt.Errorf("%w: fetch interval can't be less than 1ms",
+			status.StatusInvalidArg)
+	}
+
+	if p.FetchTimeout < 0 || (p.FetchTimeout > 0 && p.FetchTimeout < time.Millisecond) {
+		return fmt.Errorf("%w: fetch timeout can't be less than 1ms",
+			status.StatusInvalidArg)
+	}
+
+	if p.TimeSync > DeviceTimeSyncDisable {
+		return fmt.Errorf("%w: unknown time sync mode: %v",
+			status.StatusInvalidArg, p.TimeSync)
+	}
+
+	if p.MaxDriftInterval < 0 ||
+		(p.MaxDriftInterval > 0 && p.MaxDriftInterval < time.Second) {
+		return fmt.Errorf("%w: max drift interval can't be less than 1s",
+			status.StatusInvalidArg)
+	}
+
+	return nil
+}
+
+// MarshalJSON formats intervals as duration strings, e.g. "5m", unset options are omitted.
+func (p DeviceParams) MarshalJSON() ([]byte, error) {
+	var js deviceParamsJSON
+
+	if p.FetchInterval != 0 {
+		js.FetchInterval = p.FetchInterval.String()
+	}
+	if p.FetchTimeout != 0 {
+		js.FetchTimeout = p.FetchTimeout.String()
+	}
+	if p.TimeSync != DeviceTimeSyncDefault {
+		enable := p.TimeSync == DeviceTimeSyncEnable
+		js.TimeSync = &enable
+	}
+	if p.MaxDriftInterval != 0 {
+		js.MaxDriftInterval = p.MaxDriftInterval.String()
+	}
+
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON parses the options formatted with MarshalJSON.
+func (p *DeviceParams) UnmarshalJSON(buf []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.DisallowUnknownFields()
+
+	var js deviceParamsJSON
+	if err := decoder.Decode(&js); err != nil {
+		return err
+	}
+
+	params, err := parseDeviceParams(
+		js.FetchInterval, js.FetchTimeout, js.TimeSync, js.MaxDriftInterval)
+	if err != nil {
+		return err
+	}
+
+	*p = params
+
+	return nil
+}
+
+func parseDeviceParams(
+	fetchInterval string,
+	fetchTimeout string,
+	timeSync *bool,
+	maxDriftInterval string,
+) (DeviceParams, error) {
+	var (
+		params DeviceParams
+		err    error
+	)
+
+	if fetchInterval != "" {
+		if params.FetchInterval, err = time.ParseDuration(fetchInterval); err != nil {
+			return DeviceParams{}, fmt.Errorf("%w: invalid fetch interval: %w",
+				status.StatusInvalidArg, err)
+		}
+	}
+
+	if fetchTimeout != "" {
+		if params.FetchTimeout, err = time.ParseDuration(fetchTimeout); err != nil {
+			return DeviceParams{}, fmt.Errorf("%w: invalid fetch timeout: %w",
+				status.StatusInvalidArg, err)
+		}
+	}
+
+	if timeSync != nil {
+		if *timeSync {
+			params.TimeSync = DeviceTimeSyncEnable
+		} else {
+			params.TimeSync = DeviceTimeSyncDisable
+		}
+	}
+
+	if maxDriftInterval != "" {
+		if params.MaxDriftInterval, err = time.ParseDuration(maxDriftInterval); err != nil {
+			return DeviceParams{}, fmt.Errorf("%w: invalid max drift interval: %w",
+				status.StatusInvalidArg, err)
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,60 @@
+package devstore
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestDeviceParamsJSON(t *testing.T) {
+	params := DeviceParams{
+		FetchInterval:    time.Minute * 5,
+		FetchTimeout:     time.Second,
+		TimeSync:         DeviceTimeSyncEnable,
+		MaxDriftInterval: time.Second * 10,
+	}
+
+	buf, err := json.Marshal(params)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"fetch_interval":"5m0s","fetch_timeout":"1s",`+
+		`"time_sync":true,"max_drift_interval":"10s"}`, string(buf))
+
+	var parsed DeviceParams
+	require.Nil(t, json.Unmarshal(buf, &parsed))
+	require.Equal(t, params, parsed)
+
+	buf, err = json.Marshal(DeviceParams{})
+	require.Nil(t, err)
+	require.Equal(t, `{}`, string(buf))
+
+	parsed = DeviceParams{}
+	require.Nil(t, json.Unmarshal([]byte(`{"time_sync":false}`), &parsed))
+	require.Equal(t, DeviceParams{TimeSync: DeviceTimeSyncDisable}, parsed)
+
+	for _, str := range []string{
+		`{"fetch_interval":"foo"}`,
+		`{"fetch_timeout":"1"}`,
+		`{"max_drift_interval":"bar"}`,
+		`{"foo":"bar"}`,
+	} {
+		require.NotNil(t, json.Unmarshal([]byte(str), &parsed), str)
+	}
+}
+
+func TestDeviceParamsValidate(t *testing.T) {
+	require.Nil(t, DeviceParams{}.Validate())
+
+	for _, params := range []DeviceParams{
+		{FetchInterval: -time.Second},
+		{FetchInterval: time.Microsecond},
+		{FetchTimeout: time.Microsecond},
+		{TimeSync: DeviceTimeSyncDisable + 1},
+		{MaxDriftInterval: time.Millisecond},
+	} {
+		require.ErrorIs(t, params.Validate(), status.StatusInvalidArg)
+	}
+}
@@ -0,0 +1,260 @@
+package devstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var profileLogger = syscore.NewLogger("device-profile")
+
+// DeviceProfile describes how to communicate with the HTTP device of the specific type.
+type DeviceProfile struct {
+	// Type - device type the profile is used for, see Store.Add().
+	Type string `json:"type" yaml:"type"`
+
+	// RegistrationPath - path to fetch the registration data, relative to the device URI.
+	RegistrationPath string `json:"registration_path" yaml:"registration_path"`
+
+	// TelemetryPath - path to fetch the telemetry data, relative to the device URI.
+	TelemetryPath string `json:"telemetry_path" yaml:"telemetry_path"`
+
+	// TimePath - path to get and set the device UNIX time, relative to the device URI.
+	TimePath string `json:"time_path" yaml:"time_path"`
+
+	// Method - HTTP method to fetch the registration and telemetry data.
+	Method string `json:"method" yaml:"method"`
+
+	// Headers - HTTP headers added to each request to the device.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// DisableRegistration to not fetch the registration data, the device ID is taken
+	// from the `device_id` field of the telemetry data then.
+	DisableRegistration bool `json:"disable_registration" yaml:"disable_registration"`
+}
+
+// DefaultDeviceProfile returns the profile for the control-components firmware.
+func DefaultDeviceProfile() DeviceProfile {
+	return DeviceProfile{
+		RegistrationPath: "/registration",
+		TelemetryPath:    "/telemetry",
+		TimePath:         "/system/time",
+		Method:           http.MethodGet,
+	}
+}
+
+// DeviceProfileRegistry holds device profiles by the device type.
+//
+// Remarks:
+//   - Registry is thread-safe.
+type DeviceProfileRegistry struct {
+	mu       sync.Mutex
+	profiles map[string]DeviceProfile
+}
+
+// NewDeviceProfileRegistry is an initialization of DeviceProfileRegistry.
+func NewDeviceProfileRegistry() *DeviceProfileRegistry {
+	return &DeviceProfileRegistry{
+		profiles: make(map[string]DeviceProfile),
+	}
+}
+
+// Add adds the profile to the registry.
+//
+// Remarks:
+//   - Missed profile fields are taken from the default profile.
+//   - Profile with the same type is replaced.
+func (r *DeviceProfileRegistry) Add(profile DeviceProfile) error {
+	profile = mergeDeviceProfile(profile)
+
+	if err := validateDeviceProfile(profile); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[profile.Type] = profile
+
+	return nil
+}
+
+// Get returns the profile for the provided device type.
+//
+// Remarks:
+//   - Default profile is returned if the type isn't registered.
+func (r *DeviceProfileRegistry) Get(typ string) DeviceProfile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if profile, ok := r.profiles[typ]; ok {
+		return profile
+	}
+
+	return DefaultDeviceProfile()
+}
+
+// Types returns sorted types of the registered profiles.
+func (r *DeviceProfileRegistry) Types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var types []string
+	for typ := range r.profiles {
+		types = append(types, typ)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// LoadDir adds profiles from the JSON and YAML files in the provided directory.
+//
+// Remarks:
+//   - Each file contains a single profile.
+//   - Files with other extensions are ignored.
+func (r *DeviceProfileRegistry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("device-profile: failed to read directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		profile, err := readDeviceProfile(path)
+		if err != nil {
+			if err == status.StatusNotSupported {
+				continue
+			}
+
+			return fmt.Errorf("device-profile: failed to load profile: path=%s err=%w",
+				path, err)
+		}
+
+		if err := r.Add(profile); err != nil {
+			return fmt.Errorf("device-profile: invalid profile: path=%s err=%w", path, err)
+		}
+
+		profileLogger.Info("profile loaded", "type", profile.Type, "path", path)
+	}
+
+	return nil
+}
+
+// getDeviceProfile returns the profile for the device type, the default profile is
+// returned if the registry isn't set.
+func getDeviceProfile(profiles *DeviceProfileRegistry, typ string) DeviceProfile {
+	if profiles == nil {
+		return DefaultDeviceProfile()
+	}
+
+	return profiles.Get(typ)
+}
+
+func equalDeviceProfiles(a *DeviceProfileRegistry, b *DeviceProfileRegistry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	types := a.Types()
+	if !reflect.DeepEqual(types, b.Types()) {
+		return false
+	}
+
+	for _, typ := range types {
+		if !reflect.DeepEqual(a.Get(typ), b.Get(typ)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func readDeviceProfile(path string) (DeviceProfile, error) {
+	var unmarshal func([]byte, any) error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		unmarshal = json.Unmarshal
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	default:
+		return DeviceProfile{}, status.StatusNotSupported
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return DeviceProfile{}, err
+	}
+
+	var profile DeviceProfile
+	if err := unmarshal(buf, &profile); err != nil {
+		return DeviceProfile{}, err
+	}
+
+	return profile, nil
+}
+
+func mergeDeviceProfile(profile DeviceProfile) DeviceProfile {
+	def := DefaultDeviceProfile()
+
+	if profile.RegistrationPath == "" {
+		profile.RegistrationPath = def.RegistrationPath
+	}
+	if profile.TelemetryPath == "" {
+		profile.TelemetryPath = def.TelemetryPath
+	}
+	if profile.TimePath == "" {
+		profile.TimePath = def.TimePath
+	}
+	if profile.Method == "" {
+		profile.Method = def.Method
+	}
+
+	profile.Method = strings.ToUpper(profile.Method)
+
+	return profile
+}
+
+func validateDeviceProfile(profile DeviceProfile) error {
+	if profile.Type == "" {
+		return fmt.Errorf("%w: missed device type", status.StatusInvalidArg)
+	}
+
+	for _, path := range []string{
+		profile.RegistrationPath,
+		profile.TelemetryPath,
+		profile.TimePath,
+	} {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("%w: path should start with '/': %s",
+				status.StatusInvalidArg, path)
+		}
+	}
+
+	switch profile.Method {
+	case http.MethodGet, http.MethodPost:
+	default:
+		return fmt.Errorf("%w: unsupported HTTP method: %s",
+			status.StatusInvalidArg, profile.Method)
+	}
+
+	return nil
+}
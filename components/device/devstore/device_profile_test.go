@@ -0,0 +1,105 @@
+package devstore
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestDeviceProfileRegistryGet(t *testing.T) {
+	registry := NewDeviceProfileRegistry()
+	require.Equal(t, DefaultDeviceProfile(), registry.Get("foo"))
+	require.Empty(t, registry.Types())
+
+	require.Nil(t, registry.Add(DeviceProfile{
+		Type:          "foo",
+		TelemetryPath: "/data",
+		Method:        "post",
+	}))
+
+	profile := registry.Get("foo")
+	require.Equal(t, "foo", profile.Type)
+	require.Equal(t, "/data", profile.TelemetryPath)
+	require.Equal(t, "/registration", profile.RegistrationPath)
+	require.Equal(t, "/system/time", profile.TimePath)
+	require.Equal(t, http.MethodPost, profile.Method)
+
+	require.Equal(t, DefaultDeviceProfile(), registry.Get("bar"))
+	require.Equal(t, []string{"foo"}, registry.Types())
+}
+
+func TestDeviceProfileRegistryAddInvalid(t *testing.T) {
+	registry := NewDeviceProfileRegistry()
+
+	for _, profile := range []DeviceProfile{
+		{},
+		{Type: "foo", TelemetryPath: "data"},
+		{Type: "foo", RegistrationPath: "registration"},
+		{Type: "foo", TimePath: "time"},
+		{Type: "foo", Method: http.MethodDelete},
+	} {
+		err := registry.Add(profile)
+		require.True(t, errors.Is(err, status.StatusInvalidArg), "%+v", profile)
+	}
+
+	require.Empty(t, registry.Types())
+}
+
+func TestDeviceProfileRegistryLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "foo.json"), []byte(`{
+	"type": "foo",
+	"telemetry_path": "/api/telemetry",
+	"headers": {"X-Api-Key": "secret"}
+}`), 0o600))
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "bar.yaml"), []byte(`
+type: bar
+telemetry_path: /data
+method: POST
+disable_registration: true
+`), 0o600))
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("foo"), 0o600))
+
+	registry := NewDeviceProfileRegistry()
+	require.Nil(t, registry.LoadDir(dir))
+	require.Equal(t, []string{"bar", "foo"}, registry.Types())
+
+	foo := registry.Get("foo")
+	require.Equal(t, "/api/telemetry", foo.TelemetryPath)
+	require.Equal(t, http.MethodGet, foo.Method)
+	require.Equal(t, map[string]string{"X-Api-Key": "secret"}, foo.Headers)
+	require.False(t, foo.DisableRegistration)
+
+	bar := registry.Get("bar")
+	require.Equal(t, "/data", bar.TelemetryPath)
+	require.Equal(t, http.MethodPost, bar.Method)
+	require.True(t, bar.DisableRegistration)
+}
+
+func TestDeviceProfileRegistryLoadDirInvalid(t *testing.T) {
+	registry := NewDeviceProfileRegistry()
+	require.NotNil(t, registry.LoadDir(filepath.Join(t.TempDir(), "missed")))
+
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "foo.yml"), []byte(`
+type: foo
+telemetry_path: data
+`), 0o600))
+
+	err := registry.LoadDir(dir)
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "foo.yml"), []byte("{"), 0o600))
+	require.NotNil(t, registry.LoadDir(dir))
+
+	require.Empty(t, registry.Types())
+}
@@ -1,14 +1,11 @@
 package devstore
 
-import "github.com/open-control-systems/device-hub/components/system/syscore"
+import "log/slog"
 
 type logErrorHandler struct {
-	uri  string
-	typ  string
-	desc string
+	logger *slog.Logger
 }
 
 func (h *logErrorHandler) HandleError(err error) {
-	syscore.LogErr.Printf("failed to handle device data: uri=%s type=%s desc=%s err=%v",
-		h.uri, h.typ, h.desc, err)
+	h.logger.Error("failed to handle device data", "err", err)
 }
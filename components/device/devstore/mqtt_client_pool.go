@@ -0,0 +1,136 @@
+package devstore
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/mqtt/mqcore"
+)
+
+// mqttClientKey identifies the MQTT broker connection.
+type mqttClientKey struct {
+	brokerURL string
+	username  string
+	password  string
+}
+
+type mqttClientEntry struct {
+	client  *mqcore.Client
+	refs    int
+	started bool
+}
+
+// mqttClientPool shares a single MQTT client among the devices of the same broker.
+//
+// Remarks:
+//   - Devices with different credentials use different clients.
+//   - Client is connected when the first device is started and disconnected when
+//     the last device is stopped.
+type mqttClientPool struct {
+	mu      sync.Mutex
+	clients map[mqttClientKey]*mqttClientEntry
+}
+
+func newMQTTClientPool() *mqttClientPool {
+	return &mqttClientPool{
+		clients: make(map[mqttClientKey]*mqttClientEntry),
+	}
+}
+
+// acquire returns the client for the broker, each call should be paired with release().
+func (p *mqttClientPool) acquire(key mqttClientKey) *mqcore.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.clients[key]
+	if !ok {
+		entry = &mqttClientEntry{
+			client: mqcore.NewClient(mqcore.ClientParams{
+				BrokerURL:            key.brokerURL,
+				ClientID:             fmt.Sprintf("device-hub-%x", rand.Uint64()),
+				Username:             key.username,
+				Password:             key.password,
+				ConnectTimeout:       mqttConnectTimeout,
+				ConnectRetryInterval: mqttConnectRetryInterval,
+			}),
+		}
+
+		p.clients[key] = entry
+	}
+
+	entry.refs++
+
+	return entry.client
+}
+
+// start connects the client to the broker if it isn't connected yet.
+func (p *mqttClientPool) start(key mqttClientKey) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.clients[key]
+	if !ok || entry.started {
+		return nil
+	}
+
+	entry.started = true
+
+	return entry.client.Start()
+}
+
+// release unsubscribes from the topics and disconnects the client from the broker
+// if it isn't used anymore.
+func (p *mqttClientPool) release(key mqttClientKey, topics []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.clients[key]
+	if !ok {
+		return nil
+	}
+
+	entry.refs--
+	if entry.refs > 0 {
+		for _, topic := range topics {
+			entry.client.Unsubscribe(topic)
+		}
+
+		return nil
+	}
+
+	delete(p.clients, key)
+
+	if !entry.started {
+		return nil
+	}
+
+	return entry.client.Stop()
+}
+
+// mqttSubscriber subscribes the device to its topics on the shared MQTT client.
+type mqttSubscriber struct {
+	pool     *mqttClientPool
+	key      mqttClientKey
+	client   *mqcore.Client
+	handlers map[string]mqcore.MessageHandler
+}
+
+// Start subscribes to the device topics and connects the client if required.
+func (s *mqttSubscriber) Start() error {
+	for topic, handler := range s.handlers {
+		s.client.Subscribe(topic, handler)
+	}
+
+	return s.pool.start(s.key)
+}
+
+// Stop unsubscribes from the device topics and releases the client.
+func (s *mqttSubscriber) Stop() error {
+	var topics []string
+	for topic := range s.handlers {
+		topics = append(topics, topic)
+	}
+
+	return s.pool.release(s.key, topics)
+}
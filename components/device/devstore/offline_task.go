@@ -0,0 +1,49 @@
+package devstore
+
+import (
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/syssched"
+)
+
+// offlineTask slows down polling of the offline device.
+//
+// Remarks:
+//   - Each failed run of the offline device doubles the interval between the runs,
+//     up to the maximum interval.
+//   - The interval is reset as soon as the device is online again.
+//   - syssched.ErrTaskSkipped is returned if the run of the offline device isn't due
+//     yet, so the skipped run doesn't reset the scheduler backoff and isn't counted.
+type offlineTask struct {
+	task        syssched.Task
+	clock       syscore.MonotonicClock
+	isOffline   func() bool
+	interval    time.Duration
+	maxInterval time.Duration
+
+	delay     time.Duration
+	nextRunAt time.Time
+}
+
+func (t *offlineTask) Run() error {
+	if !t.isOffline() {
+		t.delay = 0
+
+		return t.task.Run()
+	}
+
+	now := t.clock.Now()
+	if now.Before(t.nextRunAt) {
+		return syssched.ErrTaskSkipped
+	}
+
+	if err := t.task.Run(); err != nil {
+		t.delay = min(max(t.delay*2, t.interval*2), t.maxInterval)
+		t.nextRunAt = now.Add(t.delay)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package devstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/system/syssched"
+)
+
+type testOfflineTaskClock struct {
+	now time.Time
+}
+
+func (c *testOfflineTaskClock) Now() time.Time {
+	return c.now
+}
+
+type testOfflineTaskTask struct {
+	err       error
+	callCount int
+}
+
+func (t *testOfflineTaskTask) Run() error {
+	t.callCount++
+
+	return t.err
+}
+
+func TestOfflineTaskBackoff(t *testing.T) {
+	interval := time.Second
+	maxInterval := time.Second * 5
+
+	clock := &testOfflineTaskClock{}
+	task := &testOfflineTaskTask{err: errors.New("failed")}
+	offline := false
+
+	offlineTask := &offlineTask{
+		task:  task,
+		clock: clock,
+		isOffline: func() bool {
+			return offline
+		},
+		interval:    interval,
+		maxInterval: maxInterval,
+	}
+
+	require.NotNil(t, offlineTask.Run())
+	require.NotNil(t, offlineTask.Run())
+	require.Equal(t, 2, task.callCount)
+
+	offline = true
+
+	// Run at 0s, next run is delayed by 2s, 4s, then by 5s.
+	var runAt []int
+	for n := 0; n < 20; n++ {
+		prev := task.callCount
+
+		_ = offlineTask.Run()
+
+		if task.callCount != prev {
+			runAt = append(runAt, int(clock.now.Sub(time.Time{}).Seconds()))
+		}
+
+		clock.now = clock.now.Add(interval)
+	}
+	require.Equal(t, []int{0, 2, 6, 11, 16}, runAt)
+
+	offline = false
+
+	require.NotNil(t, offlineTask.Run())
+	require.NotNil(t, offlineTask.Run())
+	require.Equal(t, 2+len(runAt)+2, task.callCount)
+}
+
+func TestOfflineTaskSkippedRun(t *testing.T) {
+	clock := &testOfflineTaskClock{}
+	task := &testOfflineTaskTask{err: errors.New("failed")}
+
+	offlineTask := &offlineTask{
+		task:  task,
+		clock: clock,
+		isOffline: func() bool {
+			return true
+		},
+		interval:    time.Second,
+		maxInterval: time.Minute,
+	}
+
+	require.NotNil(t, offlineTask.Run())
+	require.ErrorIs(t, offlineTask.Run(), syssched.ErrTaskSkipped)
+	require.Equal(t, 1, task.callCount)
+
+	task.err = nil
+	clock.now = clock.now.Add(time.Second * 2)
+
+	require.Nil(t, offlineTask.Run())
+	require.Equal(t, 2, task.callCount)
+}
@@ -0,0 +1,106 @@
+package devstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/open-control-systems/device-hub/components/http/htcore"
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// PushHTTPHandler allows devices to send their data over HTTP API.
+type PushHTTPHandler struct {
+	store       PushStore
+	maxBodySize int64
+}
+
+// NewPushHTTPHandler is an initialization of PushHTTPHandler.
+//
+// Parameters:
+//   - store to lookup devices by their identifiers.
+//   - maxBodySize - maximum allowed size of the device data, in bytes.
+func NewPushHTTPHandler(store PushStore, maxBodySize int64) *PushHTTPHandler {
+	return &PushHTTPHandler{
+		store:       store,
+		maxBodySize: maxBodySize,
+	}
+}
+
+// HandleRegistration handles the registration data sent by the device.
+//
+// Remarks:
+//   - Device identifier is expected to be provided in the {id} path wildcard.
+func (h *PushHTTPHandler) HandleRegistration(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, func(handler PushHandler, buf []byte) error {
+		return handler.HandleRegistration(buf)
+	})
+}
+
+// HandleTelemetry handles the telemetry data sent by the device.
+//
+// Remarks:
+//   - Device identifier is expected to be provided in the {id} path wildcard.
+func (h *PushHTTPHandler) HandleTelemetry(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, func(handler PushHandler, buf []byte) error {
+		return handler.HandleTelemetry(buf)
+	})
+}
+
+func (h *PushHTTPHandler) handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(handler PushHandler, buf []byte) error,
+) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "error: missed device `id`", http.StatusBadRequest)
+
+		return
+	}
+
+	handler, err := h.store.GetPushHandler(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to find device with id=%s: %v", id, err),
+			statusCodeFromError(err))
+
+		return
+	}
+
+	buf, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to read data: %v", err),
+			http.StatusBadRequest)
+
+		return
+	}
+
+	if err := fn(handler, buf); err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to handle data for device with id=%s: %v",
+			id, err), statusCodeFromError(err))
+
+		return
+	}
+
+	htcore.WriteText(w, "OK")
+}
+
+func statusCodeFromError(err error) int {
+	switch {
+	case errors.Is(err, status.StatusNoData):
+		return http.StatusNotFound
+	case errors.Is(err, status.StatusInvalidArg):
+		return http.StatusBadRequest
+	case errors.Is(err, status.StatusInvalidState):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,186 @@
+package devstore
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/sysnet"
+)
+
+func newTestPushHTTPHandlerStore(handler devcore.DataHandler) *CacheStore {
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+
+	clock := &testCacheStoreClock{}
+
+	return NewCacheStore(
+		context.Background(),
+		clock,
+		clock,
+		handler,
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+}
+
+func newTestPushHTTPHandlerServer(store PushStore) *httptest.Server {
+	handler := NewPushHTTPHandler(store, 1024)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/device/{id}/registration", handler.HandleRegistration)
+	mux.HandleFunc("/api/v1/device/{id}/telemetry", handler.HandleTelemetry)
+
+	return httptest.NewServer(mux)
+}
+
+func postTestPushHTTPHandlerData(url string, data string) int {
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(data))
+	if err != nil {
+		return -1
+	}
+
+	_ = resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestPushHTTPHandlerHandle(t *testing.T) {
+	handler := newTestCacheStoreDataHandler()
+
+	store := newTestPushHTTPHandlerStore(handler)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add("push://soil-sensor-1", "test-type", "foo-bar-baz", DeviceParams{}))
+
+	server := newTestPushHTTPHandlerServer(store)
+	defer server.Close()
+
+	baseURL := server.URL + "/api/v1/device/soil-sensor-1"
+
+	registrationCh := make(chan int)
+	go func() {
+		registrationCh <- postTestPushHTTPHandlerData(baseURL+"/registration",
+			`{"timestamp":123,"device_id":"0xABCD"}`)
+	}()
+
+	registrationData := devcore.JSON{"timestamp": float64(123), "device_id": "0xABCD"}
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+	require.Equal(t, http.StatusOK, <-registrationCh)
+
+	telemetryCh := make(chan int)
+	go func() {
+		telemetryCh <- postTestPushHTTPHandlerData(baseURL+"/telemetry",
+			`{"timestamp":123,"temperature":123.222}`)
+	}()
+
+	telemetryData := devcore.JSON{"timestamp": float64(123), "temperature": float64(123.222)}
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.Equal(t, http.StatusOK, <-telemetryCh)
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, "0xABCD", descs[0].ID)
+}
+
+func TestPushHTTPHandlerHandleErrors(t *testing.T) {
+	handler := newTestCacheStoreDataHandler()
+
+	store := newTestPushHTTPHandlerStore(handler)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add("push://soil-sensor-1", "test-type", "foo-bar-baz", DeviceParams{}))
+
+	server := newTestPushHTTPHandlerServer(store)
+	defer server.Close()
+
+	baseURL := server.URL + "/api/v1/device"
+
+	require.Equal(t, http.StatusNotFound, postTestPushHTTPHandlerData(
+		baseURL+"/soil-sensor-2/registration", `{"timestamp":123,"device_id":"0xABCD"}`))
+
+	require.Equal(t, http.StatusBadRequest, postTestPushHTTPHandlerData(
+		baseURL+"/soil-sensor-1/registration", `{"timestamp":-1,"device_id":"0xABCD"}`))
+
+	require.Equal(t, http.StatusConflict, postTestPushHTTPHandlerData(
+		baseURL+"/soil-sensor-1/telemetry", `{"timestamp":123,"temperature":123.222}`))
+
+	resp, err := http.Get(baseURL + "/soil-sensor-1/telemetry")
+	require.Nil(t, err)
+	require.Nil(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestPushHTTPHandlerAddInvalidURI(t *testing.T) {
+	store := newTestPushHTTPHandlerStore(newTestCacheStoreDataHandler())
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	for _, uri := range []string{
+		"push://",
+		"push://soil-sensor-1/api/v1",
+		"push://soil-sensor-1?foo=bar",
+	} {
+		require.NotNil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceParams{}), uri)
+	}
+
+	_, err := store.GetPushHandler("soil-sensor-1")
+	require.Equal(t, status.StatusNoData, err)
+}
+
+func TestPushHTTPHandlerStatus(t *testing.T) {
+	store := newTestPushHTTPHandlerStore(newTestCacheStoreDataHandler())
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Start())
+	require.Nil(t, store.Add("push://soil-sensor-1", "test-type", "foo-bar-baz", DeviceParams{}))
+
+	server := newTestPushHTTPHandlerServer(store)
+	defer server.Close()
+
+	baseURL := server.URL + "/api/v1/device/soil-sensor-1"
+
+	descs := store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, StoreStatus{}, descs[0].Status)
+
+	for n := 0; n < 2; n++ {
+		require.Equal(t, http.StatusConflict, postTestPushHTTPHandlerData(
+			baseURL+"/telemetry", `{"timestamp":123,"temperature":123.222}`))
+	}
+
+	descs = store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, 2, descs[0].Status.ConsecutiveFailures)
+	require.NotEmpty(t, descs[0].Status.LastError)
+	require.NotEmpty(t, descs[0].Status.LastErrorAt)
+	require.Empty(t, descs[0].Status.LastSuccessAt)
+
+	require.Equal(t, http.StatusOK, postTestPushHTTPHandlerData(
+		baseURL+"/registration", `{"timestamp":123,"device_id":"0xABCD"}`))
+
+	descs = store.GetDesc()
+	require.Equal(t, 1, len(descs))
+	require.Equal(t, 0, descs[0].Status.ConsecutiveFailures)
+	require.NotEmpty(t, descs[0].Status.LastError)
+	require.NotEmpty(t, descs[0].Status.LastSuccessAt)
+}
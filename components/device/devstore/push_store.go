@@ -0,0 +1,22 @@
+package devstore
+
+// PushHandler handles the data sent by the device itself.
+type PushHandler interface {
+	// HandleRegistration handles the registration data sent by the device.
+	HandleRegistration(buf []byte) error
+
+	// HandleTelemetry handles the telemetry data sent by the device.
+	HandleTelemetry(buf []byte) error
+}
+
+// PushStore to lookup devices that send the data to the hub on their own.
+type PushStore interface {
+	// GetPushHandler returns the handler for the device associated with the provided ID.
+	//
+	// Parameters:
+	//   - id - device identifier, see Store.Add() for push device URI format.
+	//
+	// Remarks:
+	//   - status.StatusNoData is returned if the device doesn't exist.
+	GetPushHandler(id string) (PushHandler, error)
+}
@@ -0,0 +1,117 @@
+package devstore
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/system/syssched"
+)
+
+type statusTracker struct {
+	timeStats *devcore.TimeStatsTracker
+
+	mu     sync.Mutex
+	status StoreStatus
+}
+
+func newStatusTracker(holder *devcore.IDHolder) *statusTracker {
+	return &statusTracker{
+		timeStats: devcore.NewTimeStatsTracker(holder),
+	}
+}
+
+func (s *statusTracker) get() StoreStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Time = s.timeStats.Get()
+
+	return status
+}
+
+func (s *statusTracker) set(status StoreStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+	s.timeStats.Set(status.Time)
+}
+
+func (s *statusTracker) handleResult(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Format(time.RFC1123)
+
+	if err != nil {
+		s.status.LastErrorAt = now
+		s.status.LastError = err.Error()
+		s.status.ConsecutiveFailures++
+	} else {
+		s.status.LastSuccessAt = now
+		s.status.ConsecutiveFailures = 0
+	}
+}
+
+func (s *statusTracker) handleTimeSync(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.LastTimeSyncAt = time.Now().Format(time.RFC1123)
+
+	if err != nil {
+		s.status.LastTimeSyncError = err.Error()
+	} else {
+		s.status.LastTimeSyncError = ""
+	}
+
+	s.timeStats.HandleSync(err)
+}
+
+type statusTask struct {
+	task    syssched.Task
+	tracker *statusTracker
+}
+
+func (t *statusTask) Run() error {
+	err := t.task.Run()
+	if !errors.Is(err, syssched.ErrTaskSkipped) {
+		t.tracker.handleResult(err)
+	}
+
+	return err
+}
+
+type statusTimeSynchronizer struct {
+	synchronizer devcore.TimeSynchronizer
+	tracker      *statusTracker
+}
+
+func (s *statusTimeSynchronizer) SyncTime() error {
+	err := s.synchronizer.SyncTime()
+	s.tracker.handleTimeSync(err)
+
+	return err
+}
+
+type statusPushHandler struct {
+	handler PushHandler
+	tracker *statusTracker
+}
+
+func (h *statusPushHandler) HandleRegistration(buf []byte) error {
+	err := h.handler.HandleRegistration(buf)
+	h.tracker.handleResult(err)
+
+	return err
+}
+
+func (h *statusPushHandler) HandleTelemetry(buf []byte) error {
+	err := h.handler.HandleTelemetry(buf)
+	h.tracker.handleResult(err)
+
+	return err
+}
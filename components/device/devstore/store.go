@@ -1,14 +1,71 @@
 package devstore
 
-import "errors"
+import (
+	"errors"
+	"net/url"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+)
 
 // StoreItem is a description of a single device.
 type StoreItem struct {
-	URI       string `json:"uri"`
-	Type      string `json:"type"`
-	Desc      string `json:"desc"`
-	ID        string `json:"id"`
-	CreatedAt string `json:"created_at"`
+	URI       string       `json:"uri"`
+	Type      string       `json:"type"`
+	Desc      string       `json:"desc"`
+	ID        string       `json:"id"`
+	CreatedAt string       `json:"created_at"`
+	Params    DeviceParams `json:"params"`
+	Status    StoreStatus  `json:"status"`
+}
+
+// StoreStatus is a runtime status of a single device.
+//
+// Remarks:
+//   - Time is formatted according to RFC1123, empty if the event hasn't happened yet.
+type StoreStatus struct {
+	// LastSuccessAt - when the device data was handled successfully last time.
+	LastSuccessAt string `json:"last_success_at"`
+
+	// LastErrorAt - when the device data failed to be handled last time.
+	LastErrorAt string `json:"last_error_at"`
+
+	// LastError - the most recent error of the device data handling.
+	LastError string `json:"last_error"`
+
+	// ConsecutiveFailures - number of failures since the last successful handling.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastTimeSyncAt - when the device UNIX time synchronization was attempted last time.
+	LastTimeSyncAt string `json:"last_time_sync_at"`
+
+	// LastTimeSyncError - the result of the last UNIX time synchronization, empty on success.
+	LastTimeSyncError string `json:"last_time_sync_error"`
+
+	// InactiveDeadline - when the device is considered inactive if no data is received.
+	InactiveDeadline string `json:"inactive_deadline"`
+
+	// State - "online" or "offline", empty if the device inactivity isn't monitored.
+	State string `json:"state"`
+
+	// Time - device clock drift and UNIX time synchronization statistics.
+	Time devcore.TimeStats `json:"time"`
+}
+
+// redactStoreItem hides the device URI credentials, e.g. the MQTT broker password,
+// so they aren't exposed via API.
+func redactStoreItem(item StoreItem) StoreItem {
+	item.URI = redactURI(item.URI)
+
+	return item
+}
+
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return uri
+	}
+
+	return u.Redacted()
 }
 
 // ErrDeviceExist is returned if the device already exists in the store.
@@ -22,6 +79,7 @@ type Store interface {
 	//   - uri - device URI, how device can be reached.
 	//   - typ - device type, to distinguish one device from another.
 	//   - desc - human readable device description.
+	//   - params - per-device configuration options.
 	//
 	// Remarks:
 	//   - uri should be unique.
@@ -30,6 +88,8 @@ type Store interface {
 	// URI examples:
 	//   - http://bonsai-growlab.local:12345/api/v1. mDNS HTTP API
 	//   - http://192.168.4.1:17321. Static IP address.
+	//   - push://soil-sensor-1. Device sends data to the hub on its own.
+	//   - mqtt://192.168.1.2:1883/devices/soil-sensor-1. MQTT broker and topic prefix.
 	//
 	// Typ examples:
 	//  - bonsai-growlab
@@ -40,7 +100,7 @@ type Store interface {
 	// Desc examples:
 	//   - room-plant-zamioculcas
 	//   - living-room-light-bulb
-	Add(uri string, typ string, desc string) error
+	Add(uri string, typ string, desc string, params DeviceParams) error
 
 	// Remove removes the device associated with the provided URI.
 	//
@@ -48,6 +108,19 @@ type Store interface {
 	//   - uri - unique device identifier.
 	Remove(uri string) error
 
+	// Update updates the type and description of the device.
+	//
+	// Parameters:
+	//   - uri - unique device identifier.
+	//   - typ - new device type.
+	//   - desc - new human readable device description.
+	//
+	// Remarks:
+	//   - Device data processing is restarted if the type change affects it, e.g. the
+	//     HTTP device profile is changed, the device ID is preserved.
+	//   - status.StatusNoData is returned if the device doesn't exist.
+	Update(uri string, typ string, desc string) error
+
 	// GetDesc returns descriptions for registered devices.
 	GetDesc() []StoreItem
 }
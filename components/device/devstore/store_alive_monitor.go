@@ -1,40 +1,93 @@
 package devstore
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/open-control-systems/device-hub/components/status"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 	"github.com/open-control-systems/device-hub/components/system/syssched"
 )
 
+var aliveMonitorLogger = syscore.NewLogger("store-alive-monitor")
+
+// StoreAliveMonitorPolicy defines how the inactive devices are handled.
+type StoreAliveMonitorPolicy int
+
+const (
+	// StoreAliveMonitorPolicyRemove removes the inactive device.
+	StoreAliveMonitorPolicyRemove StoreAliveMonitorPolicy = iota
+
+	// StoreAliveMonitorPolicyOffline marks the inactive device as offline, the device
+	// is removed only if it remains inactive during the retention interval.
+	StoreAliveMonitorPolicyOffline
+)
+
+// ParseStoreAliveMonitorPolicy converts the string representation of the policy.
+func ParseStoreAliveMonitorPolicy(str string) (StoreAliveMonitorPolicy, error) {
+	switch str {
+	case "remove":
+		return StoreAliveMonitorPolicyRemove, nil
+	case "offline":
+		return StoreAliveMonitorPolicyOffline, nil
+	default:
+		return StoreAliveMonitorPolicyRemove, fmt.Errorf(
+			"%w: unknown inactive device policy: %s", status.StatusInvalidArg, str)
+	}
+}
+
+// StoreAliveMonitorParams represents various configuration options for the monitor.
+type StoreAliveMonitorParams struct {
+	// MaxInactiveInterval is maximum allowed interval for a device to be inactive.
+	MaxInactiveInterval time.Duration
+
+	// Policy defines how the inactive devices are handled.
+	Policy StoreAliveMonitorPolicy
+
+	// RetentionInterval is how long the device is allowed to be inactive before
+	// it's removed, used only with StoreAliveMonitorPolicyOffline.
+	RetentionInterval time.Duration
+}
+
+const (
+	storeStateOnline  = "online"
+	storeStateOffline = "offline"
+)
+
 // StoreAliveMonitor monitors the operational health of devices. If a device isn't
-// active for a period of time, it is considered to be inactive and is removed.
+// active for a period of time, it is considered to be inactive and is either removed
+// or marked as offline, depending on the configured policy.
 type StoreAliveMonitor struct {
-	maxInactiveInterval time.Duration
-	clock               syscore.MonotonicClock
-	store               Store
+	clock  syscore.MonotonicClock
+	store  Store
+	params StoreAliveMonitorParams
 
 	mu      sync.Mutex
-	devices map[string]time.Time
+	devices map[string]*storeAliveDevice
+}
+
+type storeAliveDevice struct {
+	updateTime time.Time
+	offline    bool
 }
 
 // NewStoreAliveMonitor is an initialization of StoreAliveMonitor.
 //
 // Parameters:
-//   - store to automatically add/remove devices.
 //   - clock to measure time for how long device is inactive.
-//   - maxInactiveInterval is maximum allowed interval for a device to be inactive.
+//   - store to automatically add/remove devices.
+//   - params - various configuration options for the monitor.
 func NewStoreAliveMonitor(
 	clock syscore.MonotonicClock,
 	store Store,
-	maxInactiveInterval time.Duration,
+	params StoreAliveMonitorParams,
 ) *StoreAliveMonitor {
 	monitor := &StoreAliveMonitor{
-		maxInactiveInterval: maxInactiveInterval,
-		store:               store,
-		clock:               clock,
-		devices:             make(map[string]time.Time),
+		clock:   clock,
+		store:   store,
+		params:  params,
+		devices: make(map[string]*storeAliveDevice),
 	}
 
 	monitor.restoreDevices()
@@ -50,16 +103,62 @@ func (m *StoreAliveMonitor) Monitor(uri string) syssched.AliveNotifier {
 	}
 }
 
+// IsOffline returns true if the device associated with the provided URI is offline.
+func (m *StoreAliveMonitor) IsOffline(uri string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[uri]
+
+	return ok && device.offline
+}
+
+// HandleOffline marks the device associated with the provided URI as offline.
+//
+// Remarks:
+//   - The device is online again once its data is received.
+//   - The device is still removed according to the configured policy, once it
+//     becomes inactive.
+func (m *StoreAliveMonitor) HandleOffline(uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[uri]
+	if !ok || device.offline {
+		return
+	}
+
+	aliveMonitorLogger.Warn("device offline", "uri", uri, "reason", "left network")
+
+	device.offline = true
+}
+
+// SetParams changes the monitor configuration.
+//
+// Remarks:
+//   - Monitored devices are kept, the new configuration is applied on the next Run().
+func (m *StoreAliveMonitor) SetParams(params StoreAliveMonitorParams) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.params = params
+}
+
 // Add adds the device to the underlying store and starts monitoring its well-being.
-func (m *StoreAliveMonitor) Add(uri string, typ string, desc string) error {
+func (m *StoreAliveMonitor) Add(
+	uri string,
+	typ string,
+	desc string,
+	params DeviceParams,
+) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if err := m.store.Add(uri, typ, desc); err != nil {
+	if err := m.store.Add(uri, typ, desc, params); err != nil {
 		return err
 	}
 
-	m.devices[uri] = m.clock.Now()
+	m.devices[uri] = &storeAliveDevice{updateTime: m.clock.Now()}
 
 	return nil
 }
@@ -78,14 +177,49 @@ func (m *StoreAliveMonitor) Remove(uri string) error {
 	return nil
 }
 
+// Update updates the device associated with the provided URI.
+//
+// Remarks:
+//   - Device inactivity state isn't changed.
+func (m *StoreAliveMonitor) Update(uri string, typ string, desc string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.store.Update(uri, typ, desc)
+}
+
 // GetDesc returns descriptions for registered devices.
+//
+// Remarks:
+//   - Inactivity deadline and state are set for the monitored devices.
 func (m *StoreAliveMonitor) GetDesc() []StoreItem {
-	return m.store.GetDesc()
+	items := m.store.GetDesc()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for n := range items {
+		device, ok := m.devices[items[n].URI]
+		if !ok {
+			continue
+		}
+
+		items[n].Status.InactiveDeadline =
+			device.updateTime.Add(m.params.MaxInactiveInterval).Format(time.RFC1123)
+
+		if device.offline {
+			items[n].Status.State = storeStateOffline
+		} else {
+			items[n].Status.State = storeStateOnline
+		}
+	}
+
+	return items
 }
 
 // HandleError handles Run() error.
 func (*StoreAliveMonitor) HandleError(err error) {
-	syscore.LogErr.Printf("failed to verify inactive devices: %v", err)
+	aliveMonitorLogger.Error("failed to verify inactive devices", "err", err)
 }
 
 // Run verifies if added devices are still alive.
@@ -93,16 +227,28 @@ func (m *StoreAliveMonitor) Run() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for uri, updateTime := range m.devices {
+	for uri, device := range m.devices {
 		now := m.clock.Now()
 
-		diff := now.Sub(updateTime)
-		if diff < m.maxInactiveInterval {
+		diff := now.Sub(device.updateTime)
+		if diff < m.params.MaxInactiveInterval {
+			continue
+		}
+
+		if m.params.Policy == StoreAliveMonitorPolicyOffline &&
+			diff < m.params.RetentionInterval {
+			if !device.offline {
+				aliveMonitorLogger.Warn("device offline", "uri", uri,
+					"cur_inactive", diff, "max_inactive", m.params.MaxInactiveInterval)
+
+				device.offline = true
+			}
+
 			continue
 		}
 
-		syscore.LogWrn.Printf("removing inactive device:"+
-			" uri=%s cur_inactive=%s max_inactive=%s", uri, diff, m.maxInactiveInterval)
+		aliveMonitorLogger.Warn("removing inactive device", "uri", uri,
+			"cur_inactive", diff, "max_inactive", m.params.MaxInactiveInterval)
 
 		if err := m.store.Remove(uri); err != nil {
 			return err
@@ -115,8 +261,8 @@ func (m *StoreAliveMonitor) Run() error {
 }
 
 func (m *StoreAliveMonitor) restoreDevices() {
-	for _, desc := range m.GetDesc() {
-		m.devices[desc.URI] = m.clock.Now()
+	for _, desc := range m.store.GetDesc() {
+		m.devices[desc.URI] = &storeAliveDevice{updateTime: m.clock.Now()}
 	}
 }
 
@@ -124,7 +270,19 @@ func (m *StoreAliveMonitor) notifyAlive(uri string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.devices[uri] = m.clock.Now()
+	device, ok := m.devices[uri]
+	if !ok {
+		device = &storeAliveDevice{}
+		m.devices[uri] = device
+	}
+
+	if device.offline {
+		aliveMonitorLogger.Info("device online", "uri", uri)
+
+		device.offline = false
+	}
+
+	device.updateTime = m.clock.Now()
 }
 
 type storeAliveNotifier struct {
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
 )
 
 type testStoreAliveMonitorClock struct {
@@ -33,7 +35,12 @@ func newTestStoreAliveMonitorStore() *testStoreAliveMonitorStore {
 	}
 }
 
-func (s *testStoreAliveMonitorStore) Add(uri string, typ string, desc string) error {
+func (s *testStoreAliveMonitorStore) Add(
+	uri string,
+	typ string,
+	desc string,
+	_ DeviceParams,
+) error {
 	s.addCallCount++
 
 	if s.err != nil {
@@ -60,6 +67,23 @@ func (s *testStoreAliveMonitorStore) Remove(uri string) error {
 	return nil
 }
 
+func (s *testStoreAliveMonitorStore) Update(uri string, typ string, desc string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, ok := s.devices[uri]; !ok {
+		return status.StatusNoData
+	}
+
+	s.devices[uri] = testStoreAliveMonitorDevice{
+		typ:  typ,
+		desc: desc,
+	}
+
+	return nil
+}
+
 func (s *testStoreAliveMonitorStore) GetDesc() []StoreItem {
 	var ret []StoreItem
 
@@ -97,9 +121,11 @@ func TestStoreAliveMonitorVerifyInactivityOK(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 	store := newTestStoreAliveMonitorStore()
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+	})
 
-	require.Nil(t, monitor.Add(uri, typ, desc))
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceParams{}))
 	require.Nil(t, monitor.Run())
 
 	require.Equal(t, 1, store.count())
@@ -127,12 +153,14 @@ func TestStoreAliveMonitorVerifyInactivityTimeout(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 	store := newTestStoreAliveMonitorStore()
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+	})
 
 	notifier := monitor.Monitor(uri)
 	require.NotNil(t, notifier)
 
-	require.Nil(t, monitor.Add(uri, typ, desc))
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceParams{}))
 	require.Nil(t, monitor.Run())
 
 	require.Equal(t, 1, store.count())
@@ -181,9 +209,11 @@ func TestStoreAliveMonitorVerifyInactivityOnRestore(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 
 	store := newTestStoreAliveMonitorStore()
-	require.Nil(t, store.Add(uri, typ, desc))
+	require.Nil(t, store.Add(uri, typ, desc, DeviceParams{}))
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+	})
 
 	clock.now = clock.now.Add(inactiveInterval)
 
@@ -197,3 +227,161 @@ func TestStoreAliveMonitorVerifyInactivityOnRestore(t *testing.T) {
 	require.Equal(t, 1, store.removeCallCount)
 	require.False(t, store.checkDevice(uri, typ, desc))
 }
+
+func TestStoreAliveMonitorInactiveDeadline(t *testing.T) {
+	inactiveInterval := time.Minute
+
+	uri := "http://bonsai-growlab.local/api/v1"
+
+	clock := &testStoreAliveMonitorClock{now: time.Unix(1000, 0)}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+	})
+	require.Nil(t, monitor.Add(uri, "test-type", "home-plant", DeviceParams{}))
+
+	items := monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, time.Unix(1060, 0).Format(time.RFC1123), items[0].Status.InactiveDeadline)
+
+	clock.now = clock.now.Add(inactiveInterval / 2)
+	monitor.Monitor(uri).NotifyAlive()
+
+	items = monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, time.Unix(1090, 0).Format(time.RFC1123), items[0].Status.InactiveDeadline)
+}
+
+func TestStoreAliveMonitorOfflinePolicy(t *testing.T) {
+	inactiveInterval := time.Minute
+	retentionInterval := time.Hour
+
+	uri := "http://bonsai-growlab.local/api/v1"
+	desc := "home-plant"
+	typ := "test-type"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+		Policy:              StoreAliveMonitorPolicyOffline,
+		RetentionInterval:   retentionInterval,
+	})
+
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceParams{}))
+	require.False(t, monitor.IsOffline(uri))
+
+	items := monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "online", items[0].Status.State)
+
+	clock.now = clock.now.Add(inactiveInterval)
+	require.Nil(t, monitor.Run())
+
+	require.True(t, monitor.IsOffline(uri))
+	require.Equal(t, 0, store.removeCallCount)
+	require.True(t, store.checkDevice(uri, typ, desc))
+
+	items = monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "offline", items[0].Status.State)
+
+	monitor.Monitor(uri).NotifyAlive()
+	require.False(t, monitor.IsOffline(uri))
+
+	clock.now = clock.now.Add(inactiveInterval)
+	require.Nil(t, monitor.Run())
+	require.True(t, monitor.IsOffline(uri))
+
+	clock.now = clock.now.Add(retentionInterval - inactiveInterval - time.Second)
+	require.Nil(t, monitor.Run())
+	require.True(t, monitor.IsOffline(uri))
+	require.Equal(t, 0, store.removeCallCount)
+
+	clock.now = clock.now.Add(time.Second)
+	require.Nil(t, monitor.Run())
+	require.False(t, monitor.IsOffline(uri))
+	require.Equal(t, 1, store.removeCallCount)
+	require.False(t, store.checkDevice(uri, typ, desc))
+}
+
+func TestStoreAliveMonitorSetParams(t *testing.T) {
+	inactiveInterval := time.Minute
+
+	uri := "http://bonsai-growlab.local/api/v1"
+	desc := "home-plant"
+	typ := "test-type"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+	})
+
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceParams{}))
+
+	monitor.SetParams(StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval * 2,
+	})
+
+	clock.now = clock.now.Add(inactiveInterval)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, 0, store.removeCallCount)
+	require.True(t, store.checkDevice(uri, typ, desc))
+
+	clock.now = clock.now.Add(inactiveInterval)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, 1, store.removeCallCount)
+	require.False(t, store.checkDevice(uri, typ, desc))
+}
+
+func TestStoreAliveMonitorParsePolicy(t *testing.T) {
+	policy, err := ParseStoreAliveMonitorPolicy("remove")
+	require.Nil(t, err)
+	require.Equal(t, StoreAliveMonitorPolicyRemove, policy)
+
+	policy, err = ParseStoreAliveMonitorPolicy("offline")
+	require.Nil(t, err)
+	require.Equal(t, StoreAliveMonitorPolicyOffline, policy)
+
+	_, err = ParseStoreAliveMonitorPolicy("foo")
+	require.NotNil(t, err)
+}
+
+func TestStoreAliveMonitorHandleOffline(t *testing.T) {
+	inactiveInterval := time.Minute
+
+	uri := "http://bonsai-growlab.local/api/v1"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveInterval,
+		Policy:              StoreAliveMonitorPolicyOffline,
+		RetentionInterval:   time.Hour,
+	})
+
+	// Unknown device is ignored.
+	monitor.HandleOffline(uri)
+	require.False(t, monitor.IsOffline(uri))
+
+	require.Nil(t, monitor.Add(uri, "test-type", "home-plant", DeviceParams{}))
+
+	monitor.HandleOffline(uri)
+	require.True(t, monitor.IsOffline(uri))
+
+	items := monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "offline", items[0].Status.State)
+
+	require.Nil(t, monitor.Run())
+	require.True(t, monitor.IsOffline(uri))
+	require.Equal(t, 0, store.removeCallCount)
+
+	monitor.Monitor(uri).NotifyAlive()
+	require.False(t, monitor.IsOffline(uri))
+}
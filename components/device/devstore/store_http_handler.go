@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/open-control-systems/device-hub/components/http/htcore"
 )
@@ -50,7 +52,14 @@ func (h *StoreHTTPHandler) HandleAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.Add(uri, typ, desc); err != nil {
+	params, err := parseDeviceParamsQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := h.store.Add(uri, typ, desc, params); err != nil {
 		http.Error(w, fmt.Sprintf("error: failed to add device with uri=%s: %v", uri, err),
 			http.StatusBadRequest)
 
@@ -93,7 +102,12 @@ func (h *StoreHTTPHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	buf, err := json.Marshal(h.store.GetDesc())
+	items := h.store.GetDesc()
+	for n := range items {
+		items[n] = redactStoreItem(items[n])
+	}
+
+	buf, err := json.Marshal(items)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
 			http.StatusInternalServerError)
@@ -103,3 +117,60 @@ func (h *StoreHTTPHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 
 	htcore.WriteJSON(w, buf)
 }
+
+// HandleGet returns the description of the device associated with the provided URI.
+func (h *StoreHTTPHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		http.Error(w, "error: missed `uri` query parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	for _, item := range h.store.GetDesc() {
+		if item.URI != uri && redactURI(item.URI) != uri {
+			continue
+		}
+
+		buf, err := json.Marshal(redactStoreItem(item))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
+				http.StatusInternalServerError)
+
+			return
+		}
+
+		htcore.WriteJSON(w, buf)
+
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("error: device with uri=%s not found", uri),
+		http.StatusNotFound)
+}
+
+func parseDeviceParamsQuery(query url.Values) (DeviceParams, error) {
+	var timeSync *bool
+
+	if str := query.Get("time_sync"); str != "" {
+		enable, err := strconv.ParseBool(str)
+		if err != nil {
+			return DeviceParams{}, fmt.Errorf("invalid `time_sync` query parameter: %w", err)
+		}
+
+		timeSync = &enable
+	}
+
+	return parseDeviceParams(
+		query.Get("fetch_interval"),
+		query.Get("fetch_timeout"),
+		timeSync,
+		query.Get("max_drift_interval"),
+	)
+}
@@ -0,0 +1,271 @@
+package devstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/open-control-systems/device-hub/components/http/htcore"
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// StoreHTTPHandlerV2 allows to manage devices over RESTful HTTP API.
+//
+// Remarks:
+//   - Device is identified either by its ID or by its URI, the URI should be URL-encoded.
+//   - Errors are returned as JSON: {"error": "..."}.
+type StoreHTTPHandlerV2 struct {
+	store       Store
+	maxBodySize int64
+}
+
+// StoreCreateRequest is a JSON body to create the device.
+type StoreCreateRequest struct {
+	URI    string       `json:"uri"`
+	Type   string       `json:"type"`
+	Desc   string       `json:"desc"`
+	Params DeviceParams `json:"params"`
+}
+
+// StoreUpdateRequest is a JSON body to update the device, missed fields aren't changed.
+type StoreUpdateRequest struct {
+	Type *string `json:"type"`
+	Desc *string `json:"desc"`
+}
+
+// NewStoreHTTPHandlerV2 is an initialization of StoreHTTPHandlerV2.
+//
+// Parameters:
+//   - store to manage devices.
+//   - maxBodySize - maximum allowed size of the request body, in bytes.
+func NewStoreHTTPHandlerV2(store Store, maxBodySize int64) *StoreHTTPHandlerV2 {
+	return &StoreHTTPHandlerV2{
+		store:       store,
+		maxBodySize: maxBodySize,
+	}
+}
+
+// HandleDevices handles requests for the device collection:
+//   - GET returns the description of all added devices.
+//   - POST adds the device described in the JSON body.
+func (h *StoreHTTPHandlerV2) HandleDevices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		items := []StoreItem{}
+		for _, item := range h.store.GetDesc() {
+			items = append(items, redactStoreItem(item))
+		}
+
+		h.writeJSON(w, http.StatusOK, items)
+	case http.MethodPost:
+		h.handleCreate(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "unsupported method")
+	}
+}
+
+// HandleDevice handles requests for the single device identified by the {id} path value:
+//   - GET returns the device description.
+//   - PATCH updates the device type and description.
+//   - DELETE removes the device.
+func (h *StoreHTTPHandlerV2) HandleDevice(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPatch, http.MethodDelete:
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "unsupported method")
+
+		return
+	}
+
+	id := r.PathValue("id")
+
+	item, ok := h.findItem(id)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("device with id=%s not found", id))
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, redactStoreItem(item))
+	case http.MethodPatch:
+		h.handleUpdate(w, r, item)
+	case http.MethodDelete:
+		if err := h.store.Remove(item.URI); err != nil {
+			h.writeStoreError(w, fmt.Sprintf("failed to remove device with uri=%s",
+				redactURI(item.URI)), err)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *StoreHTTPHandlerV2) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req StoreCreateRequest
+	if !h.readJSON(w, r, &req) {
+		return
+	}
+
+	if req.URI == "" {
+		h.writeError(w, http.StatusBadRequest, "missed `uri` field")
+
+		return
+	}
+	if req.Type == "" {
+		h.writeError(w, http.StatusBadRequest, "missed `type` field")
+
+		return
+	}
+	if req.Desc == "" {
+		h.writeError(w, http.StatusBadRequest, "missed `desc` field")
+
+		return
+	}
+
+	if err := h.store.Add(req.URI, req.Type, req.Desc, req.Params); err != nil {
+		h.writeStoreError(w, fmt.Sprintf("failed to add device with uri=%s",
+			redactURI(req.URI)), err)
+
+		return
+	}
+
+	item, ok := h.findItem(req.URI)
+	if !ok {
+		item = StoreItem{URI: req.URI, Type: req.Type, Desc: req.Desc}
+	}
+
+	w.Header().Set("Location", r.URL.Path+"/"+url.PathEscape(redactURI(req.URI)))
+	h.writeJSON(w, http.StatusCreated, redactStoreItem(item))
+}
+
+func (h *StoreHTTPHandlerV2) handleUpdate(
+	w http.ResponseWriter,
+	r *http.Request,
+	item StoreItem,
+) {
+	var req StoreUpdateRequest
+	if !h.readJSON(w, r, &req) {
+		return
+	}
+
+	typ := item.Type
+	if req.Type != nil {
+		typ = *req.Type
+	}
+	if typ == "" {
+		h.writeError(w, http.StatusBadRequest, "`type` field can't be empty")
+
+		return
+	}
+
+	desc := item.Desc
+	if req.Desc != nil {
+		desc = *req.Desc
+	}
+	if desc == "" {
+		h.writeError(w, http.StatusBadRequest, "`desc` field can't be empty")
+
+		return
+	}
+
+	if err := h.store.Update(item.URI, typ, desc); err != nil {
+		h.writeStoreError(w, fmt.Sprintf("failed to update device with uri=%s",
+			redactURI(item.URI)), err)
+
+		return
+	}
+
+	if updated, ok := h.findItem(item.URI); ok {
+		item = updated
+	}
+
+	h.writeJSON(w, http.StatusOK, redactStoreItem(item))
+}
+
+func (h *StoreHTTPHandlerV2) findItem(id string) (StoreItem, bool) {
+	items := h.store.GetDesc()
+
+	// The device can be identified by the URI returned by the API as well.
+	for _, item := range items {
+		if item.URI == id || redactURI(item.URI) == id {
+			return item, true
+		}
+	}
+
+	for _, item := range items {
+		if item.ID != "" && item.ID == id {
+			return item, true
+		}
+	}
+
+	return StoreItem{}, false
+}
+
+func (h *StoreHTTPHandlerV2) readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+		} else {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func (h *StoreHTTPHandlerV2) writeJSON(w http.ResponseWriter, code int, v any) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSONStatus(w, code, buf)
+}
+
+func (h *StoreHTTPHandlerV2) writeStoreError(w http.ResponseWriter, msg string, err error) {
+	h.writeError(w, storeStatusCodeFromError(err), fmt.Sprintf("%s: %v", msg, err))
+}
+
+func (*StoreHTTPHandlerV2) writeError(w http.ResponseWriter, code int, msg string) {
+	buf, err := json.Marshal(struct {
+		Error string `json:"error"`
+	}{
+		Error: msg,
+	})
+	if err != nil {
+		http.Error(w, "error: "+msg, code)
+
+		return
+	}
+
+	htcore.WriteJSONStatus(w, code, buf)
+}
+
+func storeStatusCodeFromError(err error) int {
+	switch {
+	case errors.Is(err, ErrDeviceExist):
+		return http.StatusConflict
+	case errors.Is(err, status.StatusNoData):
+		return http.StatusNotFound
+	case errors.Is(err, status.StatusNotSupported):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, status.StatusInvalidArg):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
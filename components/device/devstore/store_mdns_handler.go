@@ -11,7 +11,8 @@ import (
 
 // StoreMdnsHandler notifies store about new devices discovered over local network.
 type StoreMdnsHandler struct {
-	store Store
+	store   Store
+	offline OfflineHandler
 }
 
 // NewStoreMdnsHandler is an initialization of StoreMdnsHandler.
@@ -22,6 +23,15 @@ func NewStoreMdnsHandler(store Store) *StoreMdnsHandler {
 	return &StoreMdnsHandler{store: store}
 }
 
+// SetOfflineHandler sets the handler to mark the devices which left local network
+// as offline.
+//
+// Remarks:
+//   - Should be called before HandleServiceRemove().
+func (h *StoreMdnsHandler) SetOfflineHandler(handler OfflineHandler) {
+	h.offline = handler
+}
+
 // HandleService handles mDNS service discovered over local network.
 func (h *StoreMdnsHandler) HandleService(service *sysmdns.Service) error {
 	if ignoreService(service) {
@@ -61,6 +71,32 @@ func (h *StoreMdnsHandler) HandleService(service *sysmdns.Service) error {
 	return h.handleAutodiscovery(mode, uri, typ, desc)
 }
 
+// HandleServiceRemove marks the auto-discovered device as offline when its mDNS
+// service leaves local network.
+func (h *StoreMdnsHandler) HandleServiceRemove(service *sysmdns.Service) error {
+	if h.offline == nil || ignoreService(service) {
+		return nil
+	}
+
+	records, err := parseTxtRecords(service.TxtRecords)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := records["autodiscovery_mode"]; !ok {
+		return nil
+	}
+
+	uri, ok := records["autodiscovery_uri"]
+	if !ok {
+		return nil
+	}
+
+	h.offline.HandleOffline(uri)
+
+	return nil
+}
+
 func (h *StoreMdnsHandler) handleAutodiscovery(
 	mode autodiscoveryMode,
 	uri string,
@@ -76,7 +112,7 @@ func (h *StoreMdnsHandler) handleAutodiscovery(
 }
 
 func (h *StoreMdnsHandler) handleAutodiscoveryAdd(uri string, typ string, desc string) error {
-	err := h.store.Add(uri, typ, desc)
+	err := h.store.Add(uri, typ, desc, DeviceParams{})
 	if err != nil && err != ErrDeviceExist {
 		return err
 	}
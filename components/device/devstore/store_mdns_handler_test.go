@@ -27,7 +27,12 @@ func newTestStoreMdnsHandlerStore() *testStoreMdnsHandlerStore {
 	}
 }
 
-func (s *testStoreMdnsHandlerStore) Add(uri string, typ string, desc string) error {
+func (s *testStoreMdnsHandlerStore) Add(
+	uri string,
+	typ string,
+	desc string,
+	_ DeviceParams,
+) error {
 	if s.err != nil {
 		return s.err
 	}
@@ -59,6 +64,23 @@ func (s *testStoreMdnsHandlerStore) Remove(uri string) error {
 	return nil
 }
 
+func (s *testStoreMdnsHandlerStore) Update(uri string, typ string, desc string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, ok := s.devices[uri]; !ok {
+		return status.StatusNoData
+	}
+
+	s.devices[uri] = testStoreMdnsHandlerDevice{
+		typ:  typ,
+		desc: desc,
+	}
+
+	return nil
+}
+
 func (*testStoreMdnsHandlerStore) GetDesc() []StoreItem {
 	return []StoreItem{}
 }
@@ -224,3 +246,51 @@ func TestStoreMdnsHandlerAddMultipleTimes(t *testing.T) {
 	require.True(t,
 		store.checkDevice("http://bonsai-growlab.local/api/v1", "test-type", "home-plant"))
 }
+
+type testStoreMdnsHandlerOfflineHandler struct {
+	uris []string
+}
+
+func (h *testStoreMdnsHandlerOfflineHandler) HandleOffline(uri string) {
+	h.uris = append(h.uris, uri)
+}
+
+func TestStoreMdnsHandlerServiceRemove(t *testing.T) {
+	store := newTestStoreMdnsHandlerStore()
+	mdnsHandler := NewStoreMdnsHandler(store)
+
+	uri := "http://bonsai-growlab.local/api/v1"
+
+	service := &sysmdns.Service{
+		TxtRecords: []string{
+			"autodiscovery_mode=1",
+			"autodiscovery_uri=" + uri,
+			"autodiscovery_desc=home-plant",
+			"autodiscovery_type=bonsai-growlab",
+		},
+	}
+
+	require.Nil(t, mdnsHandler.HandleService(service))
+	require.True(t, store.checkDevice(uri, "bonsai-growlab", "home-plant"))
+
+	// No offline handler.
+	require.Nil(t, mdnsHandler.HandleServiceRemove(service))
+
+	offlineHandler := &testStoreMdnsHandlerOfflineHandler{}
+	mdnsHandler.SetOfflineHandler(offlineHandler)
+
+	require.Nil(t, mdnsHandler.HandleServiceRemove(&sysmdns.Service{
+		TxtRecords: []string{"foo=bar"},
+	}))
+	require.Nil(t, mdnsHandler.HandleServiceRemove(&sysmdns.Service{
+		TxtRecords: []string{"autodiscovery_mode=1"},
+	}))
+	require.Empty(t, offlineHandler.uris)
+
+	require.Nil(t, mdnsHandler.HandleServiceRemove(service))
+	require.Equal(t, []string{uri}, offlineHandler.uris)
+
+	// Device is kept in the store.
+	require.Equal(t, 1, store.count())
+	require.Equal(t, 0, store.removeCallCount)
+}
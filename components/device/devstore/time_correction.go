@@ -0,0 +1,39 @@
+package devstore
+
+import (
+	"fmt"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// TimeCorrection defines how the device data with invalid timestamp is handled.
+type TimeCorrection int
+
+const (
+	// TimeCorrectionNone rejects the data with invalid timestamp.
+	TimeCorrectionNone TimeCorrection = iota
+
+	// TimeCorrectionReceive replaces the invalid timestamp with the local UNIX time
+	// when the data is received.
+	TimeCorrectionReceive
+
+	// TimeCorrectionOffset adjusts the invalid timestamp by the offset between local
+	// and device UNIX time, the local UNIX time is used if the offset can't be
+	// estimated.
+	TimeCorrectionOffset
+)
+
+// ParseTimeCorrection converts the string representation of the time correction.
+func ParseTimeCorrection(str string) (TimeCorrection, error) {
+	switch str {
+	case "none":
+		return TimeCorrectionNone, nil
+	case "receive":
+		return TimeCorrectionReceive, nil
+	case "offset":
+		return TimeCorrectionOffset, nil
+	default:
+		return TimeCorrectionNone, fmt.Errorf(
+			"%w: unknown time correction: %s", status.StatusInvalidArg, str)
+	}
+}
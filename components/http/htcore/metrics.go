@@ -0,0 +1,50 @@
+package htcore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var (
+	fetchDuration = promauto.With(sysmetrics.Registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "http",
+			Name:      "fetch_duration_seconds",
+			Help:      "Duration of the HTTP requests to the device.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"device_id", "url"},
+	)
+
+	fetchTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "http",
+			Name:      "fetch_total",
+			Help:      "Number of HTTP requests to the device by status code.",
+		},
+		[]string{"device_id", "url", "code"},
+	)
+)
+
+// metricsUnknownDeviceID labels the metrics until the device ID is known.
+const metricsUnknownDeviceID = "unknown"
+
+// DeleteFetchMetrics removes the HTTP fetch metrics of the URL for all device IDs.
+//
+// Remarks:
+//   - Should be called when the device is removed, to not expose stale series.
+func DeleteFetchMetrics(url string) {
+	fetchDuration.DeletePartialMatch(prometheus.Labels{"url": url})
+	fetchTotal.DeletePartialMatch(prometheus.Labels{"url": url})
+}
+
+func deleteDeviceFetchMetrics(deviceID string, url string) {
+	labels := prometheus.Labels{"device_id": deviceID, "url": url}
+
+	fetchDuration.DeletePartialMatch(labels)
+	fetchTotal.DeletePartialMatch(labels)
+}
@@ -19,10 +19,15 @@ func WriteText(w http.ResponseWriter, text string) {
 
 // WriteJSON writes JSON to HTTP response.
 func WriteJSON(w http.ResponseWriter, buf []byte) {
+	WriteJSONStatus(w, http.StatusOK, buf)
+}
+
+// WriteJSONStatus writes JSON to HTTP response with the provided status code.
+func WriteJSONStatus(w http.ResponseWriter, code int, buf []byte) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Content-Length", strconv.Itoa(len(buf)))
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(code)
 
 	if _, err := w.Write(buf); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -8,6 +8,8 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 )
 
+var serverLogger = syscore.NewLogger("http-server")
+
 // Server is a wrapper for http.Server.
 type Server struct {
 	server http.Server
@@ -94,6 +96,6 @@ func (s *Server) run() {
 	defer close(s.doneCh)
 
 	if err := s.server.Serve(s.ln); err != nil && err != http.ErrServerClosed {
-		syscore.LogErr.Printf("failed to serve connection: %v", err)
+		serverLogger.Error("failed to serve connection", "err", err)
 	}
 }
@@ -6,9 +6,23 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
 )
 
+// minTimestampMilli is the lower bound of the valid UNIX time in milliseconds.
+//
+// Remarks:
+//   - Firmware without millisecond support ignores the unit and replies with the UNIX
+//     time in seconds, which is far below this value until the year 5138.
+const minTimestampMilli = 100_000_000_000
+
 // SystemClock handles the UNIX time for the HTTP resource.
+//
+// Remarks:
+//   - UNIX time in milliseconds is requested with "unit=ms" and set with "value_ms"
+//     query parameters. Older firmware ignores both parameters and treats the request
+//     as a read of the UNIX time in seconds.
 type SystemClock struct {
 	url     string
 	timeout time.Duration
@@ -39,6 +53,42 @@ func NewSystemClock(
 
 // SetTimestamp sets the UNIX time for a remoute resource.
 func (c *SystemClock) SetTimestamp(timestamp int64) error {
+	return c.set("value", timestamp)
+}
+
+// GetTimestamp gets the UNIX time from a remote resource.
+func (c *SystemClock) GetTimestamp() (int64, error) {
+	return c.get("")
+}
+
+// SetTimestampMilli sets the UNIX time in milliseconds for a remote resource.
+//
+// Remarks:
+//   - Older firmware ignores the request, read the UNIX time back to verify it was set.
+func (c *SystemClock) SetTimestampMilli(timestamp int64) error {
+	return c.set("value_ms", timestamp)
+}
+
+// GetTimestampMilli gets the UNIX time in milliseconds from a remote resource.
+//
+// Remarks:
+//   - status.StatusNotSupported is returned if the resource replies with the UNIX time
+//     in seconds.
+func (c *SystemClock) GetTimestampMilli() (int64, error) {
+	timestamp, err := c.get("ms")
+	if err != nil {
+		return -1, err
+	}
+
+	if timestamp >= 0 && timestamp < minTimestampMilli {
+		return -1, fmt.Errorf("%w: http-system-clock: UNIX time isn't in milliseconds: %v",
+			status.StatusNotSupported, timestamp)
+	}
+
+	return timestamp, nil
+}
+
+func (c *SystemClock) set(param string, timestamp int64) error {
 	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
 	defer cancel()
 
@@ -48,7 +98,7 @@ func (c *SystemClock) SetTimestamp(timestamp int64) error {
 	}
 
 	query := req.URL.Query()
-	query.Set("value", strconv.FormatInt(timestamp, 10))
+	query.Set(param, strconv.FormatInt(timestamp, 10))
 	req.URL.RawQuery = query.Encode()
 
 	resp, _, err := c.client.Do(req)
@@ -63,8 +113,7 @@ func (c *SystemClock) SetTimestamp(timestamp int64) error {
 	return nil
 }
 
-// GetTimestamp gets the UNIX time from a remote resource.
-func (c *SystemClock) GetTimestamp() (int64, error) {
+func (c *SystemClock) get(unit string) (int64, error) {
 	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
 	defer cancel()
 
@@ -73,6 +122,12 @@ func (c *SystemClock) GetTimestamp() (int64, error) {
 		return -1, err
 	}
 
+	if unit != "" {
+		query := req.URL.Query()
+		query.Set("unit", unit)
+		req.URL.RawQuery = query.Encode()
+	}
+
 	resp, body, err := c.client.Do(req)
 	if err != nil {
 		return -1, err
@@ -4,15 +4,26 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 )
 
+// DeviceIDProvider provides the device ID to label the metrics.
+type DeviceIDProvider interface {
+	// Get returns the device ID, empty if it isn't known yet.
+	Get() string
+}
+
 // URLFetcher sends requests to the configured HTTP endpoint.
 type URLFetcher struct {
 	ctx     context.Context
+	method  string
 	url     string
 	timeout time.Duration
 	client  *HTTPClient
+
+	idProvider   DeviceIDProvider
+	lastDeviceID string
 }
 
 // NewURLFetcher initializes URL Fetcher.
@@ -20,40 +31,81 @@ type URLFetcher struct {
 // Parameters:
 //   - ctx to pass to the HTTP request.
 //   - client to perform an actual HTTP request.
+//   - method - HTTP method, e.g. GET.
 //   - url - HTTP URL.
 //   - timeout - HTTP request timeout.
 func NewURLFetcher(
 	ctx context.Context,
 	client *HTTPClient,
+	method string,
 	url string,
 	timeout time.Duration,
 ) *URLFetcher {
 	return &URLFetcher{
 		ctx:     ctx,
+		method:  method,
 		url:     url,
 		timeout: timeout,
 		client:  client,
 	}
 }
 
+// SetDeviceIDProvider sets the provider of the device ID to label the fetch metrics.
+//
+// Remarks:
+//   - Should be called before Fetch().
+//   - Metrics are labeled with the "unknown" device ID until the ID is known.
+func (f *URLFetcher) SetDeviceIDProvider(provider DeviceIDProvider) {
+	f.idProvider = provider
+}
+
 // Fetch data from the HTTP resource.
 func (f *URLFetcher) Fetch() ([]byte, error) {
 	ctx, cancel := context.WithTimeout(f.ctx, f.timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", f.url, nil)
+	req, err := http.NewRequestWithContext(ctx, f.method, f.url, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	deviceID := f.getDeviceID()
+
+	start := time.Now()
 	resp, body, err := f.client.Do(req)
+	fetchDuration.WithLabelValues(deviceID, f.url).Observe(time.Since(start).Seconds())
+
 	if err != nil {
+		fetchTotal.WithLabelValues(deviceID, f.url, "error").Inc()
+
 		return nil, err
 	}
 
+	fetchTotal.WithLabelValues(deviceID, f.url, strconv.Itoa(resp.StatusCode)).Inc()
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("url-fetcher: failed to fetch data: code=%v", resp.StatusCode)
 	}
 
 	return body, nil
 }
+
+// getDeviceID returns the metrics label, the URL series of the previous device ID
+// are removed if the device ID is changed.
+func (f *URLFetcher) getDeviceID() string {
+	deviceID := metricsUnknownDeviceID
+
+	if f.idProvider != nil {
+		if id := f.idProvider.Get(); id != "" {
+			deviceID = id
+		}
+	}
+
+	if f.lastDeviceID != "" && f.lastDeviceID != deviceID {
+		deleteDeviceFetchMetrics(f.lastDeviceID, f.url)
+	}
+
+	f.lastDeviceID = deviceID
+
+	return deviceID
+}
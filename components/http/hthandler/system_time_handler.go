@@ -1,6 +1,7 @@
 package hthandler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,12 +9,27 @@ import (
 
 	"github.com/open-control-systems/device-hub/components/http/htcore"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/systime"
 )
 
+// SNTPStatusProvider provides the results of the local clock synchronization.
+type SNTPStatusProvider interface {
+	// GetStatus returns the results of the local clock synchronization.
+	GetStatus() systime.SNTPClientStatus
+}
+
 // SystemTimeHandler handles the UNIX time configuration over HTTP.
+//
+// Remarks:
+//   - UNIX time in milliseconds is handled with "unit=ms" and "value_ms" query
+//     parameters if the clock implements syscore.PreciseSystemClock, otherwise
+//     these parameters are ignored.
+//   - Results of the local clock synchronization are returned with "status=sntp"
+//     query parameter if the SNTP status provider is set.
 type SystemTimeHandler struct {
 	clock      syscore.SystemClock
 	startPoint time.Time
+	sntp       SNTPStatusProvider
 }
 
 // NewSystemTimeHandler creates an HTTP handler for the UNIX time configuration.
@@ -27,6 +43,14 @@ func NewSystemTimeHandler(
 	}
 }
 
+// SetSNTPStatusProvider sets the provider of the local clock synchronization results.
+//
+// Remarks:
+//   - Should be called before ServeHTTP().
+func (h *SystemTimeHandler) SetSNTPStatusProvider(provider SNTPStatusProvider) {
+	h.sntp = provider
+}
+
 // ServeHTTP implements an HTTP endpoint logic.
 func (h *SystemTimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -35,6 +59,18 @@ func (h *SystemTimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("status") == "sntp" {
+		h.handleSNTPStatus(w)
+
+		return
+	}
+
+	if clock, ok := h.clock.(syscore.PreciseSystemClock); ok {
+		if h.handleMilli(w, r, clock) {
+			return
+		}
+	}
+
 	response := ""
 
 	str := r.URL.Query().Get("value")
@@ -72,3 +108,69 @@ func (h *SystemTimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	htcore.WriteText(w, response)
 }
+
+func (h *SystemTimeHandler) handleSNTPStatus(w http.ResponseWriter) {
+	if h.sntp == nil {
+		http.Error(w, "error: SNTP client is disabled", http.StatusNotFound)
+
+		return
+	}
+
+	buf, err := json.Marshal(h.sntp.GetStatus())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to format SNTP status: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
+
+func (h *SystemTimeHandler) handleMilli(
+	w http.ResponseWriter,
+	r *http.Request,
+	clock syscore.PreciseSystemClock,
+) bool {
+	query := r.URL.Query()
+
+	if str := query.Get("value_ms"); str != "" {
+		timestamp, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return true
+		}
+
+		if err := clock.SetTimestampMilli(timestamp); err != nil {
+			http.Error(w, fmt.Sprintf("failed to set UNIX time: %v", err),
+				http.StatusInternalServerError)
+
+			return true
+		}
+
+		htcore.WriteText(w, "OK")
+
+		return true
+	}
+
+	if query.Get("unit") == "ms" {
+		timestamp, err := clock.GetTimestampMilli()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to get UNIX time: %v", err),
+				http.StatusInternalServerError)
+
+			return true
+		}
+
+		if timestamp < h.startPoint.UnixMilli() {
+			timestamp = -1
+		}
+
+		htcore.WriteText(w, strconv.FormatInt(timestamp, 10))
+
+		return true
+	}
+
+	return false
+}
@@ -2,6 +2,8 @@ package hthandler
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -11,6 +13,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-control-systems/device-hub/components/http/htcore"
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/systime"
 )
 
 type testClock struct {
@@ -44,6 +48,26 @@ func (c *testClock) GetTimestamp() (int64, error) {
 	return c.timestamp, nil
 }
 
+type testPreciseClock struct {
+	testClock
+}
+
+func (c *testPreciseClock) SetTimestampMilli(timestamp int64) error {
+	return c.SetTimestamp(timestamp)
+}
+
+func (c *testPreciseClock) GetTimestampMilli() (int64, error) {
+	return c.GetTimestamp()
+}
+
+type testSNTPStatusProvider struct {
+	status systime.SNTPClientStatus
+}
+
+func (p *testSNTPStatusProvider) GetStatus() systime.SNTPClientStatus {
+	return p.status
+}
+
 func newTestClock(timestamp int64) *testClock {
 	return &testClock{
 		timestamp: timestamp,
@@ -84,3 +108,91 @@ func TestSystemTimeHandlerSetGetTimestamp(t *testing.T) {
 	require.NotEqual(t, currTimestamp, recvTimestamp)
 	require.Equal(t, newTimestamp, recvTimestamp)
 }
+
+func TestSystemTimeHandlerSetGetTimestampMilli(t *testing.T) {
+	startPoint := time.Unix(1733215816, 0)
+	currTimestamp := startPoint.UnixMilli() - 1
+
+	testClock := &testPreciseClock{testClock: testClock{timestamp: currTimestamp}}
+	handler := NewSystemTimeHandler(testClock, startPoint)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clock := htcore.NewSystemClock(context.Background(), htcore.NewDefaultClient(),
+		server.URL, time.Second*10)
+
+	recvTimestamp, err := clock.GetTimestampMilli()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), recvTimestamp)
+
+	newTimestamp := startPoint.UnixMilli() + 123
+	require.Nil(t, clock.SetTimestampMilli(newTimestamp))
+
+	recvTimestamp, err = clock.GetTimestampMilli()
+	require.Nil(t, err)
+	require.Equal(t, newTimestamp, recvTimestamp)
+}
+
+func TestSystemTimeHandlerGetTimestampMilliNotSupported(t *testing.T) {
+	startPoint := time.Unix(1733215816, 0)
+	currTimestamp := startPoint.Unix() + 1
+
+	testClock := newTestClock(currTimestamp)
+	handler := NewSystemTimeHandler(testClock, startPoint)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	clock := htcore.NewSystemClock(context.Background(), htcore.NewDefaultClient(),
+		server.URL, time.Second*10)
+
+	recvTimestamp, err := clock.GetTimestampMilli()
+	require.ErrorIs(t, err, status.StatusNotSupported)
+	require.Equal(t, int64(-1), recvTimestamp)
+
+	// Millisecond UNIX time is ignored by the clock without millisecond precision.
+	require.Nil(t, clock.SetTimestampMilli(startPoint.UnixMilli()*2))
+
+	recvTimestamp, err = clock.GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, currTimestamp, recvTimestamp)
+}
+
+func TestSystemTimeHandlerSNTPStatus(t *testing.T) {
+	handler := NewSystemTimeHandler(newTestClock(123), time.Unix(0, 0))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	url := server.URL + "?status=sntp"
+
+	resp, err := http.Get(url)
+	require.Nil(t, err)
+	require.Nil(t, resp.Body.Close())
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	provider := &testSNTPStatusProvider{
+		status: systime.SNTPClientStatus{
+			Server:     "pool.ntp.org",
+			OffsetMs:   -42,
+			DelayMs:    12,
+			Adjustment: "slew",
+			Accepted:   2,
+			Rejected:   1,
+		},
+	}
+	handler.SetSNTPStatusProvider(provider)
+
+	resp, err = http.Get(url)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	buf, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+
+	var status systime.SNTPClientStatus
+	require.Nil(t, json.Unmarshal(buf, &status))
+	require.Equal(t, provider.status, status)
+}
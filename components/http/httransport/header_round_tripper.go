@@ -0,0 +1,32 @@
+package httransport
+
+import "net/http"
+
+// HeaderRoundTripper adds the configured headers to each HTTP request.
+type HeaderRoundTripper struct {
+	header http.Header
+	rt     http.RoundTripper
+}
+
+// NewHeaderRoundTripper initializes round tripper.
+//
+// Parameters:
+//   - header - HTTP headers to add to the request, existing headers are overwritten.
+//   - rt to perform an actual HTTP transaction.
+func NewHeaderRoundTripper(header http.Header, rt http.RoundTripper) *HeaderRoundTripper {
+	return &HeaderRoundTripper{
+		header: header,
+		rt:     rt,
+	}
+}
+
+// RoundTrip adds headers and perform HTTP transaction.
+func (r *HeaderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+
+	return r.rt.RoundTrip(req)
+}
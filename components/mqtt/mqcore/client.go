@@ -0,0 +1,195 @@
+package mqcore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// ClientParams represents various configuration options for MQTT client.
+type ClientParams struct {
+	// BrokerURL is the MQTT broker address, e.g. tcp://192.168.1.2:1883.
+	BrokerURL string
+
+	// ClientID is the unique client identifier on the MQTT broker.
+	ClientID string
+
+	// Username is the MQTT broker user, empty for anonymous access.
+	Username string
+
+	// Password is the MQTT broker password.
+	Password string
+
+	// ConnectTimeout - how long to wait for the connection to be established.
+	ConnectTimeout time.Duration
+
+	// ConnectRetryInterval - how long to wait between the connection attempts.
+	ConnectRetryInterval time.Duration
+
+	// Logger to log the connection events, the component logger is used if unset.
+	Logger *slog.Logger
+}
+
+// Client maintains the connection to the MQTT broker.
+//
+// Remarks:
+//   - Connection is re-established automatically if it's lost.
+//   - Topics are re-subscribed automatically when the connection is re-established.
+//   - Client can be shared by multiple devices of the same broker, each device
+//     subscribes to its own topics.
+//
+// References:
+//   - https://github.com/eclipse/paho.mqtt.golang
+type Client struct {
+	client mqtt.Client
+	params ClientParams
+
+	mu       sync.Mutex
+	handlers map[string]MessageHandler
+}
+
+// NewClient is an initialization of Client.
+//
+// Remarks:
+//   - The connection isn't established until Start() is called.
+func NewClient(params ClientParams) *Client {
+	if params.Logger == nil {
+		params.Logger = syscore.NewLogger("mqtt-client")
+	}
+
+	params.Logger = params.Logger.With("broker", params.BrokerURL)
+
+	c := &Client{
+		params:   params,
+		handlers: make(map[string]MessageHandler),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(params.BrokerURL).
+		SetClientID(params.ClientID).
+		SetUsername(params.Username).
+		SetPassword(params.Password).
+		SetCleanSession(true).
+		SetOrderMatters(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(params.ConnectTimeout).
+		SetConnectRetryInterval(params.ConnectRetryInterval).
+		SetOnConnectHandler(c.handleConnect).
+		SetConnectionLostHandler(c.handleConnectionLost)
+
+	c.client = mqtt.NewClient(opts)
+
+	return c
+}
+
+// Subscribe registers the handler for the MQTT topic.
+//
+// Remarks:
+//   - Topic is subscribed immediately if the connection is established, otherwise
+//     it's subscribed once the connection is established.
+//   - Handler is replaced if the topic is already subscribed.
+func (c *Client) Subscribe(topic string, handler MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[topic] = handler
+
+	if c.client.IsConnectionOpen() {
+		c.subscribe(c.client, topic)
+	}
+}
+
+// Unsubscribe unregisters the handler for the MQTT topic.
+func (c *Client) Unsubscribe(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.handlers, topic)
+
+	if c.client.IsConnectionOpen() {
+		go c.waitToken("unsubscribe from", topic, c.client.Unsubscribe(topic))
+	}
+}
+
+// Publish sends the payload to the MQTT topic and waits until it's delivered to the broker.
+func (c *Client) Publish(ctx context.Context, topic string, buf []byte) error {
+	token := c.client.Publish(topic, 1, false, buf)
+
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("mqtt-client: failed to publish: topic=%s err=%w", topic, err)
+		}
+
+		return nil
+
+	case <-ctx.Done():
+		return status.StatusTimeout
+	}
+}
+
+// Start starts establishing the connection to the MQTT broker in the background.
+func (c *Client) Start() error {
+	c.client.Connect()
+
+	return nil
+}
+
+// Stop closes the connection to the MQTT broker.
+func (c *Client) Stop() error {
+	c.client.Disconnect(uint(time.Second.Milliseconds()))
+
+	return nil
+}
+
+func (c *Client) handleConnect(client mqtt.Client) {
+	c.params.Logger.Info("connected to MQTT broker")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for topic := range c.handlers {
+		c.subscribe(client, topic)
+	}
+}
+
+// subscribe subscribes to the topic, the handler is looked up on each message, so
+// it can be replaced without re-subscribing.
+func (c *Client) subscribe(client mqtt.Client, topic string) {
+	token := client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		c.mu.Lock()
+		handler, ok := c.handlers[topic]
+		c.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		if err := handler.HandleMessage(msg.Payload()); err != nil {
+			c.params.Logger.Error("failed to handle MQTT message",
+				"topic", topic, "err", err)
+		}
+	})
+
+	go c.waitToken("subscribe to", topic, token)
+}
+
+func (c *Client) waitToken(action string, topic string, token mqtt.Token) {
+	<-token.Done()
+
+	if err := token.Error(); err != nil {
+		c.params.Logger.Error("failed to "+action+" MQTT topic", "topic", topic, "err", err)
+	}
+}
+
+func (c *Client) handleConnectionLost(_ mqtt.Client, err error) {
+	c.params.Logger.Warn("MQTT connection lost", "err", err)
+}
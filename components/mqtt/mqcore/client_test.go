@@ -0,0 +1,185 @@
+package mqcore
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBroker(t *testing.T) (*mqttserver.Server, string) {
+	server := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.Nil(t, server.AddHook(new(auth.AllowHook), nil))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	require.Nil(t, server.AddListener(listeners.NewNet("test", ln)))
+	require.Nil(t, server.Serve())
+
+	return server, "tcp://" + ln.Addr().String()
+}
+
+func newTestClient(brokerURL string) *Client {
+	return NewClient(ClientParams{
+		BrokerURL:            brokerURL,
+		ClientID:             "device-hub-test",
+		ConnectTimeout:       time.Second,
+		ConnectRetryInterval: time.Millisecond * 100,
+	})
+}
+
+func TestClientSubscribe(t *testing.T) {
+	broker, brokerURL := newTestBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	recvCh := make(chan []byte, 1)
+
+	client := newTestClient(brokerURL)
+	client.Subscribe("foo/telemetry", FuncMessageHandler(func(buf []byte) error {
+		select {
+		case recvCh <- buf:
+		default:
+		}
+
+		return nil
+	}))
+
+	require.Nil(t, client.Start())
+	defer func() {
+		require.Nil(t, client.Stop())
+	}()
+
+	ticker := time.NewTicker(time.Millisecond * 50)
+	defer ticker.Stop()
+
+	for {
+		require.Nil(t, broker.Publish("foo/telemetry", []byte("bar"), false, 0))
+
+		select {
+		case buf := <-recvCh:
+			require.Equal(t, []byte("bar"), buf)
+
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func TestClientSubscribeAfterStart(t *testing.T) {
+	broker, brokerURL := newTestBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	client := newTestClient(brokerURL)
+	require.Nil(t, client.Start())
+	defer func() {
+		require.Nil(t, client.Stop())
+	}()
+
+	require.Eventually(t, func() bool {
+		return client.client.IsConnectionOpen()
+	}, time.Second*5, time.Millisecond*10)
+
+	recvCh := make(chan []byte, 1)
+
+	client.Subscribe("foo/telemetry", FuncMessageHandler(func(buf []byte) error {
+		select {
+		case recvCh <- buf:
+		default:
+		}
+
+		return nil
+	}))
+
+	require.Eventually(t, func() bool {
+		require.Nil(t, broker.Publish("foo/telemetry", []byte("bar"), false, 0))
+
+		select {
+		case buf := <-recvCh:
+			require.Equal(t, []byte("bar"), buf)
+
+			return true
+
+		case <-time.After(time.Millisecond * 50):
+			return false
+		}
+	}, time.Second*5, time.Millisecond*10)
+
+	client.Unsubscribe("foo/telemetry")
+
+	// Messages aren't handled once the topic is unsubscribed.
+	require.Nil(t, broker.Publish("foo/telemetry", []byte("baz"), false, 0))
+	require.Never(t, func() bool {
+		select {
+		case buf := <-recvCh:
+			return string(buf) == "baz"
+		default:
+			return false
+		}
+	}, time.Millisecond*200, time.Millisecond*10)
+}
+
+func TestSystemClockSetTimestamp(t *testing.T) {
+	broker, brokerURL := newTestBroker(t)
+	defer func() {
+		require.Nil(t, broker.Close())
+	}()
+
+	recvCh := make(chan []byte, 1)
+
+	require.Nil(t, broker.Subscribe("foo/system/time/set", 1,
+		func(_ *mqttserver.Client, _ packets.Subscription, pk packets.Packet) {
+			select {
+			case recvCh <- pk.Payload:
+			default:
+			}
+		}))
+
+	client := newTestClient(brokerURL)
+	require.Nil(t, client.Start())
+	defer func() {
+		require.Nil(t, client.Stop())
+	}()
+
+	clock := NewSystemClock(context.Background(), client, "foo/system/time/set", time.Second)
+
+	timestamp, err := clock.GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), timestamp)
+
+	clock.HandleTimestamp(42)
+
+	timestamp, err = clock.GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(42), timestamp)
+
+	for clock.SetTimestamp(123) != nil {
+		time.Sleep(time.Millisecond * 50)
+	}
+
+	require.Equal(t, []byte("123"), <-recvCh)
+}
+
+func TestSystemClockSetTimestampNoConnection(t *testing.T) {
+	client := newTestClient("tcp://127.0.0.1:1")
+
+	clock := NewSystemClock(context.Background(), client, "foo/system/time/set",
+		time.Millisecond*100)
+	require.NotNil(t, clock.SetTimestamp(123))
+}
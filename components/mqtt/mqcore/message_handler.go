@@ -0,0 +1,15 @@
+package mqcore
+
+// MessageHandler handles the payload of the received MQTT message.
+type MessageHandler interface {
+	// HandleMessage handles the MQTT message payload.
+	HandleMessage(buf []byte) error
+}
+
+// FuncMessageHandler is a function type that implements the MessageHandler interface.
+type FuncMessageHandler func(buf []byte) error
+
+// HandleMessage calls the function to handle the MQTT message payload.
+func (h FuncMessageHandler) HandleMessage(buf []byte) error {
+	return h(buf)
+}
@@ -0,0 +1,68 @@
+package mqcore
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SystemClock handles the UNIX time for the MQTT device.
+type SystemClock struct {
+	ctx     context.Context
+	client  *Client
+	topic   string
+	timeout time.Duration
+
+	mu        sync.Mutex
+	timestamp int64
+}
+
+// NewSystemClock initializes SystemClock.
+//
+// Parameters:
+//   - ctx - parent context.
+//   - client to publish the UNIX time.
+//   - topic - MQTT topic to publish the UNIX time to.
+//   - timeout - how long to wait for the UNIX time to be published.
+func NewSystemClock(
+	ctx context.Context,
+	client *Client,
+	topic string,
+	timeout time.Duration,
+) *SystemClock {
+	return &SystemClock{
+		ctx:       ctx,
+		client:    client,
+		topic:     topic,
+		timeout:   timeout,
+		timestamp: -1,
+	}
+}
+
+// SetTimestamp publishes the UNIX time for the MQTT device.
+func (c *SystemClock) SetTimestamp(timestamp int64) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	return c.client.Publish(ctx, c.topic, []byte(strconv.FormatInt(timestamp, 10)))
+}
+
+// GetTimestamp returns the most recent UNIX time reported by the MQTT device.
+//
+// Remarks:
+//   - -1 is returned if the device hasn't reported its UNIX time yet.
+func (c *SystemClock) GetTimestamp() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timestamp, nil
+}
+
+// HandleTimestamp records the UNIX time reported by the MQTT device.
+func (c *SystemClock) HandleTimestamp(timestamp int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timestamp = timestamp
+}
@@ -0,0 +1,125 @@
+package stcore
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var fakeHwclockLogger = syscore.NewLogger("fake-hwclock")
+
+// fakeHwclockKey is the database key of the saved local UNIX time.
+const fakeHwclockKey = "timestamp"
+
+// FakeHardwareClock periodically saves the local UNIX time to the database and moves
+// the local UNIX time forward on startup, like fake-hwclock(8) on machines without RTC.
+//
+// Remarks:
+//   - The local UNIX time is never moved back.
+//   - The local UNIX time is moved forward to the last persisted device data
+//     timestamp as well, once it's restored from the storage.
+type FakeHardwareClock struct {
+	local   syscore.SystemClock
+	storage syscore.SystemClock
+	db      DB
+
+	storageRestored bool
+}
+
+// NewFakeHardwareClock is an initialization of FakeHardwareClock.
+//
+// Parameters:
+//   - local to get/set the local UNIX time.
+//   - storage to get the last persisted device data timestamp, an error is expected
+//     until the timestamp is restored.
+//   - db to save the local UNIX time.
+func NewFakeHardwareClock(
+	local syscore.SystemClock,
+	storage syscore.SystemClock,
+	db DB,
+) *FakeHardwareClock {
+	return &FakeHardwareClock{
+		local:   local,
+		storage: storage,
+		db:      db,
+	}
+}
+
+// Restore moves the local UNIX time forward to the saved local UNIX time.
+//
+// Remarks:
+//   - Should be called before Run().
+func (c *FakeHardwareClock) Restore() error {
+	buf, err := c.db.Read(fakeHwclockKey)
+	if err != nil {
+		if err == status.StatusNoData {
+			fakeHwclockLogger.Info("saved timestamp not found")
+
+			return nil
+		}
+
+		return fmt.Errorf("fake-hwclock: failed to read timestamp: %w", err)
+	}
+
+	timestamp, err := strconv.ParseInt(string(buf), 10, 64)
+	if err != nil {
+		return fmt.Errorf("fake-hwclock: failed to parse timestamp: %w", err)
+	}
+
+	return c.moveForward(timestamp, "database")
+}
+
+// Run moves the local UNIX time forward to the last persisted device data timestamp,
+// if it's restored, and saves the local UNIX time to the database.
+func (c *FakeHardwareClock) Run() error {
+	if !c.storageRestored {
+		if timestamp, err := c.storage.GetTimestamp(); err == nil {
+			c.storageRestored = true
+
+			if err := c.moveForward(timestamp, "storage"); err != nil {
+				return err
+			}
+		}
+	}
+
+	timestamp, err := c.local.GetTimestamp()
+	if err != nil {
+		return fmt.Errorf("fake-hwclock: failed to get local timestamp: %w", err)
+	}
+
+	if err := c.db.Write(fakeHwclockKey, []byte(strconv.FormatInt(timestamp, 10))); err != nil {
+		return fmt.Errorf("fake-hwclock: failed to save timestamp: %w", err)
+	}
+
+	return nil
+}
+
+// HandleError handles error from the Run() call.
+func (*FakeHardwareClock) HandleError(err error) {
+	fakeHwclockLogger.Error("failed to handle local timestamp", "err", err)
+}
+
+func (c *FakeHardwareClock) moveForward(timestamp int64, origin string) error {
+	localTs, err := c.local.GetTimestamp()
+	if err != nil {
+		return fmt.Errorf("fake-hwclock: failed to get local timestamp: %w", err)
+	}
+
+	if localTs >= timestamp {
+		fakeHwclockLogger.Info("local timestamp is up to date",
+			"local", localTs, "restored", timestamp, "origin", origin)
+
+		return nil
+	}
+
+	if err := c.local.SetTimestamp(timestamp); err != nil {
+		return fmt.Errorf("fake-hwclock: failed to set local timestamp: %w", err)
+	}
+
+	fakeHwclockLogger.Warn("local timestamp moved forward",
+		"from", localTs, "to", timestamp, "origin", origin)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package stcore
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+type testFakeHwclockDB struct {
+	NoopDB
+
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (d *testFakeHwclockDB) Read(key string) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	buf, ok := d.data[key]
+	if !ok {
+		return []byte{}, status.StatusNoData
+	}
+
+	return buf, nil
+}
+
+func (d *testFakeHwclockDB) Write(key string, value []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.data == nil {
+		d.data = make(map[string][]byte)
+	}
+
+	d.data[key] = value
+
+	return nil
+}
+
+type testFakeHwclockClock struct {
+	timestamp int64
+	getErr    error
+	setCount  int
+}
+
+func (c *testFakeHwclockClock) GetTimestamp() (int64, error) {
+	if c.getErr != nil {
+		return -1, c.getErr
+	}
+
+	return c.timestamp, nil
+}
+
+func (c *testFakeHwclockClock) SetTimestamp(timestamp int64) error {
+	c.timestamp = timestamp
+	c.setCount++
+
+	return nil
+}
+
+func TestFakeHardwareClockRestoreNoData(t *testing.T) {
+	local := &testFakeHwclockClock{timestamp: 10}
+	storage := &testFakeHwclockClock{getErr: status.StatusInvalidState}
+
+	clock := NewFakeHardwareClock(local, storage, &testFakeHwclockDB{})
+	require.Nil(t, clock.Restore())
+	require.Equal(t, int64(10), local.timestamp)
+	require.Equal(t, 0, local.setCount)
+}
+
+func TestFakeHardwareClockSaveRestore(t *testing.T) {
+	db := &testFakeHwclockDB{}
+	storage := &testFakeHwclockClock{getErr: status.StatusInvalidState}
+
+	local := &testFakeHwclockClock{timestamp: 1000}
+	require.Nil(t, NewFakeHardwareClock(local, storage, db).Run())
+
+	// Rebooted without RTC.
+	local = &testFakeHwclockClock{timestamp: 10}
+	require.Nil(t, NewFakeHardwareClock(local, storage, db).Restore())
+	require.Equal(t, int64(1000), local.timestamp)
+	require.Equal(t, 1, local.setCount)
+
+	// The local clock is never moved back.
+	local = &testFakeHwclockClock{timestamp: 2000}
+	require.Nil(t, NewFakeHardwareClock(local, storage, db).Restore())
+	require.Equal(t, int64(2000), local.timestamp)
+	require.Equal(t, 0, local.setCount)
+}
+
+func TestFakeHardwareClockRestoreInvalidData(t *testing.T) {
+	db := &testFakeHwclockDB{}
+	require.Nil(t, db.Write(fakeHwclockKey, []byte("foo")))
+
+	local := &testFakeHwclockClock{timestamp: 10}
+	storage := &testFakeHwclockClock{getErr: status.StatusInvalidState}
+
+	require.NotNil(t, NewFakeHardwareClock(local, storage, db).Restore())
+	require.Equal(t, int64(10), local.timestamp)
+}
+
+func TestFakeHardwareClockRestoreStorage(t *testing.T) {
+	db := &testFakeHwclockDB{}
+	local := &testFakeHwclockClock{timestamp: 10}
+	storage := &testFakeHwclockClock{getErr: status.StatusInvalidState}
+
+	clock := NewFakeHardwareClock(local, storage, db)
+	require.Nil(t, clock.Restore())
+
+	// Storage timestamp isn't restored yet.
+	require.Nil(t, clock.Run())
+	require.Equal(t, int64(10), local.timestamp)
+
+	storage.getErr = nil
+	storage.timestamp = 1000
+
+	require.Nil(t, clock.Run())
+	require.Equal(t, int64(1000), local.timestamp)
+	require.Equal(t, 1, local.setCount)
+
+	buf, err := db.Read(fakeHwclockKey)
+	require.Nil(t, err)
+	require.Equal(t, "1000", string(buf))
+
+	// Storage timestamp is handled only once.
+	local.timestamp = 500
+	storage.timestamp = 2000
+
+	require.Nil(t, clock.Run())
+	require.Equal(t, int64(500), local.timestamp)
+	require.Equal(t, 1, local.setCount)
+}
+
+func TestFakeHardwareClockRunLocalError(t *testing.T) {
+	db := &testFakeHwclockDB{}
+	local := &testFakeHwclockClock{getErr: status.StatusError}
+	storage := &testFakeHwclockClock{getErr: status.StatusInvalidState}
+
+	clock := NewFakeHardwareClock(local, storage, db)
+	require.ErrorIs(t, clock.Run(), status.StatusError)
+
+	_, err := db.Read(fakeHwclockKey)
+	require.Equal(t, status.StatusNoData, err)
+}
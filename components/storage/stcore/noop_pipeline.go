@@ -0,0 +1,52 @@
+package stcore
+
+import (
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// NoopPipeline is a storage pipeline which doesn't persist device data.
+//
+// Remarks:
+//   - Last persisted UNIX time is always unknown, -1 is returned.
+type NoopPipeline struct{}
+
+// GetDataHandler returns a non-operational data handler.
+func (p *NoopPipeline) GetDataHandler() devcore.DataHandler {
+	return p
+}
+
+// GetSystemClock returns a clock without the last persisted UNIX time.
+func (p *NoopPipeline) GetSystemClock() syscore.SystemClock {
+	return p
+}
+
+// HandleTelemetry is non-operational.
+func (*NoopPipeline) HandleTelemetry(_ string, _ devcore.JSON) error {
+	return nil
+}
+
+// HandleRegistration is non-operational.
+func (*NoopPipeline) HandleRegistration(_ string, _ devcore.JSON) error {
+	return nil
+}
+
+// SetTimestamp is non-operational.
+func (*NoopPipeline) SetTimestamp(_ int64) error {
+	return nil
+}
+
+// GetTimestamp returns -1.
+func (*NoopPipeline) GetTimestamp() (int64, error) {
+	return -1, nil
+}
+
+// Start is non-operational.
+func (*NoopPipeline) Start() error {
+	return nil
+}
+
+// Stop is non-operational.
+func (*NoopPipeline) Stop() error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package stcore
+
+import (
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/syssched"
+)
+
+// Pipeline contains various building blocks for persisting device data.
+type Pipeline interface {
+	syssched.Starter
+	syssched.Stopper
+
+	// GetDataHandler returns the handler to persist device data.
+	GetDataHandler() devcore.DataHandler
+
+	// GetSystemClock returns the clock to get last persisted UNIX time.
+	//
+	// Remarks:
+	//   - Clock is used as the last known UNIX time for the time synchronization
+	//     between the local and remote resources.
+	GetSystemClock() syscore.SystemClock
+}
@@ -0,0 +1,63 @@
+package stcore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// PipelineFactory creates the storage pipeline.
+type PipelineFactory func(ctx context.Context) (Pipeline, error)
+
+// PipelineRegistry contains all known storage pipelines.
+type PipelineRegistry struct {
+	factories map[string]PipelineFactory
+}
+
+// NewPipelineRegistry is an initialization of PipelineRegistry.
+func NewPipelineRegistry() *PipelineRegistry {
+	return &PipelineRegistry{
+		factories: make(map[string]PipelineFactory),
+	}
+}
+
+// Register registers the storage pipeline factory.
+//
+// Parameters:
+//   - name - unique storage name, e.g. influxdb, file.
+//   - factory to create the storage pipeline.
+//
+// Remarks:
+//   - Factory is replaced if it's already registered.
+func (r *PipelineRegistry) Register(name string, factory PipelineFactory) {
+	r.factories[name] = factory
+}
+
+// Create creates the storage pipeline by name.
+//
+// Remarks:
+//   - status.StatusNotSupported is returned if the storage isn't registered.
+func (r *PipelineRegistry) Create(ctx context.Context, name string) (Pipeline, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: pipeline-registry: unknown storage: name=%s known=%s",
+			status.StatusNotSupported, name, strings.Join(r.Names(), ","))
+	}
+
+	return factory(ctx)
+}
+
+// Names returns the sorted names of all registered storages.
+func (r *PipelineRegistry) Names() []string {
+	var names []string
+	for name := range r.factories {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,49 @@
+package stcore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func TestPipelineRegistryCreate(t *testing.T) {
+	registry := NewPipelineRegistry()
+
+	pipeline := &NoopPipeline{}
+
+	registry.Register("none", func(_ context.Context) (Pipeline, error) {
+		return pipeline, nil
+	})
+	registry.Register("foo", func(_ context.Context) (Pipeline, error) {
+		return nil, status.StatusError
+	})
+
+	require.Equal(t, []string{"foo", "none"}, registry.Names())
+
+	created, err := registry.Create(context.Background(), "none")
+	require.Nil(t, err)
+	require.Equal(t, pipeline, created)
+
+	_, err = registry.Create(context.Background(), "foo")
+	require.Equal(t, status.StatusError, err)
+
+	_, err = registry.Create(context.Background(), "bar")
+	require.True(t, errors.Is(err, status.StatusNotSupported))
+}
+
+func TestNoopPipeline(t *testing.T) {
+	pipeline := &NoopPipeline{}
+
+	require.Nil(t, pipeline.GetDataHandler().HandleTelemetry("0xABCD",
+		map[string]any{"timestamp": float64(123)}))
+
+	require.Nil(t, pipeline.GetSystemClock().SetTimestamp(123))
+
+	timestamp, err := pipeline.GetSystemClock().GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), timestamp)
+}
@@ -0,0 +1,401 @@
+package stcore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var queueLogger = syscore.NewLogger("queue")
+
+// ErrQueueFull is returned if the item can't be added to the queue due to the size limit.
+var ErrQueueFull = errors.New("queue is full")
+
+// QueueDropPolicy defines which items are dropped when the queue is full.
+type QueueDropPolicy int
+
+const (
+	// QueueDropOldest drops the oldest item to free space for the new one.
+	QueueDropOldest QueueDropPolicy = iota
+
+	// QueueDropNewest rejects the new item.
+	QueueDropNewest
+)
+
+// ParseQueueDropPolicy converts the string representation of the drop policy.
+func ParseQueueDropPolicy(str string) (QueueDropPolicy, error) {
+	switch str {
+	case "oldest":
+		return QueueDropOldest, nil
+	case "newest":
+		return QueueDropNewest, nil
+	default:
+		return QueueDropOldest, fmt.Errorf("%w: unknown queue drop policy: %s",
+			status.StatusInvalidArg, str)
+	}
+}
+
+// QueueParams provides various configuration options for the queue.
+type QueueParams struct {
+	// MaxSize - maximum number of items in the queue.
+	MaxSize int
+
+	// MaxAge - how long an item is allowed to stay in the queue, 0 to disable.
+	MaxAge time.Duration
+
+	// DropPolicy - which items are dropped when the queue is full.
+	DropPolicy QueueDropPolicy
+}
+
+// QueueStats contains various queue counters.
+type QueueStats struct {
+	Size           int   `json:"size"`
+	MaxSize        int   `json:"max_size"`
+	OldestAge      int64 `json:"oldest_age_sec"`
+	Pushed         int64 `json:"pushed"`
+	Popped         int64 `json:"popped"`
+	DroppedFull    int64 `json:"dropped_full"`
+	DroppedExpired int64 `json:"dropped_expired"`
+}
+
+// Queue is a persistent FIFO queue of arbitrary items.
+//
+// Remarks:
+//   - All items are kept in memory and mirrored to the DB, the DB is only read
+//     when the queue is created.
+//   - Items are ordered by the zero-padded sequence number used as the DB key.
+//   - Item age is based on the wall clock, items are expired on each operation.
+//   - Queue is thread-safe.
+type Queue struct {
+	db     DB
+	clock  syscore.MonotonicClock
+	params QueueParams
+
+	mu    sync.Mutex
+	seq   uint64
+	items []queueItem
+	stats QueueStats
+}
+
+type queueItem struct {
+	key       string
+	createdAt time.Time
+	buf       []byte
+}
+
+// NewQueue is an initialization of Queue.
+//
+// Parameters:
+//   - db to persist queued items.
+//   - clock to read the current time.
+//   - params - various queue configuration options.
+//
+// Remarks:
+//   - Items persisted in the DB are restored, malformed items are removed.
+func NewQueue(db DB, clock syscore.MonotonicClock, params QueueParams) (*Queue, error) {
+	if params.MaxSize < 1 {
+		return nil, fmt.Errorf("%w: queue: invalid size: %v",
+			status.StatusInvalidArg, params.MaxSize)
+	}
+
+	q := &Queue{
+		db:     db,
+		clock:  clock,
+		params: params,
+	}
+	q.stats.MaxSize = params.MaxSize
+
+	if err := q.restore(); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Push adds the item to the end of the queue.
+//
+// Remarks:
+//   - ErrQueueFull is returned if the queue is full and the new item can't be added.
+func (q *Queue) Push(buf []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	if len(q.items) >= q.params.MaxSize {
+		if q.params.DropPolicy == QueueDropNewest {
+			q.stats.DroppedFull++
+
+			return ErrQueueFull
+		}
+
+		if err := q.pop(1); err != nil {
+			return err
+		}
+
+		q.stats.DroppedFull++
+	}
+
+	item := queueItem{
+		key:       q.formatKey(q.seq),
+		createdAt: q.clock.Now(),
+		buf:       buf,
+	}
+
+	if err := q.db.Write(item.key, encodeQueueItem(item)); err != nil {
+		return fmt.Errorf("queue: failed to write item: %w", err)
+	}
+
+	q.seq++
+	q.items = append(q.items, item)
+	q.stats.Pushed++
+
+	return nil
+}
+
+// Peek returns up to n items from the beginning of the queue.
+//
+// Remarks:
+//   - status.StatusNoData is returned if the queue is empty.
+func (q *Queue) Peek(n int) ([][]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	if len(q.items) == 0 {
+		return nil, status.StatusNoData
+	}
+
+	n = min(n, len(q.items))
+
+	var ret [][]byte
+	for _, item := range q.items[:n] {
+		ret = append(ret, item.buf)
+	}
+
+	return ret, nil
+}
+
+// PeekBatch returns up to n items from the beginning of the queue and the key of the
+// last returned item, to be passed to PopUntil().
+//
+// Remarks:
+//   - status.StatusNoData is returned if the queue is empty.
+func (q *Queue) PeekBatch(n int) ([][]byte, string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	if len(q.items) == 0 {
+		return nil, "", status.StatusNoData
+	}
+
+	n = min(n, len(q.items))
+
+	var ret [][]byte
+	for _, item := range q.items[:n] {
+		ret = append(ret, item.buf)
+	}
+
+	return ret, q.items[n-1].key, nil
+}
+
+// PopUntil removes items from the beginning of the queue up to and including the item
+// with the provided key.
+//
+// Remarks:
+//   - Items removed after PeekBatch(), e.g. expired or dropped because the queue is full,
+//     aren't removed twice, so the caller doesn't need to serialize PeekBatch() and
+//     PopUntil() with Push().
+func (q *Queue) PopUntil(key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, item := range q.items {
+		if item.key > key {
+			break
+		}
+
+		n++
+	}
+
+	if err := q.pop(n); err != nil {
+		return err
+	}
+
+	q.stats.Popped += int64(n)
+
+	return nil
+}
+
+// Pop removes up to n items from the beginning of the queue.
+func (q *Queue) Pop(n int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n = min(n, len(q.items))
+
+	if err := q.pop(n); err != nil {
+		return err
+	}
+
+	q.stats.Popped += int64(n)
+
+	return nil
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	return len(q.items)
+}
+
+// GetStats returns the queue counters.
+func (q *Queue) GetStats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	stats := q.stats
+	stats.Size = len(q.items)
+
+	if len(q.items) > 0 {
+		stats.OldestAge = int64(q.clock.Now().Sub(q.items[0].createdAt).Seconds())
+	}
+
+	return stats
+}
+
+func (q *Queue) expire() {
+	if q.params.MaxAge == 0 {
+		return
+	}
+
+	now := q.clock.Now()
+
+	n := 0
+	for _, item := range q.items {
+		if now.Sub(item.createdAt) < q.params.MaxAge {
+			break
+		}
+
+		n++
+	}
+
+	if n == 0 {
+		return
+	}
+
+	if err := q.pop(n); err != nil {
+		queueLogger.Error("failed to remove expired items", "err", err)
+
+		return
+	}
+
+	q.stats.DroppedExpired += int64(n)
+}
+
+func (q *Queue) pop(n int) error {
+	for i := 0; i < n; i++ {
+		if err := q.db.Remove(q.items[0].key); err != nil {
+			return fmt.Errorf("queue: failed to remove item: %w", err)
+		}
+
+		q.items = q.items[1:]
+	}
+
+	return nil
+}
+
+func (q *Queue) restore() error {
+	var malformed []string
+
+	err := q.db.ForEach(func(key string, buf []byte) error {
+		seq, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			malformed = append(malformed, key)
+
+			return nil
+		}
+
+		item, err := decodeQueueItem(key, buf)
+		if err != nil {
+			malformed = append(malformed, key)
+
+			return nil
+		}
+
+		q.items = append(q.items, item)
+		q.seq = max(q.seq, seq+1)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("queue: failed to restore items: %w", err)
+	}
+
+	for _, key := range malformed {
+		queueLogger.Warn("removing malformed item", "key", key)
+
+		if err := q.db.Remove(key); err != nil {
+			return fmt.Errorf("queue: failed to remove malformed item: %w", err)
+		}
+	}
+
+	sort.Slice(q.items, func(i, j int) bool {
+		return q.items[i].key < q.items[j].key
+	})
+
+	for len(q.items) > q.params.MaxSize {
+		if err := q.pop(1); err != nil {
+			return err
+		}
+	}
+
+	if len(q.items) > 0 {
+		queueLogger.Info("items restored", "count", len(q.items))
+	}
+
+	return nil
+}
+
+func (*Queue) formatKey(seq uint64) string {
+	return fmt.Sprintf("%020d", seq)
+}
+
+func encodeQueueItem(item queueItem) []byte {
+	buf := make([]byte, 8, 8+len(item.buf))
+	binary.BigEndian.PutUint64(buf, uint64(item.createdAt.UnixNano()))
+
+	return append(buf, item.buf...)
+}
+
+func decodeQueueItem(key string, buf []byte) (queueItem, error) {
+	if len(buf) < 8 {
+		return queueItem{}, fmt.Errorf("%w: queue: item is too short: len=%v",
+			status.StatusInvalidArg, len(buf))
+	}
+
+	createdAt := int64(binary.BigEndian.Uint64(buf[:8]))
+
+	return queueItem{
+		key:       key,
+		createdAt: time.Unix(0, createdAt),
+		buf:       append([]byte{}, buf[8:]...),
+	}, nil
+}
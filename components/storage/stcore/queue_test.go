@@ -0,0 +1,210 @@
+package stcore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+type testQueueClock struct {
+	now time.Time
+}
+
+func (c *testQueueClock) Now() time.Time {
+	return c.now
+}
+
+func newTestQueueDB(t *testing.T) *bbolt.DB {
+	db, err := NewBboltDB(filepath.Join(t.TempDir(), "bbolt.db"), nil)
+	require.Nil(t, err)
+
+	return db
+}
+
+func peekTestQueue(t *testing.T, queue *Queue, n int) []string {
+	bufs, err := queue.Peek(n)
+	require.Nil(t, err)
+
+	var ret []string
+	for _, buf := range bufs {
+		ret = append(ret, string(buf))
+	}
+
+	return ret
+}
+
+func TestQueuePushPop(t *testing.T) {
+	db := newTestQueueDB(t)
+	defer func() {
+		require.Nil(t, db.Close())
+	}()
+
+	clock := &testQueueClock{now: time.Unix(100, 0)}
+
+	queue, err := NewQueue(NewBboltDBBucket(db, "queue"), clock, QueueParams{MaxSize: 10})
+	require.Nil(t, err)
+
+	_, err = queue.Peek(1)
+	require.Equal(t, status.StatusNoData, err)
+
+	for _, item := range []string{"foo", "bar", "baz"} {
+		require.Nil(t, queue.Push([]byte(item)))
+	}
+	require.Equal(t, 3, queue.Len())
+
+	require.Equal(t, []string{"foo", "bar"}, peekTestQueue(t, queue, 2))
+	require.Nil(t, queue.Pop(2))
+
+	require.Equal(t, []string{"baz"}, peekTestQueue(t, queue, 2))
+	require.Nil(t, queue.Pop(2))
+
+	_, err = queue.Peek(1)
+	require.Equal(t, status.StatusNoData, err)
+
+	stats := queue.GetStats()
+	require.Equal(t, 0, stats.Size)
+	require.Equal(t, 10, stats.MaxSize)
+	require.Equal(t, int64(3), stats.Pushed)
+	require.Equal(t, int64(3), stats.Popped)
+}
+
+func TestQueueRestore(t *testing.T) {
+	db := newTestQueueDB(t)
+	defer func() {
+		require.Nil(t, db.Close())
+	}()
+
+	clock := &testQueueClock{now: time.Unix(100, 0)}
+	params := QueueParams{MaxSize: 100}
+
+	queue, err := NewQueue(NewBboltDBBucket(db, "queue"), clock, params)
+	require.Nil(t, err)
+
+	var items []string
+	for n := 0; n < 20; n++ {
+		item := time.Unix(int64(n), 0).String()
+		items = append(items, item)
+
+		require.Nil(t, queue.Push([]byte(item)))
+	}
+	require.Nil(t, queue.Pop(5))
+
+	queue, err = NewQueue(NewBboltDBBucket(db, "queue"), clock, params)
+	require.Nil(t, err)
+	require.Equal(t, items[5:], peekTestQueue(t, queue, 100))
+
+	require.Nil(t, queue.Push([]byte("foo")))
+	require.Equal(t, append(items[5:], "foo"), peekTestQueue(t, queue, 100))
+}
+
+func TestQueueRestoreMalformed(t *testing.T) {
+	db := newTestQueueDB(t)
+	defer func() {
+		require.Nil(t, db.Close())
+	}()
+
+	bucket := NewBboltDBBucket(db, "queue")
+	require.Nil(t, bucket.Write("foo", []byte("12345678bar")))
+	require.Nil(t, bucket.Write("1", []byte("123")))
+
+	queue, err := NewQueue(bucket, &testQueueClock{}, QueueParams{MaxSize: 10})
+	require.Nil(t, err)
+	require.Equal(t, 0, queue.Len())
+
+	_, err = bucket.Read("foo")
+	require.Equal(t, status.StatusNoData, err)
+}
+
+func TestQueueDropOldest(t *testing.T) {
+	queue, err := NewQueue(&NoopDB{}, &testQueueClock{}, QueueParams{
+		MaxSize:    2,
+		DropPolicy: QueueDropOldest,
+	})
+	require.Nil(t, err)
+
+	for _, item := range []string{"foo", "bar", "baz"} {
+		require.Nil(t, queue.Push([]byte(item)))
+	}
+
+	require.Equal(t, []string{"bar", "baz"}, peekTestQueue(t, queue, 10))
+	require.Equal(t, int64(1), queue.GetStats().DroppedFull)
+}
+
+func TestQueuePeekBatchPopUntil(t *testing.T) {
+	queue, err := NewQueue(&NoopDB{}, &testQueueClock{}, QueueParams{
+		MaxSize:    3,
+		DropPolicy: QueueDropOldest,
+	})
+	require.Nil(t, err)
+
+	_, _, err = queue.PeekBatch(1)
+	require.Equal(t, status.StatusNoData, err)
+
+	for _, item := range []string{"foo", "bar", "baz"} {
+		require.Nil(t, queue.Push([]byte(item)))
+	}
+
+	bufs, key, err := queue.PeekBatch(2)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("foo"), []byte("bar")}, bufs)
+
+	// The oldest peeked item is dropped meanwhile.
+	require.Nil(t, queue.Push([]byte("qux")))
+
+	require.Nil(t, queue.PopUntil(key))
+	require.Equal(t, []string{"baz", "qux"}, peekTestQueue(t, queue, 10))
+	require.Equal(t, int64(1), queue.GetStats().Popped)
+}
+
+func TestQueueDropNewest(t *testing.T) {
+	queue, err := NewQueue(&NoopDB{}, &testQueueClock{}, QueueParams{
+		MaxSize:    2,
+		DropPolicy: QueueDropNewest,
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push([]byte("foo")))
+	require.Nil(t, queue.Push([]byte("bar")))
+	require.Equal(t, ErrQueueFull, queue.Push([]byte("baz")))
+
+	require.Equal(t, []string{"foo", "bar"}, peekTestQueue(t, queue, 10))
+	require.Equal(t, int64(1), queue.GetStats().DroppedFull)
+}
+
+func TestQueueMaxAge(t *testing.T) {
+	clock := &testQueueClock{now: time.Unix(100, 0)}
+
+	queue, err := NewQueue(&NoopDB{}, clock, QueueParams{
+		MaxSize: 10,
+		MaxAge:  time.Minute,
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push([]byte("foo")))
+
+	clock.now = clock.now.Add(time.Second * 30)
+	require.Nil(t, queue.Push([]byte("bar")))
+	require.Equal(t, int64(30), queue.GetStats().OldestAge)
+
+	clock.now = clock.now.Add(time.Second * 30)
+	require.Equal(t, []string{"bar"}, peekTestQueue(t, queue, 10))
+
+	clock.now = clock.now.Add(time.Second * 30)
+	_, err = queue.Peek(10)
+	require.Equal(t, status.StatusNoData, err)
+
+	require.Equal(t, int64(2), queue.GetStats().DroppedExpired)
+}
+
+func TestQueueInvalidParams(t *testing.T) {
+	_, err := NewQueue(&NoopDB{}, &testQueueClock{}, QueueParams{})
+	require.NotNil(t, err)
+
+	_, err = ParseQueueDropPolicy("foo")
+	require.NotNil(t, err)
+}
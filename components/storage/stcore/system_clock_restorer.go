@@ -8,6 +8,8 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 )
 
+var clockRestorerLogger = syscore.NewLogger("system-clock-restorer")
+
 // SystemClockRestorer restores the UNIX timestamp from the persistent storage.
 type SystemClockRestorer struct {
 	ctx    context.Context
@@ -42,7 +44,7 @@ func (r *SystemClockRestorer) SetTimestamp(timestamp int64) error {
 	if !r.restored {
 		r.restored = true
 
-		syscore.LogInf.Printf("skip timestamp restoring: value=%v", timestamp)
+		clockRestorerLogger.Info("skip timestamp restoring", "value", timestamp)
 	}
 
 	return nil
@@ -63,7 +65,7 @@ func (r *SystemClockRestorer) GetTimestamp() (int64, error) {
 // HandleError handles error from the Run() call.
 func (*SystemClockRestorer) HandleError(err error) {
 	if err != status.StatusNoData {
-		syscore.LogErr.Printf("failed to restore timestamp: err=%v", err)
+		clockRestorerLogger.Error("failed to restore timestamp", "err", err)
 	}
 }
 
@@ -78,13 +80,13 @@ func (r *SystemClockRestorer) Run() error {
 	defer r.mu.Unlock()
 
 	if r.restored {
-		syscore.LogInf.Printf("timestamp already restored: restored=%v persisted=%v",
-			r.timestamp, timestamp)
+		clockRestorerLogger.Info("timestamp already restored",
+			"restored", r.timestamp, "persisted", timestamp)
 	} else {
 		r.restored = true
 		r.timestamp = timestamp
 
-		syscore.LogInf.Printf("timestamp restored: value=%v", r.timestamp)
+		clockRestorerLogger.Info("timestamp restored", "value", r.timestamp)
 	}
 
 	return nil
@@ -0,0 +1,81 @@
+package stfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// Record is a single line in the file storage.
+type Record struct {
+	Measurement string       `json:"measurement"`
+	DeviceID    string       `json:"device_id"`
+	Timestamp   int64        `json:"timestamp"`
+	Data        devcore.JSON `json:"data"`
+}
+
+// DataHandler appends incoming data to the file, one JSON record per line.
+type DataHandler struct {
+	clock syscore.SystemClock
+
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewDataHandler is an initialization of DataHandler.
+//
+// Parameters:
+//   - clock to update the most recent UNIX time.
+//   - writer to write the JSON records.
+func NewDataHandler(clock syscore.SystemClock, writer io.Writer) *DataHandler {
+	return &DataHandler{
+		clock:  clock,
+		writer: writer,
+	}
+}
+
+// HandleTelemetry appends telemetry data to the file.
+func (h *DataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.handleData("telemetry", deviceID, js)
+}
+
+// HandleRegistration appends registration data to the file.
+func (h *DataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.handleData("registration", deviceID, js)
+}
+
+func (h *DataHandler) handleData(dataID string, deviceID string, js devcore.JSON) error {
+	ts, ok := js["timestamp"]
+	if !ok {
+		return fmt.Errorf("file-data-handler: missed timestamp field")
+	}
+
+	timestamp, ok := ts.(float64)
+	if !ok {
+		return fmt.Errorf("file-data-handler: invalid type for timestamp")
+	}
+
+	buf, err := json.Marshal(Record{
+		Measurement: dataID,
+		DeviceID:    deviceID,
+		Timestamp:   int64(timestamp),
+		Data:        js,
+	})
+	if err != nil {
+		return fmt.Errorf("file-data-handler: failed to format record: %w", err)
+	}
+
+	h.mu.Lock()
+	_, err = h.writer.Write(append(buf, '\n'))
+	h.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("file-data-handler: failed to write record: %w", err)
+	}
+
+	return h.clock.SetTimestamp(int64(timestamp))
+}
@@ -0,0 +1,78 @@
+package stfile
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/syssched"
+)
+
+// Pipeline contains various building blocks for persisting data in the file.
+type Pipeline struct {
+	file     *os.File
+	restorer *stcore.SystemClockRestorer
+	runner   *syssched.AsyncTaskRunner
+	handler  *DataHandler
+}
+
+// NewPipeline initializes all components associated with the file storage.
+//
+// Parameters:
+//   - ctx - parent context.
+//   - path - file path, data is appended to the file, the file is created if it
+//     doesn't exist.
+func NewPipeline(ctx context.Context, path string) (*Pipeline, error) {
+	reader := NewSystemClockReader(path, 64*1024)
+	restorer := stcore.NewSystemClockRestorer(ctx, reader)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	runner := syssched.NewAsyncTaskRunner(
+		ctx,
+		restorer,
+		restorer,
+		syssched.AsyncTaskRunnerParams{
+			Name:           "storage-file-clock-restorer",
+			UpdateInterval: time.Second * 5,
+			ExitOnSuccess:  true,
+		},
+	)
+
+	return &Pipeline{
+		file:     file,
+		restorer: restorer,
+		runner:   runner,
+		handler:  NewDataHandler(restorer, file),
+	}, nil
+}
+
+// GetDataHandler returns the underlying file data handler.
+func (p *Pipeline) GetDataHandler() devcore.DataHandler {
+	return p.handler
+}
+
+// GetSystemClock returns the clock to get last persisted UNIX time.
+func (p *Pipeline) GetSystemClock() syscore.SystemClock {
+	return p.restorer
+}
+
+// Start starts the asynchronous UNIX time restoring.
+func (p *Pipeline) Start() error {
+	return p.runner.Start()
+}
+
+// Stop stops writing data to the file.
+func (p *Pipeline) Stop() error {
+	if err := p.runner.Stop(); err != nil {
+		return err
+	}
+
+	return p.file.Close()
+}
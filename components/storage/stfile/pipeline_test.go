@@ -0,0 +1,106 @@
+package stfile
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func readTestPipelineRecords(t *testing.T, path string) []Record {
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer func() {
+		require.Nil(t, file.Close())
+	}()
+
+	var records []Record
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &record))
+
+		records = append(records, record)
+	}
+	require.Nil(t, scanner.Err())
+
+	return records
+}
+
+func TestPipelineHandleData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+
+	pipeline, err := NewPipeline(context.Background(), path)
+	require.Nil(t, err)
+
+	handler := pipeline.GetDataHandler()
+
+	require.Nil(t, handler.HandleRegistration("0xABCD",
+		devcore.JSON{"timestamp": float64(100), "device_id": "0xABCD"}))
+	require.Nil(t, handler.HandleTelemetry("0xABCD",
+		devcore.JSON{"timestamp": float64(123), "temperature": float64(42.5)}))
+
+	require.NotNil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{"foo": "bar"}))
+
+	timestamp, err := pipeline.GetSystemClock().GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(123), timestamp)
+
+	require.Nil(t, pipeline.file.Close())
+
+	records := readTestPipelineRecords(t, path)
+	require.Equal(t, 2, len(records))
+
+	require.Equal(t, "registration", records[0].Measurement)
+	require.Equal(t, "0xABCD", records[0].DeviceID)
+	require.Equal(t, int64(100), records[0].Timestamp)
+
+	require.Equal(t, "telemetry", records[1].Measurement)
+	require.Equal(t, int64(123), records[1].Timestamp)
+	require.Equal(t, float64(42.5), records[1].Data["temperature"])
+}
+
+func TestSystemClockReaderReadTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+
+	reader := NewSystemClockReader(path, 256)
+
+	_, err := reader.ReadTimestamp(context.Background())
+	require.Equal(t, status.StatusNoData, err)
+
+	file, err := os.Create(path)
+	require.Nil(t, err)
+
+	handler := NewDataHandler(&testPipelineClock{}, file)
+	for n := 1; n <= 10; n++ {
+		require.Nil(t, handler.HandleTelemetry("0xABCD",
+			devcore.JSON{"timestamp": float64(n * 100)}))
+	}
+	require.Nil(t, handler.HandleRegistration("0xABCD",
+		devcore.JSON{"timestamp": float64(2000)}))
+	require.Nil(t, handler.HandleTelemetry("0xABCD",
+		devcore.JSON{"timestamp": float64(1100)}))
+	require.Nil(t, file.Close())
+
+	timestamp, err := reader.ReadTimestamp(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, int64(2000), timestamp)
+}
+
+type testPipelineClock struct{}
+
+func (*testPipelineClock) SetTimestamp(_ int64) error {
+	return nil
+}
+
+func (*testPipelineClock) GetTimestamp() (int64, error) {
+	return -1, nil
+}
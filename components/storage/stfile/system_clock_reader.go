@@ -0,0 +1,79 @@
+package stfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// SystemClockReader reads the UNIX timestamp from the file storage.
+type SystemClockReader struct {
+	path     string
+	tailSize int64
+}
+
+// NewSystemClockReader is an initialization of SystemClockReader.
+//
+// Parameters:
+//   - path - file storage path.
+//   - tailSize - how many bytes to read from the end of the file.
+func NewSystemClockReader(path string, tailSize int64) *SystemClockReader {
+	return &SystemClockReader{
+		path:     path,
+		tailSize: tailSize,
+	}
+}
+
+// ReadTimestamp reads the most recent UNIX timestamp from the file storage.
+//
+// Remarks:
+//   - Only the end of the file is read, the most recent timestamp of all records
+//     found there is returned.
+func (r *SystemClockReader) ReadTimestamp(_ context.Context) (int64, error) {
+	file, err := os.Open(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, status.StatusNoData
+		}
+
+		return -1, fmt.Errorf("file-storage: failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return -1, fmt.Errorf("file-storage: failed to stat file: %w", err)
+	}
+
+	offset := max(fi.Size()-r.tailSize, 0)
+
+	buf := make([]byte, fi.Size()-offset)
+	if _, err := file.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return -1, fmt.Errorf("file-storage: failed to read file: %w", err)
+	}
+
+	timestamp := int64(-1)
+
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			// The first line may be partially read.
+			continue
+		}
+
+		if record.Timestamp > timestamp {
+			timestamp = record.Timestamp
+		}
+	}
+
+	if timestamp == -1 {
+		return -1, status.StatusNoData
+	}
+
+	return timestamp, nil
+}
@@ -6,10 +6,10 @@ import (
 	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	"github.com/influxdata/influxdb-client-go/v2/api"
 
 	"github.com/open-control-systems/device-hub/components/device/devcore"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
 )
 
 // DataHandler stores incoming data in influxDB.
@@ -20,7 +20,7 @@ import (
 type DataHandler struct {
 	ctx    context.Context
 	clock  syscore.SystemClock
-	client api.WriteAPIBlocking
+	client Writer
 }
 
 // NewDataHandler initializes influxDB handler.
@@ -32,7 +32,7 @@ type DataHandler struct {
 func NewDataHandler(
 	ctx context.Context,
 	clock syscore.SystemClock,
-	client api.WriteAPIBlocking,
+	client Writer,
 ) *DataHandler {
 	return &DataHandler{
 		ctx:    ctx,
@@ -64,12 +64,31 @@ func (h *DataHandler) handleData(dataID string, deviceID string, js devcore.JSON
 
 	unixTimestamp := time.Unix(int64(timestamp), 0)
 
-	point := influxdb2.NewPoint(dataID,
-		map[string]string{"device_id": deviceID},
-		js,
-		unixTimestamp)
+	tags := map[string]string{"device_id": deviceID}
+	fields := js
 
-	if err := h.client.WritePoint(h.ctx, point); err != nil {
+	// Corrected data is tagged, so it can be filtered out or compared with the data
+	// timestamped by the device.
+	if source, ok := js[devcore.TimestampSourceKey].(string); ok {
+		tags[devcore.TimestampSourceKey] = source
+
+		fields = make(devcore.JSON, len(js))
+		for key, value := range js {
+			if key != devcore.TimestampSourceKey {
+				fields[key] = value
+			}
+		}
+	}
+
+	point := influxdb2.NewPoint(dataID, tags, fields, unixTimestamp)
+
+	start := time.Now()
+	err := h.client.WritePoint(h.ctx, point)
+
+	writeDuration.WithLabelValues(dataID).Observe(time.Since(start).Seconds())
+	writeTotal.WithLabelValues(dataID, sysmetrics.ResultLabel(err)).Inc()
+
+	if err != nil {
 		return fmt.Errorf("influxdb-data-handler: failed to write to DB: %w", err)
 	}
 
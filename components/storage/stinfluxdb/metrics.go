@@ -0,0 +1,31 @@
+package stinfluxdb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var (
+	writeDuration = promauto.With(sysmetrics.Registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "influxdb",
+			Name:      "write_duration_seconds",
+			Help:      "Duration of the influxdb writes.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"measurement"},
+	)
+
+	writeTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "influxdb",
+			Name:      "write_total",
+			Help:      "Number of influxdb writes by result.",
+		},
+		[]string{"measurement", "result"},
+	)
+)
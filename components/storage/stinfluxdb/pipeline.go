@@ -6,6 +6,7 @@ import (
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 
+	"github.com/open-control-systems/device-hub/components/device/devcore"
 	"github.com/open-control-systems/device-hub/components/storage/stcore"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 	"github.com/open-control-systems/device-hub/components/system/syssched"
@@ -19,12 +20,28 @@ type DBParams struct {
 	Bucket string
 }
 
+// QueueParams provides various configuration options for the influxDB write queue.
+type QueueParams struct {
+	stcore.QueueParams
+
+	// Disable disables the write queue, data is written directly to the DB.
+	Disable bool
+
+	// ReplayInterval - how often to replay the queued data.
+	ReplayInterval time.Duration
+
+	// BatchSize - maximum number of records replayed in a single write.
+	BatchSize int
+}
+
 // Pipeline contains various building blocks for persisting data in influxdb.
 type Pipeline struct {
-	dbClient influxdb2.Client
-	restorer *stcore.SystemClockRestorer
-	runner   *syssched.AsyncTaskRunner
-	handler  *DataHandler
+	dbClient    influxdb2.Client
+	restorer    *stcore.SystemClockRestorer
+	runner      *syssched.AsyncTaskRunner
+	queueWriter *QueueWriter
+	queueRunner *syssched.AsyncTaskRunner
+	handler     *DataHandler
 }
 
 // NewPipeline initializes all components associated with the influxdb subsystem.
@@ -32,9 +49,15 @@ type Pipeline struct {
 // Parameters:
 //   - ctx - parent context.
 //   - params - various influxDB configuration parameters.
-func NewPipeline(ctx context.Context, params DBParams) *Pipeline {
+//   - queueDB to persist the data while influxDB is unavailable.
+//   - queueParams - various write queue configuration parameters.
+func NewPipeline(
+	ctx context.Context,
+	params DBParams,
+	queueDB stcore.DB,
+	queueParams QueueParams,
+) (*Pipeline, error) {
 	dbClient := influxdb2.NewClient(params.URL, params.Token)
-	writeClient := dbClient.WriteAPIBlocking(params.Org, params.Bucket)
 	queryClient := dbClient.QueryAPI(params.Org)
 
 	reader := NewSystemClockReader(queryClient, params.Bucket)
@@ -44,36 +67,82 @@ func NewPipeline(ctx context.Context, params DBParams) *Pipeline {
 		restorer,
 		restorer,
 		syssched.AsyncTaskRunnerParams{
+			Name:           "storage-influxdb-clock-restorer",
 			UpdateInterval: time.Second * 5,
 			ExitOnSuccess:  true,
 		},
 	)
 
-	return &Pipeline{
+	pipeline := &Pipeline{
 		dbClient: dbClient,
 		restorer: restorer,
 		runner:   runner,
-		handler:  NewDataHandler(ctx, restorer, writeClient),
 	}
+
+	var writer Writer = dbClient.WriteAPIBlocking(params.Org, params.Bucket)
+
+	if !queueParams.Disable {
+		queue, err := stcore.NewQueue(queueDB, &syscore.LocalMonotonicClock{},
+			queueParams.QueueParams)
+		if err != nil {
+			dbClient.Close()
+
+			return nil, err
+		}
+
+		pipeline.queueWriter = NewQueueWriter(ctx, writer, queue, queueParams.BatchSize)
+		pipeline.queueRunner = syssched.NewAsyncTaskRunner(
+			ctx,
+			pipeline.queueWriter,
+			pipeline.queueWriter,
+			syssched.AsyncTaskRunnerParams{
+				Name:           "storage-influxdb-queue-replay",
+				UpdateInterval: queueParams.ReplayInterval,
+			},
+		)
+
+		writer = pipeline.queueWriter
+	}
+
+	pipeline.handler = NewDataHandler(ctx, restorer, writer)
+
+	return pipeline, nil
 }
 
 // GetDataHandler returns the underlying influxdb data handler.
-func (p *Pipeline) GetDataHandler() *DataHandler {
+func (p *Pipeline) GetDataHandler() devcore.DataHandler {
 	return p.handler
 }
 
+// GetQueueWriter returns the write queue, nil is returned if the queue is disabled.
+func (p *Pipeline) GetQueueWriter() *QueueWriter {
+	return p.queueWriter
+}
+
 // GetSystemClock returns the clock to get last persisted UNIX time.
 func (p *Pipeline) GetSystemClock() syscore.SystemClock {
 	return p.restorer
 }
 
-// Start starts the asynchronous UNIX time restoring.
+// Start starts the asynchronous UNIX time restoring and the queued data replaying.
 func (p *Pipeline) Start() error {
+	if p.queueRunner != nil {
+		if err := p.queueRunner.Start(); err != nil {
+			return err
+		}
+	}
+
 	return p.runner.Start()
 }
 
 // Stop stops writing data to the DB.
 func (p *Pipeline) Stop() error {
+	if p.queueRunner != nil {
+		if err := p.queueRunner.Stop(); err != nil {
+			return err
+		}
+	}
+
 	p.dbClient.Close()
 
 	return p.runner.Stop()
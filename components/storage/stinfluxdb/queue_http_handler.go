@@ -0,0 +1,40 @@
+package stinfluxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/open-control-systems/device-hub/components/http/htcore"
+)
+
+// QueueHTTPHandler exposes the queue writer counters over HTTP.
+type QueueHTTPHandler struct {
+	writer *QueueWriter
+}
+
+// NewQueueHTTPHandler is an initialization of QueueHTTPHandler.
+func NewQueueHTTPHandler(writer *QueueWriter) *QueueHTTPHandler {
+	return &QueueHTTPHandler{
+		writer: writer,
+	}
+}
+
+// ServeHTTP implements an HTTP endpoint logic.
+func (h *QueueHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	buf, err := json.Marshal(h.writer.GetStats())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to format stats: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
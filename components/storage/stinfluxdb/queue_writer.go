@@ -0,0 +1,218 @@
+package stinfluxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	influxhttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var queueWriterLogger = syscore.NewLogger("influxdb-queue-writer")
+
+// Writer writes data to influxdb.
+//
+// Remarks:
+//   - api.WriteAPIBlocking implements this interface.
+type Writer interface {
+	// WritePoint writes data points to the DB.
+	WritePoint(ctx context.Context, point ...*write.Point) error
+
+	// WriteRecord writes line protocol records to the DB.
+	WriteRecord(ctx context.Context, line ...string) error
+}
+
+// QueueWriterStats contains various queue writer counters.
+type QueueWriterStats struct {
+	stcore.QueueStats
+
+	Replayed      int64 `json:"replayed"`
+	Rejected      int64 `json:"rejected"`
+	WriteFailures int64 `json:"write_failures"`
+}
+
+// QueueWriter writes data to influxdb through the persistent queue.
+//
+// Remarks:
+//   - Data is written directly if the queue is empty, otherwise it's added to the queue
+//     to preserve the write order.
+//   - Data is added to the queue if influxdb is unavailable.
+//   - Data rejected by influxdb isn't added to the queue.
+//   - Queued data is replayed in order on each Run() call.
+//   - Concurrent writers are serialized to preserve the write order, the replay
+//     doesn't block the writers and the writers don't block the stats.
+type QueueWriter struct {
+	ctx       context.Context
+	writer    Writer
+	queue     *stcore.Queue
+	batchSize int
+
+	// writeMu makes the queue check and the direct write atomic, otherwise the newer
+	// data could be written directly while the older data is being queued.
+	writeMu sync.Mutex
+
+	mu    sync.Mutex
+	stats QueueWriterStats
+}
+
+// NewQueueWriter is an initialization of QueueWriter.
+//
+// Parameters:
+//   - ctx - parent context.
+//   - writer to write data to influxdb.
+//   - queue to store data while influxdb is unavailable.
+//   - batchSize - maximum number of records replayed in a single write.
+func NewQueueWriter(
+	ctx context.Context,
+	writer Writer,
+	queue *stcore.Queue,
+	batchSize int,
+) *QueueWriter {
+	return &QueueWriter{
+		ctx:       ctx,
+		writer:    writer,
+		queue:     queue,
+		batchSize: batchSize,
+	}
+}
+
+// WritePoint writes data points to influxdb or to the queue.
+func (w *QueueWriter) WritePoint(ctx context.Context, points ...*write.Point) error {
+	var lines []string
+	for _, point := range points {
+		lines = append(lines, write.PointToLineProtocol(point, time.Nanosecond))
+	}
+
+	return w.WriteRecord(ctx, lines...)
+}
+
+// WriteRecord writes line protocol records to influxdb or to the queue.
+//
+// Remarks:
+//   - stcore.ErrQueueFull is returned if the records can't be added to the queue.
+func (w *QueueWriter) WriteRecord(ctx context.Context, lines ...string) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	if w.queue.Len() == 0 {
+		err := w.writer.WriteRecord(ctx, lines...)
+		if err == nil {
+			return nil
+		}
+
+		w.mu.Lock()
+		rejected := isRejectedError(err)
+		if rejected {
+			w.stats.Rejected++
+		} else {
+			w.stats.WriteFailures++
+		}
+		w.mu.Unlock()
+
+		if rejected {
+			return fmt.Errorf("influxdb-queue-writer: data rejected: %w", err)
+		}
+
+		queueWriterLogger.Warn("write failed, queueing data", "err", err)
+	}
+
+	for _, line := range lines {
+		if err := w.queue.Push([]byte(line)); err != nil {
+			return fmt.Errorf("influxdb-queue-writer: failed to queue data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Run replays the queued data in order.
+func (w *QueueWriter) Run() error {
+	for {
+		if err := w.replay(); err != nil {
+			if err == status.StatusNoData {
+				return nil
+			}
+
+			return err
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		default:
+		}
+	}
+}
+
+// HandleError handles error from the Run() call.
+func (*QueueWriter) HandleError(err error) {
+	queueWriterLogger.Error("failed to replay data", "err", err)
+}
+
+// GetStats returns the queue writer counters.
+func (w *QueueWriter) GetStats() QueueWriterStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := w.stats
+	stats.QueueStats = w.queue.GetStats()
+
+	return stats
+}
+
+func (w *QueueWriter) replay() error {
+	bufs, lastKey, err := w.queue.PeekBatch(w.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, buf := range bufs {
+		lines = append(lines, string(buf))
+	}
+
+	err = w.writer.WriteRecord(w.ctx, lines...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		if !isRejectedError(err) {
+			w.stats.WriteFailures++
+
+			return fmt.Errorf("influxdb-queue-writer: failed to write data: %w", err)
+		}
+
+		queueWriterLogger.Error("data rejected, dropping", "count", len(lines), "err", err)
+
+		w.stats.Rejected += int64(len(lines))
+	} else {
+		w.stats.Replayed += int64(len(lines))
+	}
+
+	// Items could be expired or dropped by the concurrent writers during the write.
+	return w.queue.PopUntil(lastKey)
+}
+
+func isRejectedError(err error) bool {
+	var httpErr *influxhttp.Error
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+
+	switch httpErr.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge,
+		http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
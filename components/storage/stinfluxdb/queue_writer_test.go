@@ -0,0 +1,324 @@
+package stinfluxdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	influxhttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+type testQueueWriterWriter struct {
+	mu    sync.Mutex
+	err   error
+	lines []string
+}
+
+func (w *testQueueWriterWriter) WritePoint(ctx context.Context, points ...*write.Point) error {
+	var lines []string
+	for _, point := range points {
+		lines = append(lines, write.PointToLineProtocol(point, time.Nanosecond))
+	}
+
+	return w.WriteRecord(ctx, lines...)
+}
+
+func (w *testQueueWriterWriter) WriteRecord(_ context.Context, lines ...string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	w.lines = append(w.lines, lines...)
+
+	return nil
+}
+
+func (w *testQueueWriterWriter) setError(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.err = err
+}
+
+func (w *testQueueWriterWriter) getLines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string{}, w.lines...)
+}
+
+func newTestQueueWriter(t *testing.T, writer Writer, maxSize int) *QueueWriter {
+	queue, err := stcore.NewQueue(&stcore.NoopDB{}, &syscore.LocalMonotonicClock{},
+		stcore.QueueParams{MaxSize: maxSize})
+	require.Nil(t, err)
+
+	return NewQueueWriter(context.Background(), writer, queue, 2)
+}
+
+func newTestQueueWriterPoint(value int) *write.Point {
+	return influxdb2.NewPoint("telemetry",
+		map[string]string{"device_id": "0xABCD"},
+		map[string]any{"value": value},
+		time.Unix(int64(value), 0))
+}
+
+func TestQueueWriterWriteDirect(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	point := newTestQueueWriterPoint(1)
+	require.Nil(t, queueWriter.WritePoint(context.Background(), point))
+
+	require.Equal(t, []string{write.PointToLineProtocol(point, time.Nanosecond)},
+		writer.getLines())
+
+	stats := queueWriter.GetStats()
+	require.Equal(t, 0, stats.Size)
+	require.Equal(t, int64(0), stats.WriteFailures)
+}
+
+func TestQueueWriterReplay(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	writer.setError(errors.New("connection refused"))
+
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	var want []string
+	for n := 0; n < 5; n++ {
+		point := newTestQueueWriterPoint(n)
+		want = append(want, write.PointToLineProtocol(point, time.Nanosecond))
+
+		require.Nil(t, queueWriter.WritePoint(context.Background(), point))
+	}
+	require.Empty(t, writer.getLines())
+
+	require.NotNil(t, queueWriter.Run())
+
+	stats := queueWriter.GetStats()
+	require.Equal(t, 5, stats.Size)
+	require.Equal(t, int64(2), stats.WriteFailures)
+
+	writer.setError(nil)
+
+	require.Nil(t, queueWriter.Run())
+	require.Equal(t, want, writer.getLines())
+
+	stats = queueWriter.GetStats()
+	require.Equal(t, 0, stats.Size)
+	require.Equal(t, int64(5), stats.Replayed)
+}
+
+func TestQueueWriterPreserveOrder(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	writer.setError(errors.New("connection refused"))
+
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	first := newTestQueueWriterPoint(1)
+	require.Nil(t, queueWriter.WritePoint(context.Background(), first))
+
+	writer.setError(nil)
+
+	// Queue isn't empty, the point should be queued even if influxdb is available.
+	second := newTestQueueWriterPoint(2)
+	require.Nil(t, queueWriter.WritePoint(context.Background(), second))
+	require.Empty(t, writer.getLines())
+
+	require.Nil(t, queueWriter.Run())
+	require.Equal(t, []string{
+		write.PointToLineProtocol(first, time.Nanosecond),
+		write.PointToLineProtocol(second, time.Nanosecond),
+	}, writer.getLines())
+}
+
+type testQueueWriterBlockingWriter struct {
+	testQueueWriterWriter
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *testQueueWriterBlockingWriter) WriteRecord(
+	ctx context.Context,
+	lines ...string,
+) error {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+
+	<-w.release
+
+	return w.testQueueWriterWriter.WriteRecord(ctx, lines...)
+}
+
+func TestQueueWriterReplayNotBlocking(t *testing.T) {
+	writer := &testQueueWriterBlockingWriter{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+
+	queue, err := stcore.NewQueue(&stcore.NoopDB{}, &syscore.LocalMonotonicClock{},
+		stcore.QueueParams{MaxSize: 10})
+	require.Nil(t, err)
+
+	first := newTestQueueWriterPoint(1)
+	require.Nil(t, queue.Push([]byte(write.PointToLineProtocol(first, time.Nanosecond))))
+
+	queueWriter := NewQueueWriter(context.Background(), writer, queue, 2)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- queueWriter.Run()
+	}()
+
+	<-writer.started
+
+	// Replay is in progress, the data is queued without waiting for influxdb.
+	second := newTestQueueWriterPoint(2)
+	require.Nil(t, queueWriter.WritePoint(context.Background(), second))
+	require.Equal(t, 2, queueWriter.GetStats().Size)
+
+	close(writer.release)
+	require.Nil(t, <-errCh)
+
+	require.Equal(t, []string{
+		write.PointToLineProtocol(first, time.Nanosecond),
+		write.PointToLineProtocol(second, time.Nanosecond),
+	}, writer.getLines())
+	require.Equal(t, int64(2), queueWriter.GetStats().Replayed)
+}
+
+type testQueueWriterFailingWriter struct {
+	testQueueWriterWriter
+
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+// WriteRecord fails the first write once it's released.
+func (w *testQueueWriterFailingWriter) WriteRecord(
+	ctx context.Context,
+	lines ...string,
+) error {
+	first := false
+	w.once.Do(func() {
+		first = true
+	})
+
+	if first {
+		close(w.started)
+		<-w.release
+
+		return errors.New("connection refused")
+	}
+
+	return w.testQueueWriterWriter.WriteRecord(ctx, lines...)
+}
+
+func TestQueueWriterConcurrentWritersOrder(t *testing.T) {
+	writer := &testQueueWriterFailingWriter{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	first := newTestQueueWriterPoint(1)
+	second := newTestQueueWriterPoint(2)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		require.Nil(t, queueWriter.WritePoint(context.Background(), first))
+	}()
+
+	<-writer.started
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		require.Nil(t, queueWriter.WritePoint(context.Background(), second))
+	}()
+
+	// Give the second writer a chance to race with the failing one.
+	time.Sleep(time.Millisecond * 50)
+	require.Empty(t, writer.getLines())
+
+	close(writer.release)
+	wg.Wait()
+
+	// The newer point isn't written before the older queued one.
+	require.Nil(t, queueWriter.Run())
+	require.Equal(t, []string{
+		write.PointToLineProtocol(first, time.Nanosecond),
+		write.PointToLineProtocol(second, time.Nanosecond),
+	}, writer.getLines())
+}
+
+func TestQueueWriterRejected(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	writer.setError(&influxhttp.Error{StatusCode: http.StatusBadRequest})
+
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	require.NotNil(t, queueWriter.WritePoint(context.Background(),
+		newTestQueueWriterPoint(1)))
+
+	stats := queueWriter.GetStats()
+	require.Equal(t, 0, stats.Size)
+	require.Equal(t, int64(1), stats.Rejected)
+}
+
+func TestQueueWriterReplayRejected(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	writer.setError(errors.New("connection refused"))
+
+	queueWriter := newTestQueueWriter(t, writer, 10)
+
+	for n := 0; n < 3; n++ {
+		require.Nil(t, queueWriter.WritePoint(context.Background(),
+			newTestQueueWriterPoint(n)))
+	}
+
+	writer.setError(&influxhttp.Error{StatusCode: http.StatusBadRequest})
+
+	require.Nil(t, queueWriter.Run())
+
+	stats := queueWriter.GetStats()
+	require.Equal(t, 0, stats.Size)
+	require.Equal(t, int64(3), stats.Rejected)
+}
+
+func TestQueueWriterQueueFull(t *testing.T) {
+	writer := &testQueueWriterWriter{}
+	writer.setError(errors.New("connection refused"))
+
+	queueWriter := newTestQueueWriter(t, writer, 2)
+
+	for n := 0; n < 3; n++ {
+		require.Nil(t, queueWriter.WritePoint(context.Background(),
+			newTestQueueWriterPoint(n)))
+	}
+
+	stats := queueWriter.GetStats()
+	require.Equal(t, 2, stats.Size)
+	require.Equal(t, int64(1), stats.DroppedFull)
+}
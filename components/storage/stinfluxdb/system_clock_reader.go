@@ -11,6 +11,8 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 )
 
+var clockReaderLogger = syscore.NewLogger("influxdb-system-clock-reader")
+
 // SystemClockReader reads the UNIX timestamp from the influxdb.
 type SystemClockReader struct {
 	bucket string
@@ -38,7 +40,7 @@ func (r *SystemClockReader) ReadTimestamp(ctx context.Context) (int64, error) {
 
 	result, err := r.client.Query(ctx, query)
 	if err != nil {
-		syscore.LogErr.Printf("failed to perform query: %v", err)
+		clockReaderLogger.Error("failed to perform query", "err", err)
 
 		// HACK: library doesn't return the specific errors, so it's hard to tell what's wrong.
 		if strings.Contains(err.Error(), "unauthorized") {
@@ -0,0 +1,86 @@
+package stprometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/open-control-systems/device-hub/components/device/devstore"
+)
+
+// Collector exports the latest device telemetry in the Prometheus format.
+//
+// Remarks:
+//   - Only devices present in the store are exported, telemetry of the removed
+//     devices is dropped on each collection.
+//   - Devices are labelled with the type and description from the store.
+type Collector struct {
+	handler *DataHandler
+	store   devstore.Store
+
+	telemetryDesc *prometheus.Desc
+	timestampDesc *prometheus.Desc
+}
+
+// NewCollector is an initialization of Collector.
+//
+// Parameters:
+//   - handler to get the latest device telemetry.
+//   - store to get the device type and description.
+func NewCollector(handler *DataHandler, store devstore.Store) *Collector {
+	return &Collector{
+		handler: handler,
+		store:   store,
+		telemetryDesc: prometheus.NewDesc(
+			"device_telemetry",
+			"Latest numeric telemetry field received from the device.",
+			[]string{"device_id", "type", "desc", "field"},
+			nil,
+		),
+		timestampDesc: prometheus.NewDesc(
+			"device_telemetry_timestamp_seconds",
+			"UNIX time of the latest device telemetry.",
+			[]string{"device_id", "type", "desc"},
+			nil,
+		),
+	}
+}
+
+// Describe sends the descriptors of all exported metrics.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.telemetryDesc
+	ch <- c.timestampDesc
+}
+
+// Collect sends the latest telemetry of all devices in the store.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	items := make(map[string]devstore.StoreItem)
+	for _, item := range c.store.GetDesc() {
+		if item.ID != "" {
+			items[item.ID] = item
+		}
+	}
+
+	var removed []string
+
+	c.handler.ForEach(func(deviceID string, timestamp float64, fields map[string]float64) {
+		item, ok := items[deviceID]
+		if !ok {
+			removed = append(removed, deviceID)
+
+			return
+		}
+
+		if timestamp >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.timestampDesc, prometheus.GaugeValue,
+				timestamp, deviceID, item.Type, item.Desc)
+		}
+
+		for field, value := range fields {
+			ch <- prometheus.MustNewConstMetric(c.telemetryDesc, prometheus.GaugeValue,
+				value, deviceID, item.Type, item.Desc, field)
+		}
+	})
+
+	for _, deviceID := range removed {
+		c.handler.Remove(deviceID)
+	}
+}
@@ -0,0 +1,90 @@
+package stprometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+	"github.com/open-control-systems/device-hub/components/device/devstore"
+)
+
+type testCollectorStore struct {
+	items []devstore.StoreItem
+}
+
+func (*testCollectorStore) Add(_ string, _ string, _ string, _ devstore.DeviceParams) error {
+	return nil
+}
+
+func (*testCollectorStore) Remove(_ string) error {
+	return nil
+}
+
+func (*testCollectorStore) Update(_ string, _ string, _ string) error {
+	return nil
+}
+
+func (s *testCollectorStore) GetDesc() []devstore.StoreItem {
+	return s.items
+}
+
+func TestCollectorCollect(t *testing.T) {
+	handler := NewDataHandler()
+
+	store := &testCollectorStore{
+		items: []devstore.StoreItem{
+			{URI: "push://foo", Type: "zero", Desc: "plant", ID: "0xABCD"},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	require.Nil(t, registry.Register(NewCollector(handler, store)))
+
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"timestamp":   float64(123),
+		"temperature": float64(23.5),
+		"status":      "ok",
+		"pump":        true,
+		"soil": map[string]any{
+			"moisture": float64(42),
+		},
+	}))
+
+	require.Nil(t, handler.HandleTelemetry("0xCBDE", devcore.JSON{
+		"timestamp":   float64(123),
+		"temperature": float64(11),
+	}))
+
+	want := `
+# HELP device_telemetry Latest numeric telemetry field received from the device.
+# TYPE device_telemetry gauge
+device_telemetry{desc="plant",device_id="0xABCD",field="pump",type="zero"} 1
+device_telemetry{desc="plant",device_id="0xABCD",field="soil.moisture",type="zero"} 42
+device_telemetry{desc="plant",device_id="0xABCD",field="temperature",type="zero"} 23.5
+# HELP device_telemetry_timestamp_seconds UNIX time of the latest device telemetry.
+# TYPE device_telemetry_timestamp_seconds gauge
+device_telemetry_timestamp_seconds{desc="plant",device_id="0xABCD",type="zero"} 123
+`
+	require.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(want)))
+
+	// Telemetry of the device missing in the store is dropped.
+	count := 0
+	handler.ForEach(func(_ string, _ float64, _ map[string]float64) {
+		count++
+	})
+	require.Equal(t, 1, count)
+
+	store.items = nil
+
+	require.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader("")))
+
+	count = 0
+	handler.ForEach(func(_ string, _ float64, _ map[string]float64) {
+		count++
+	})
+	require.Equal(t, 0, count)
+}
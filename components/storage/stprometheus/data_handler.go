@@ -0,0 +1,101 @@
+package stprometheus
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/device/devcore"
+)
+
+// DataHandler keeps the latest numeric telemetry fields per device in memory.
+//
+// Remarks:
+//   - Nested objects are flattened, keys are joined with ".".
+//   - The "timestamp" field is kept separately from the other fields.
+//   - Registration data is ignored.
+type DataHandler struct {
+	mu      sync.Mutex
+	devices map[string]*deviceTelemetry
+}
+
+type deviceTelemetry struct {
+	timestamp float64
+	fields    map[string]float64
+}
+
+// NewDataHandler is an initialization of DataHandler.
+func NewDataHandler() *DataHandler {
+	return &DataHandler{
+		devices: make(map[string]*deviceTelemetry),
+	}
+}
+
+// HandleTelemetry updates the latest telemetry fields for the device.
+func (h *DataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	telemetry := &deviceTelemetry{
+		timestamp: -1,
+		fields:    make(map[string]float64),
+	}
+
+	if timestamp, ok := js["timestamp"].(float64); ok {
+		telemetry.timestamp = timestamp
+	}
+
+	flattenFields(telemetry.fields, "", js)
+	delete(telemetry.fields, "timestamp")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.devices[deviceID] = telemetry
+
+	return nil
+}
+
+// HandleRegistration is non-operational.
+func (*DataHandler) HandleRegistration(_ string, _ devcore.JSON) error {
+	return nil
+}
+
+// Remove removes the telemetry for the device.
+func (h *DataHandler) Remove(deviceID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.devices, deviceID)
+}
+
+// ForEach iterates over the latest telemetry of all devices.
+func (h *DataHandler) ForEach(
+	fn func(deviceID string, timestamp float64, fields map[string]float64),
+) {
+	h.mu.Lock()
+	devices := maps.Clone(h.devices)
+	h.mu.Unlock()
+
+	// Telemetry is never modified after it's stored, so it's safe to use it without lock.
+	for deviceID, telemetry := range devices {
+		fn(deviceID, telemetry.timestamp, telemetry.fields)
+	}
+}
+
+func flattenFields(fields map[string]float64, prefix string, js devcore.JSON) {
+	for key, value := range js {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case float64:
+			fields[key] = v
+		case bool:
+			if v {
+				fields[key] = 1
+			} else {
+				fields[key] = 0
+			}
+		case map[string]any:
+			flattenFields(fields, key, v)
+		}
+	}
+}
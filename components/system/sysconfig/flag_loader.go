@@ -0,0 +1,321 @@
+package sysconfig
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+// FlagLoaderParams represents various configuration options for FlagLoader.
+type FlagLoaderParams struct {
+	// ConfigFlag is a name of the flag with the configuration file path.
+	ConfigFlag string
+
+	// EnvPrefix is a prefix of the environment variables, e.g. "DEVICE_HUB_".
+	EnvPrefix string
+
+	// Aliases maps configuration file keys to the flag names, for the keys that
+	// don't follow the flag naming.
+	Aliases map[string]string
+
+	// SecretFlags are flags which values are hidden when the configuration is printed.
+	SecretFlags []string
+}
+
+// FlagLoader applies configuration options from the file and the environment
+// variables to the command-line flags.
+//
+// Remarks:
+//   - Options are applied in the following order, each next source overrides
+//     the previous one: flag defaults, configuration file, environment variables,
+//     command-line flags.
+//   - Configuration file is either YAML or TOML, determined by the file extension.
+//   - Nested configuration file keys are joined with "-" to get the flag name,
+//     "_" is replaced with "-", e.g. device.http.fetch_interval is mapped to
+//     the device-http-fetch-interval flag.
+//   - Environment variable name is the flag name in upper case with "-" replaced
+//     with "_", prefixed with EnvPrefix, e.g. DEVICE_HUB_DEVICE_HTTP_FETCH_INTERVAL.
+type FlagLoader struct {
+	flags  *pflag.FlagSet
+	params FlagLoaderParams
+
+	// Flags set on the command line, captured by the first Load().
+	changed map[string]bool
+}
+
+// NewFlagLoader is an initialization of FlagLoader.
+//
+// Parameters:
+//   - flags to apply configuration options to.
+//   - params - various configuration options for the loader.
+func NewFlagLoader(flags *pflag.FlagSet, params FlagLoaderParams) *FlagLoader {
+	return &FlagLoader{
+		flags:  flags,
+		params: params,
+	}
+}
+
+// Load applies configuration options to the flags.
+//
+// Remarks:
+//   - Should be called after the command-line flags are parsed.
+//   - Unknown configuration file keys are reported as errors.
+func (l *FlagLoader) Load() error {
+	if l.changed == nil {
+		l.changed = make(map[string]bool)
+		l.flags.Visit(func(flag *pflag.Flag) {
+			l.changed[flag.Name] = true
+		})
+	}
+
+	return l.load(l.changed)
+}
+
+// Reload re-reads the configuration file and the environment variables.
+//
+// Remarks:
+//   - Should be called after Load().
+//   - Options that aren't set on the command line are reset to the flag defaults
+//     first, so an option removed from the configuration file gets its default value.
+func (l *FlagLoader) Reload() error {
+	if l.changed == nil {
+		return fmt.Errorf("%w: configuration isn't loaded", status.StatusInvalidState)
+	}
+
+	var err error
+
+	l.flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || l.changed[flag.Name] || flag.Name == "help" {
+			return
+		}
+
+		if err = resetFlag(flag); err != nil {
+			err = fmt.Errorf("sysconfig: failed to reset option: name=%s err=%w",
+				flag.Name, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return l.load(l.changed)
+}
+
+func (l *FlagLoader) load(changed map[string]bool) error {
+	if l.params.ConfigFlag != "" && !changed[l.params.ConfigFlag] {
+		if value, ok := os.LookupEnv(l.envName(l.params.ConfigFlag)); ok {
+			if err := l.flags.Set(l.params.ConfigFlag, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if path := l.configPath(); path != "" {
+		values, err := l.readFile(path)
+		if err != nil {
+			return fmt.Errorf("sysconfig: failed to read config file: path=%s err=%w",
+				path, err)
+		}
+
+		if err := l.apply(values, changed, "config file"); err != nil {
+			return err
+		}
+	}
+
+	return l.apply(l.readEnv(), changed, "environment")
+}
+
+// Write prints the effective configuration options in the configuration file format.
+//
+// Remarks:
+//   - Output is a flat YAML document, which can be used as a configuration file.
+func (l *FlagLoader) Write(w io.Writer) error {
+	secrets := make(map[string]bool)
+	for _, name := range l.params.SecretFlags {
+		secrets[name] = true
+	}
+
+	var flags []*pflag.Flag
+
+	l.flags.VisitAll(func(flag *pflag.Flag) {
+		flags = append(flags, flag)
+	})
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+
+	for _, flag := range flags {
+		if flag.Name == l.params.ConfigFlag || flag.Name == "help" {
+			continue
+		}
+
+		value := flag.Value.String()
+
+		if secrets[flag.Name] && value != "" {
+			value = "<hidden>"
+		}
+
+		if flag.Value.Type() == "string" {
+			value = strconv.Quote(value)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s\n", flag.Name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *FlagLoader) configPath() string {
+	if l.params.ConfigFlag == "" {
+		return ""
+	}
+
+	flag := l.flags.Lookup(l.params.ConfigFlag)
+	if flag == nil {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func (l *FlagLoader) readFile(path string) (map[string]string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(buf, &doc)
+	case ".toml":
+		err = toml.Unmarshal(buf, &doc)
+	default:
+		return nil, fmt.Errorf("%w: unsupported config file format: %s",
+			status.StatusNotSupported, filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if err := flattenValues(values, "", doc); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (l *FlagLoader) readEnv() map[string]string {
+	values := make(map[string]string)
+
+	l.flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == l.params.ConfigFlag || flag.Name == "help" {
+			return
+		}
+
+		if value, ok := os.LookupEnv(l.envName(flag.Name)); ok {
+			values[flag.Name] = value
+		}
+	})
+
+	return values
+}
+
+func (l *FlagLoader) apply(
+	values map[string]string,
+	changed map[string]bool,
+	source string,
+) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := key
+		if alias, ok := l.params.Aliases[key]; ok {
+			name = alias
+		}
+
+		if name == l.params.ConfigFlag || l.flags.Lookup(name) == nil {
+			return fmt.Errorf("%w: unknown %s option: %s", status.StatusInvalidArg, source, key)
+		}
+
+		if changed[name] {
+			continue
+		}
+
+		if err := l.flags.Set(name, values[key]); err != nil {
+			return fmt.Errorf("%w: invalid %s option: %s: %w",
+				status.StatusInvalidArg, source, key, err)
+		}
+	}
+
+	return nil
+}
+
+func resetFlag(flag *pflag.Flag) error {
+	if value, ok := flag.Value.(pflag.SliceValue); ok {
+		def := strings.Trim(flag.DefValue, "[]")
+		if def == "" {
+			return value.Replace(nil)
+		}
+
+		return value.Replace(strings.Split(def, ","))
+	}
+
+	return flag.Value.Set(flag.DefValue)
+}
+
+func (l *FlagLoader) envName(name string) string {
+	return l.params.EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func flattenValues(values map[string]string, prefix string, doc map[string]any) error {
+	for key, value := range doc {
+		name := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		if prefix != "" {
+			name = prefix + "-" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flattenValues(values, name, v); err != nil {
+				return err
+			}
+
+		case []any:
+			var items []string
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+
+			values[name] = strings.Join(items, ",")
+
+		case nil:
+			return fmt.Errorf("%w: missed value: %s", status.StatusInvalidArg, name)
+
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+
+	return nil
+}
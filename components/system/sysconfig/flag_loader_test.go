@@ -0,0 +1,242 @@
+package sysconfig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+type testFlagLoaderOptions struct {
+	config        string
+	backend       string
+	fetchInterval string
+	workers       int
+	jitter        float64
+	disable       bool
+	token         string
+	iface         string
+}
+
+func newTestFlagLoaderFlags(opts *testFlagLoaderOptions) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	flags.StringVar(&opts.config, "config", "", "config file")
+	flags.StringVar(&opts.backend, "storage", "influxdb", "storage backend")
+	flags.StringVar(&opts.fetchInterval, "device-http-fetch-interval", "5s", "fetch interval")
+	flags.IntVar(&opts.workers, "device-http-workers", 0, "workers")
+	flags.Float64Var(&opts.jitter, "device-http-fetch-jitter", 0.1, "jitter")
+	flags.BoolVar(&opts.disable, "mdns-server-disable", false, "disable mDNS server")
+	flags.StringVar(&opts.token, "storage-influxdb-api-token", "", "token")
+	flags.StringVar(&opts.iface, "mdns-server-iface", "", "interfaces")
+
+	return flags
+}
+
+func newTestFlagLoader(flags *pflag.FlagSet) *FlagLoader {
+	return NewFlagLoader(flags, FlagLoaderParams{
+		ConfigFlag:  "config",
+		EnvPrefix:   "TEST_FLAG_LOADER_",
+		Aliases:     map[string]string{"storage-backend": "storage"},
+		SecretFlags: []string{"storage-influxdb-api-token"},
+	})
+}
+
+func writeTestFlagLoaderFile(t *testing.T, name string, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(data), 0o600))
+
+	return path
+}
+
+func TestFlagLoaderYAML(t *testing.T) {
+	path := writeTestFlagLoaderFile(t, "config.yaml", `
+storage:
+  backend: none
+  influxdb:
+    api_token: secret
+device:
+  http:
+    fetch_interval: 10s
+    workers: 4
+    fetch_jitter: 0.5
+mdns:
+  server:
+    disable: true
+    iface: [eth0, wlan0]
+`)
+
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--config", path}))
+
+	require.Nil(t, newTestFlagLoader(flags).Load())
+	require.Equal(t, "none", opts.backend)
+	require.Equal(t, "10s", opts.fetchInterval)
+	require.Equal(t, 4, opts.workers)
+	require.Equal(t, 0.5, opts.jitter)
+	require.True(t, opts.disable)
+	require.Equal(t, "secret", opts.token)
+	require.Equal(t, "eth0,wlan0", opts.iface)
+}
+
+func TestFlagLoaderTOML(t *testing.T) {
+	path := writeTestFlagLoaderFile(t, "config.toml", `
+storage-backend = "file"
+
+[device.http]
+fetch_interval = "1m"
+workers = 8
+`)
+
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--config", path}))
+
+	require.Nil(t, newTestFlagLoader(flags).Load())
+	require.Equal(t, "file", opts.backend)
+	require.Equal(t, "1m", opts.fetchInterval)
+	require.Equal(t, 8, opts.workers)
+	require.Equal(t, 0.1, opts.jitter)
+}
+
+func TestFlagLoaderPrecedence(t *testing.T) {
+	path := writeTestFlagLoaderFile(t, "config.yml", `
+storage:
+  backend: none
+device:
+  http:
+    fetch_interval: 10s
+    workers: 4
+`)
+
+	t.Setenv("TEST_FLAG_LOADER_CONFIG", path)
+	t.Setenv("TEST_FLAG_LOADER_DEVICE_HTTP_FETCH_INTERVAL", "20s")
+	t.Setenv("TEST_FLAG_LOADER_DEVICE_HTTP_WORKERS", "2")
+
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--device-http-workers", "1"}))
+
+	require.Nil(t, newTestFlagLoader(flags).Load())
+	require.Equal(t, path, opts.config)
+	require.Equal(t, "none", opts.backend)
+	require.Equal(t, "20s", opts.fetchInterval)
+	require.Equal(t, 1, opts.workers)
+}
+
+func TestFlagLoaderReload(t *testing.T) {
+	path := writeTestFlagLoaderFile(t, "config.yaml", `
+storage:
+  backend: none
+device:
+  http:
+    fetch_interval: 10s
+    workers: 4
+`)
+
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--config", path, "--device-http-workers", "1"}))
+
+	loader := newTestFlagLoader(flags)
+	require.True(t, errors.Is(loader.Reload(), status.StatusInvalidState))
+
+	require.Nil(t, loader.Load())
+	require.Equal(t, "none", opts.backend)
+	require.Equal(t, "10s", opts.fetchInterval)
+	require.Equal(t, 1, opts.workers)
+
+	require.Nil(t, os.WriteFile(path, []byte(`
+device:
+  http:
+    fetch_interval: 30s
+    workers: 4
+`), 0o600))
+
+	require.Nil(t, loader.Reload())
+	require.Equal(t, "influxdb", opts.backend)
+	require.Equal(t, "30s", opts.fetchInterval)
+	require.Equal(t, 1, opts.workers)
+	require.Equal(t, path, opts.config)
+
+	require.Nil(t, os.WriteFile(path, []byte(`foo: bar`), 0o600))
+	require.NotNil(t, loader.Reload())
+}
+
+func TestFlagLoaderNoConfig(t *testing.T) {
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse(nil))
+
+	require.Nil(t, newTestFlagLoader(flags).Load())
+	require.Equal(t, "influxdb", opts.backend)
+	require.Equal(t, "5s", opts.fetchInterval)
+}
+
+func TestFlagLoaderInvalid(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		err  error
+	}{
+		{"unknown.yaml", "foo: bar", status.StatusInvalidArg},
+		{"config.yaml", "config: foo.yaml", status.StatusInvalidArg},
+		{"type.yaml", "device: {http: {workers: foo}}", status.StatusInvalidArg},
+		{"empty.yaml", "device: {http: {workers: }}", status.StatusInvalidArg},
+		{"config.json", "{}", status.StatusNotSupported},
+		{"malformed.toml", "foo = ", nil},
+	} {
+		path := writeTestFlagLoaderFile(t, test.name, test.data)
+
+		flags := newTestFlagLoaderFlags(&testFlagLoaderOptions{})
+		require.Nil(t, flags.Parse([]string{"--config", path}))
+
+		err := newTestFlagLoader(flags).Load()
+		require.NotNil(t, err, test.name)
+
+		if test.err != nil {
+			require.True(t, errors.Is(err, test.err), test.name)
+		}
+	}
+
+	t.Setenv("TEST_FLAG_LOADER_DEVICE_HTTP_WORKERS", "foo")
+
+	flags := newTestFlagLoaderFlags(&testFlagLoaderOptions{})
+	require.Nil(t, flags.Parse(nil))
+	require.True(t, errors.Is(newTestFlagLoader(flags).Load(), status.StatusInvalidArg))
+}
+
+func TestFlagLoaderWrite(t *testing.T) {
+	opts := &testFlagLoaderOptions{}
+	flags := newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--storage-influxdb-api-token", "secret"}))
+
+	loader := newTestFlagLoader(flags)
+
+	var buf bytes.Buffer
+	require.Nil(t, loader.Write(&buf))
+
+	require.Equal(t, `device-http-fetch-interval: "5s"
+device-http-fetch-jitter: 0.1
+device-http-workers: 0
+mdns-server-disable: false
+mdns-server-iface: ""
+storage: "influxdb"
+storage-influxdb-api-token: "<hidden>"
+`, buf.String())
+
+	path := writeTestFlagLoaderFile(t, "config.yaml", buf.String())
+
+	opts = &testFlagLoaderOptions{}
+	flags = newTestFlagLoaderFlags(opts)
+	require.Nil(t, flags.Parse([]string{"--config", path}))
+	require.Nil(t, newTestFlagLoader(flags).Load())
+	require.Equal(t, "5s", opts.fetchInterval)
+}
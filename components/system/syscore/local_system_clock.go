@@ -6,6 +6,9 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+// adjOffsetSingleshot is the adjtimex(2) mode to slew the clock like adjtime(3).
+const adjOffsetSingleshot = 0x8001
+
 // LocalSystemClock is used to set/get the current UNIX time.
 type LocalSystemClock struct{}
 
@@ -26,3 +29,33 @@ func (*LocalSystemClock) SetTimestamp(timestamp int64) error {
 func (*LocalSystemClock) GetTimestamp() (int64, error) {
 	return time.Now().Unix(), nil
 }
+
+// SetTimestampMilli sets the UNIX time in milliseconds via settimeofday(2) system call.
+func (*LocalSystemClock) SetTimestampMilli(timestamp int64) error {
+	tv := unix.NsecToTimeval(timestamp * int64(time.Millisecond))
+
+	return unix.Settimeofday(&tv)
+}
+
+// GetTimestampMilli returns the current UNIX time in milliseconds.
+func (*LocalSystemClock) GetTimestampMilli() (int64, error) {
+	return time.Now().UnixMilli(), nil
+}
+
+// AdjustTimestamp slews the UNIX time via adjtimex(2) system call.
+//
+// Remarks:
+//   - The clock is slewed at most by 0.5ms per second.
+//
+// References:
+//   - https://man7.org/linux/man-pages/man2/adjtimex.2.html
+func (*LocalSystemClock) AdjustTimestamp(offset time.Duration) error {
+	tx := unix.Timex{
+		Modes:  adjOffsetSingleshot,
+		Offset: offset.Microseconds(),
+	}
+
+	_, err := unix.Adjtimex(&tx)
+
+	return err
+}
@@ -1,30 +1,106 @@
 package syscore
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sync"
+
+	"github.com/open-control-systems/device-hub/components/status"
 )
 
-var (
-	// LogInf logs informational events.
-	LogInf = log.New(os.Stderr, "inf: ", log.LstdFlags)
-	// LogWrn logs warning events.
-	LogWrn = log.New(os.Stderr, "wrn: ", log.LstdFlags)
-	// LogErr logs error events.
-	LogErr = log.New(os.Stderr, "err: ", log.LstdFlags)
+// LogFormat defines how log records are formatted.
+type LogFormat int
+
+const (
+	// LogFormatText formats records as logfmt key=value pairs.
+	LogFormatText LogFormat = iota
+
+	// LogFormatJSON formats records as JSON objects, one object per line.
+	LogFormatJSON
 )
 
+// ParseLogFormat converts the string representation of the log format.
+func ParseLogFormat(str string) (LogFormat, error) {
+	switch str {
+	case "text", "logfmt":
+		return LogFormatText, nil
+	case "json":
+		return LogFormatJSON, nil
+	default:
+		return LogFormatText, fmt.Errorf("%w: unknown log format: %s",
+			status.StatusInvalidArg, str)
+	}
+}
+
+// ParseLogLevel converts the string representation of the log level:
+// debug, info, warn or error.
+func ParseLogLevel(str string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(str)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("%w: unknown log level: %s",
+			status.StatusInvalidArg, str)
+	}
+
+	return level, nil
+}
+
+// Log is the root logger, the output, format and level are configured for all
+// loggers derived from it, even for the already created ones.
+var Log = slog.New(&logHandler{state: logRoot})
+
+var logRoot = newLogState()
+
+// NewLogger returns the logger for the component, each record is annotated with
+// the component name.
+func NewLogger(component string) *slog.Logger {
+	return Log.With("component", component)
+}
+
+// SetLogLevel sets the minimum level of the logged records.
+func SetLogLevel(level slog.Level) {
+	logRoot.level.Set(level)
+}
+
+// SetLogFormat sets how log records are formatted.
+func SetLogFormat(format LogFormat) {
+	logRoot.mu.Lock()
+	defer logRoot.mu.Unlock()
+
+	logRoot.format = format
+	logRoot.rebuild()
+}
+
 // SetLogFile setups a log file for all loggers.
-func SetLogFile(path string) error {
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+//
+// Parameters:
+//   - path to the log file, records are appended to the existing file.
+//   - params - log file rotation options, rotation is disabled for zero params.
+//
+// Remarks:
+//   - The previous log file is closed, it allows to reopen the log file after
+//     it's rotated by the external tool.
+func SetLogFile(path string, params LogFileParams) error {
+	file, err := openLogFile(path, params, &LocalMonotonicClock{})
 	if err != nil {
 		return err
 	}
 
-	for _, logger := range []*log.Logger{LogInf, LogWrn, LogErr} {
-		logger.SetOutput(file)
-		logger.SetFlags(log.LUTC | log.Ldate | log.Ltime | log.Lmicroseconds | log.Lshortfile)
+	logRoot.mu.Lock()
+	defer logRoot.mu.Unlock()
+
+	prevFile := logRoot.file
+
+	logRoot.file = file
+	logRoot.output = file
+	logRoot.rebuild()
+
+	if prevFile != nil {
+		return prevFile.Close()
 	}
 
 	return nil
@@ -35,5 +111,119 @@ func LogCrash(err any) {
 	trace := make([]byte, 32*1024)
 	traceSize := runtime.Stack(trace, false)
 
-	LogErr.Printf("crash: %#v, %s", err, trace[:traceSize])
+	Log.Error("crash", "err", fmt.Sprintf("%#v", err), "trace", string(trace[:traceSize]))
+}
+
+type logState struct {
+	level slog.LevelVar
+
+	mu      sync.RWMutex
+	format  LogFormat
+	output  io.Writer
+	file    *logFile
+	handler slog.Handler
+}
+
+func newLogState() *logState {
+	state := &logState{
+		output: os.Stderr,
+	}
+	state.rebuild()
+
+	return state
+}
+
+// rebuild should be called with the mutex locked.
+func (s *logState) rebuild() {
+	opts := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       &s.level,
+		ReplaceAttr: replaceLogAttr,
+	}
+
+	if s.format == LogFormatJSON {
+		s.handler = slog.NewJSONHandler(s.output, opts)
+	} else {
+		s.handler = slog.NewTextHandler(s.output, opts)
+	}
+}
+
+func replaceLogAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) != 0 {
+		return attr
+	}
+
+	switch attr.Key {
+	case slog.TimeKey:
+		return slog.Time(slog.TimeKey, attr.Value.Time().UTC())
+
+	case slog.SourceKey:
+		if source, ok := attr.Value.Any().(*slog.Source); ok {
+			return slog.String(slog.SourceKey,
+				fmt.Sprintf("%s:%d", filepath.Base(source.File), source.Line))
+		}
+	}
+
+	return attr
+}
+
+// logHandler forwards records to the handler configured in logState, so the
+// derived loggers follow the output, format and level changes.
+type logHandler struct {
+	state *logState
+	ops   []logHandlerOp
+}
+
+// logHandlerOp is either a group or attributes added to the handler.
+type logHandlerOp struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.state.level.Level()
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.state.mu.RLock()
+	defer h.state.mu.RUnlock()
+
+	handler := h.state.handler
+
+	for _, op := range h.ops {
+		if op.group != "" {
+			handler = handler.WithGroup(op.group)
+		} else {
+			handler = handler.WithAttrs(op.attrs)
+		}
+	}
+
+	return handler.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return h.with(logHandlerOp{attrs: attrs})
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.with(logHandlerOp{group: name})
+}
+
+func (h *logHandler) with(op logHandlerOp) *logHandler {
+	ops := make([]logHandlerOp, 0, len(h.ops)+1)
+	ops = append(ops, h.ops...)
+	ops = append(ops, op)
+
+	return &logHandler{
+		state: h.state,
+		ops:   ops,
+	}
 }
@@ -0,0 +1,312 @@
+package syscore
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogFileParams represents various configuration options for the log file rotation.
+type LogFileParams struct {
+	// MaxSize is the maximum size of the log file in bytes before it's rotated,
+	// 0 disables the size-based rotation.
+	MaxSize int64
+
+	// MaxAge is the maximum time the log file is written before it's rotated,
+	// 0 disables the age-based rotation.
+	MaxAge time.Duration
+
+	// MaxFiles is the maximum number of retained rotated files, the oldest files
+	// are removed first, 0 means the rotated files aren't retained.
+	MaxFiles int
+
+	// Compress enables gzip compression of the rotated files.
+	Compress bool
+}
+
+// logFile is the log file rotated by size and age.
+//
+// Remarks:
+//   - The current file is renamed to path.1, the previously rotated files are
+//     shifted: path.1 becomes path.2 and so on, path.N is the oldest file.
+//   - Rotated files are suffixed with .gz if compression is enabled.
+//   - Rotated file is compressed in the background, so writes aren't blocked while
+//     the file is compressed. The next rotation waits for the compression to finish.
+//   - Writes are serialized, the file can be used by multiple loggers.
+type logFile struct {
+	path   string
+	params LogFileParams
+	clock  MonotonicClock
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	openTime     time.Time
+	compressDone chan struct{}
+}
+
+func openLogFile(
+	path string,
+	params LogFileParams,
+	clock MonotonicClock,
+) (*logFile, error) {
+	f := &logFile{
+		path:   path,
+		params: params,
+		clock:  clock,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write writes the log record to the file, the file is rotated before the write
+// if the record doesn't fit into the file or the file is too old.
+//
+// Remarks:
+//   - If rotation fails, the error is reported to stderr and the record is
+//     appended to the current file.
+func (f *logFile) Write(buf []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(len(buf)) {
+		if err := f.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to rotate log file: path=%s err=%v\n",
+				f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(buf)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file.
+//
+// Remarks:
+//   - Waits for the background compression of the rotated file to finish.
+func (f *logFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.waitCompress()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+func (f *logFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	f.file = file
+	f.size = fi.Size()
+	f.openTime = f.clock.Now()
+
+	return nil
+}
+
+func (f *logFile) shouldRotate(size int) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.params.MaxSize > 0 && f.size+int64(size) > f.params.MaxSize {
+		return true
+	}
+
+	if f.params.MaxAge > 0 && f.clock.Now().Sub(f.openTime) >= f.params.MaxAge {
+		return true
+	}
+
+	return false
+}
+
+func (f *logFile) rotate() error {
+	// The previously rotated file is shifted, its compression should be finished.
+	f.waitCompress()
+
+	if err := f.shiftRotated(); err != nil {
+		return err
+	}
+
+	// The file is renamed even if it can't be closed properly, the descriptor is
+	// released anyway.
+	closeErr := f.file.Close()
+
+	rotatedPath := f.path + ".1"
+
+	renameErr := os.Rename(f.path, rotatedPath)
+
+	// The current file is reopened even if it can't be renamed, the records are
+	// appended to it until the next rotation attempt.
+	if err := f.open(); err != nil {
+		f.file = nil
+
+		return err
+	}
+
+	if renameErr != nil {
+		return renameErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	if f.params.MaxFiles < 1 {
+		return os.Remove(rotatedPath)
+	}
+
+	if f.params.Compress {
+		done := make(chan struct{})
+		f.compressDone = done
+
+		go func() {
+			defer close(done)
+
+			if err := compressLogFile(rotatedPath); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to compress log file: path=%s err=%v\n",
+					rotatedPath, err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (f *logFile) waitCompress() {
+	if f.compressDone == nil {
+		return
+	}
+
+	<-f.compressDone
+	f.compressDone = nil
+}
+
+// shiftRotated shifts the rotated files to free the first slot, files that don't
+// fit into MaxFiles are removed.
+func (f *logFile) shiftRotated() error {
+	paths, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return err
+	}
+
+	type rotatedFile struct {
+		index  int
+		path   string
+		suffix string
+	}
+
+	var files []rotatedFile
+
+	for _, path := range paths {
+		name := strings.TrimPrefix(path, f.path+".")
+
+		suffix := ""
+		if strings.HasSuffix(name, ".gz") {
+			suffix = ".gz"
+			name = strings.TrimSuffix(name, suffix)
+		}
+
+		index, err := strconv.Atoi(name)
+		if err != nil || index < 1 {
+			continue
+		}
+
+		files = append(files, rotatedFile{index: index, path: path, suffix: suffix})
+	}
+
+	// The oldest files are handled first, so the shifted file never overwrites
+	// the file that is not shifted yet.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].index > files[j].index
+	})
+
+	for _, file := range files {
+		if file.index >= f.params.MaxFiles {
+			if err := os.Remove(file.path); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		newPath := fmt.Sprintf("%s.%d%s", f.path, file.index+1, file.suffix)
+		if err := os.Rename(file.path, newPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compressLogFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	gzPath := path + ".gz"
+
+	dst, err := os.OpenFile(gzPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(gzPath)
+		}
+	}()
+
+	writer := gzip.NewWriter(dst)
+
+	if _, err = io.Copy(writer, src); err != nil {
+		_ = dst.Close()
+
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		_ = dst.Close()
+
+		return err
+	}
+
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,299 @@
+package syscore
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testLogFileClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testLogFileClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testLogFileClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// waitTestLogFileRotated waits for the background compression of the rotated file.
+func waitTestLogFileRotated(f *logFile) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.waitCompress()
+}
+
+func listTestLogFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func readTestLogFile(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	if strings.HasSuffix(path, ".gz") {
+		gzReader, err := gzip.NewReader(file)
+		require.Nil(t, err)
+		defer gzReader.Close()
+
+		reader = gzReader
+	}
+
+	buf, err := io.ReadAll(reader)
+	require.Nil(t, err)
+
+	return string(buf)
+}
+
+func TestLogFileNoRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	require.Nil(t, os.WriteFile(path, []byte("foo\n"), 0666))
+
+	clock := &testLogFileClock{}
+
+	file, err := openLogFile(path, LogFileParams{}, clock)
+	require.Nil(t, err)
+
+	for n := 0; n < 100; n++ {
+		clock.advance(time.Hour)
+
+		_, err := file.Write([]byte("bar\n"))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, file.Close())
+
+	require.Equal(t, []string{"app.log"}, listTestLogFiles(t, dir))
+	require.Equal(t, "foo\n"+strings.Repeat("bar\n", 100), readTestLogFile(t, path))
+
+	_, err = file.Write([]byte("bar\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestLogFileRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	file, err := openLogFile(path, LogFileParams{
+		MaxSize:  8,
+		MaxFiles: 2,
+	}, &testLogFileClock{})
+	require.Nil(t, err)
+	defer file.Close()
+
+	for n := 0; n < 4; n++ {
+		_, err := file.Write([]byte(fmt.Sprintf("%03d\n", n)))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, []string{"app.log", "app.log.1"}, listTestLogFiles(t, dir))
+	require.Equal(t, "002\n003\n", readTestLogFile(t, path))
+	require.Equal(t, "000\n001\n", readTestLogFile(t, path+".1"))
+
+	for n := 4; n < 10; n++ {
+		_, err := file.Write([]byte(fmt.Sprintf("%03d\n", n)))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, []string{"app.log", "app.log.1", "app.log.2"},
+		listTestLogFiles(t, dir))
+	require.Equal(t, "008\n009\n", readTestLogFile(t, path))
+	require.Equal(t, "006\n007\n", readTestLogFile(t, path+".1"))
+	require.Equal(t, "004\n005\n", readTestLogFile(t, path+".2"))
+}
+
+func TestLogFileRotateLargeRecord(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	file, err := openLogFile(path, LogFileParams{
+		MaxSize:  4,
+		MaxFiles: 1,
+	}, &testLogFileClock{})
+	require.Nil(t, err)
+	defer file.Close()
+
+	// The record larger than the maximum size is written to the empty file.
+	_, err = file.Write([]byte("foo-bar\n"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"app.log"}, listTestLogFiles(t, dir))
+
+	_, err = file.Write([]byte("baz\n"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"app.log", "app.log.1"}, listTestLogFiles(t, dir))
+	require.Equal(t, "baz\n", readTestLogFile(t, path))
+	require.Equal(t, "foo-bar\n", readTestLogFile(t, path+".1"))
+}
+
+func TestLogFileRotateByAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	clock := &testLogFileClock{}
+
+	file, err := openLogFile(path, LogFileParams{
+		MaxAge:   time.Hour,
+		MaxFiles: 5,
+	}, clock)
+	require.Nil(t, err)
+	defer file.Close()
+
+	_, err = file.Write([]byte("foo\n"))
+	require.Nil(t, err)
+
+	clock.advance(time.Hour - time.Second)
+
+	_, err = file.Write([]byte("bar\n"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"app.log"}, listTestLogFiles(t, dir))
+
+	clock.advance(time.Second)
+
+	_, err = file.Write([]byte("baz\n"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"app.log", "app.log.1"}, listTestLogFiles(t, dir))
+	require.Equal(t, "baz\n", readTestLogFile(t, path))
+	require.Equal(t, "foo\nbar\n", readTestLogFile(t, path+".1"))
+}
+
+func TestLogFileRotateCompress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// Rotated file left from the previous run without compression.
+	require.Nil(t, os.WriteFile(path+".1", []byte("old\n"), 0666))
+
+	file, err := openLogFile(path, LogFileParams{
+		MaxSize:  4,
+		MaxFiles: 3,
+		Compress: true,
+	}, &testLogFileClock{})
+	require.Nil(t, err)
+	defer file.Close()
+
+	for _, record := range []string{"foo\n", "bar\n", "baz\n"} {
+		_, err := file.Write([]byte(record))
+		require.Nil(t, err)
+	}
+
+	waitTestLogFileRotated(file)
+
+	require.Equal(t, []string{"app.log", "app.log.1.gz", "app.log.2.gz", "app.log.3"},
+		listTestLogFiles(t, dir))
+	require.Equal(t, "baz\n", readTestLogFile(t, path))
+	require.Equal(t, "bar\n", readTestLogFile(t, path+".1.gz"))
+	require.Equal(t, "foo\n", readTestLogFile(t, path+".2.gz"))
+	require.Equal(t, "old\n", readTestLogFile(t, path+".3"))
+
+	_, err = file.Write([]byte("qux\n"))
+	require.Nil(t, err)
+
+	waitTestLogFileRotated(file)
+
+	require.Equal(t, []string{"app.log", "app.log.1.gz", "app.log.2.gz", "app.log.3.gz"},
+		listTestLogFiles(t, dir))
+	require.Equal(t, "foo\n", readTestLogFile(t, path+".3.gz"))
+}
+
+func TestLogFileRotateNoRetainedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	require.Nil(t, os.WriteFile(path+".1", []byte("old\n"), 0666))
+
+	file, err := openLogFile(path, LogFileParams{MaxSize: 4}, &testLogFileClock{})
+	require.Nil(t, err)
+	defer file.Close()
+
+	for _, record := range []string{"foo\n", "bar\n"} {
+		_, err := file.Write([]byte(record))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, []string{"app.log"}, listTestLogFiles(t, dir))
+	require.Equal(t, "bar\n", readTestLogFile(t, path))
+}
+
+func TestLogFileRotateConcurrentWrites(t *testing.T) {
+	const (
+		writerCount = 10
+		recordCount = 100
+		recordSize  = 8
+	)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	file, err := openLogFile(path, LogFileParams{
+		MaxSize:  recordSize * 10,
+		MaxFiles: writerCount * recordCount,
+	}, &testLogFileClock{})
+	require.Nil(t, err)
+	defer file.Close()
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < writerCount; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			for n := 0; n < recordCount; n++ {
+				_, err := file.Write([]byte(fmt.Sprintf("%02d-%04d\n", w, n)))
+				require.Nil(t, err)
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	var records []string
+
+	for _, name := range listTestLogFiles(t, dir) {
+		content := readTestLogFile(t, filepath.Join(dir, name))
+
+		require.LessOrEqual(t, len(content), recordSize*10)
+		records = append(records, strings.Split(strings.TrimSuffix(content, "\n"), "\n")...)
+	}
+
+	require.Equal(t, writerCount*recordCount, len(records))
+
+	for _, record := range records {
+		require.Equal(t, recordSize-1, len(record))
+	}
+}
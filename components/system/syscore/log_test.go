@@ -0,0 +1,105 @@
+package syscore
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+func readTestLogRecords(t *testing.T, path string) []map[string]any {
+	buf, err := os.ReadFile(path)
+	require.Nil(t, err)
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(string(buf)), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record map[string]any
+		require.Nil(t, json.Unmarshal([]byte(line), &record), line)
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogParse(t *testing.T) {
+	for str, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	} {
+		level, err := ParseLogLevel(str)
+		require.Nil(t, err)
+		require.Equal(t, want, level)
+	}
+
+	_, err := ParseLogLevel("foo")
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+
+	format, err := ParseLogFormat("json")
+	require.Nil(t, err)
+	require.Equal(t, LogFormatJSON, format)
+
+	format, err = ParseLogFormat("logfmt")
+	require.Nil(t, err)
+	require.Equal(t, LogFormatText, format)
+
+	_, err = ParseLogFormat("foo")
+	require.True(t, errors.Is(err, status.StatusInvalidArg))
+}
+
+func TestLogChildLogger(t *testing.T) {
+	defer SetLogLevel(slog.LevelInfo)
+	defer SetLogFormat(LogFormatText)
+
+	dir := t.TempDir()
+
+	// Logger is created before the output is configured.
+	logger := NewLogger("foo").With("uri", "push://bar")
+
+	SetLogFormat(LogFormatJSON)
+	SetLogLevel(slog.LevelWarn)
+
+	path := filepath.Join(dir, "app.log")
+	require.Nil(t, SetLogFile(path, LogFileParams{}))
+
+	logger.Info("filtered")
+	logger.Warn("logged", "count", 1)
+
+	records := readTestLogRecords(t, path)
+	require.Equal(t, 1, len(records))
+	require.Equal(t, "logged", records[0]["msg"])
+	require.Equal(t, "WARN", records[0]["level"])
+	require.Equal(t, "foo", records[0]["component"])
+	require.Equal(t, "push://bar", records[0]["uri"])
+	require.Equal(t, float64(1), records[0]["count"])
+	require.Equal(t, "log_test.go", strings.Split(records[0]["source"].(string), ":")[0])
+
+	SetLogLevel(slog.LevelDebug)
+
+	rotatedPath := filepath.Join(dir, "app.log.1")
+	require.Nil(t, os.Rename(path, rotatedPath))
+	require.Nil(t, SetLogFile(path, LogFileParams{}))
+
+	logger.Debug("reopened")
+
+	require.Equal(t, 1, len(readTestLogRecords(t, rotatedPath)))
+
+	records = readTestLogRecords(t, path)
+	require.Equal(t, 1, len(records))
+	require.Equal(t, "reopened", records[0]["msg"])
+	require.Equal(t, "push://bar", records[0]["uri"])
+}
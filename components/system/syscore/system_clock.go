@@ -1,5 +1,7 @@
 package syscore
 
+import "time"
+
 // SystemClock represents a UNIX time of the resource.
 type SystemClock interface {
 	// SetTimestamp sets the UNIX time for the resource.
@@ -17,3 +19,36 @@ type SystemClock interface {
 	//  - Implementation should be thread safe.
 	GetTimestamp() (int64, error)
 }
+
+// PreciseSystemClock represents a UNIX time of the resource with millisecond precision.
+type PreciseSystemClock interface {
+	// SetTimestampMilli sets the UNIX time in milliseconds for the resource.
+	//
+	// Requirements:
+	//  - Implementation should be thread safe.
+	SetTimestampMilli(timestamp int64) error
+
+	// GetTimestampMilli returns the UNIX time in milliseconds for the resource.
+	//
+	// Notes:
+	//  - -1 should be returned if the UNIX time isn't known.
+	//  - status.StatusNotSupported should be returned if the resource doesn't
+	//    support millisecond precision.
+	//
+	// Requirements:
+	//  - Implementation should be thread safe.
+	GetTimestampMilli() (int64, error)
+}
+
+// AdjustableSystemClock represents a UNIX time of the resource which can be gradually
+// adjusted.
+type AdjustableSystemClock interface {
+	PreciseSystemClock
+
+	// AdjustTimestamp gradually speeds up or slows down the clock until the offset is
+	// compensated, positive offset moves the clock forward.
+	//
+	// Requirements:
+	//  - Implementation should be thread safe.
+	AdjustTimestamp(offset time.Duration) error
+}
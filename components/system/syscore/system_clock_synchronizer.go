@@ -1,14 +1,44 @@
 package syscore
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
 	"github.com/open-control-systems/device-hub/components/status"
 )
 
+var (
+	// ErrLastRemoteAhead is returned if the last known remote UNIX time is ahead of
+	// the local UNIX time, the local time is likely invalid.
+	ErrLastRemoteAhead = fmt.Errorf("%w: last remote is ahead of local", status.StatusError)
+
+	// ErrRemoteAhead is returned if the current remote UNIX time is ahead of the
+	// local UNIX time, the remote time isn't moved back.
+	ErrRemoteAhead = fmt.Errorf("%w: current remote is ahead of local", status.StatusError)
+
+	// ErrSyncNotVerified is returned if the remote UNIX time read after the
+	// synchronization doesn't match the local UNIX time.
+	ErrSyncNotVerified = fmt.Errorf("%w: remote time isn't verified", status.StatusError)
+)
+
+// syncTolerance is the maximum error of the millisecond synchronization on top of the
+// network delay uncertainty.
+const syncTolerance = 100 * time.Millisecond
+
 // SystemClockSynchronizer synchronizes the UNIX time between local and remote resources.
+//
+// Remarks:
+//   - If both local and current remote clocks implement PreciseSystemClock, the remote
+//     UNIX time is set in milliseconds, compensated by the half of the request
+//     round-trip time, and verified with a follow-up read. The UNIX time in seconds
+//     is used if the remote resource doesn't support millisecond precision.
 type SystemClockSynchronizer struct {
 	local      SystemClock
 	remoteLast SystemClock
 	remoteCurr SystemClock
+	logger     *slog.Logger
 }
 
 // NewSystemClockSynchronizer initializes the component for the UNIX time synchronization.
@@ -26,10 +56,25 @@ func NewSystemClockSynchronizer(
 		local:      local,
 		remoteLast: remoteLast,
 		remoteCurr: remoteCurr,
+		logger:     NewLogger("system-clock-synchronizer"),
 	}
 }
 
+// SetLogger sets the logger for the synchronization events.
+//
+// Remarks:
+//   - Should be called before SyncTime().
+func (s *SystemClockSynchronizer) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
 // SyncTime synchronizes the UNIX time between local and remote resources.
+//
+// Remarks:
+//   - ErrLastRemoteAhead or ErrRemoteAhead is returned if the synchronization is
+//     refused.
+//   - ErrSyncNotVerified is returned if the remote UNIX time in milliseconds doesn't
+//     match the local UNIX time after the synchronization.
 func (s *SystemClockSynchronizer) SyncTime() error {
 	localTs, err := s.local.GetTimestamp()
 	if err != nil {
@@ -42,11 +87,23 @@ func (s *SystemClockSynchronizer) SyncTime() error {
 	}
 
 	if localTs < remoteLastTs {
-		LogWrn.Printf(
-			"system-clock-synchronizer: unable to sync: last remote is ahead of local: "+
-				"local=%v remote=%v", localTs, remoteLastTs)
+		s.logger.Warn("unable to sync time: last remote is ahead of local",
+			"local", localTs, "remote", remoteLastTs)
+
+		return ErrLastRemoteAhead
+	}
+
+	local, localOk := s.local.(PreciseSystemClock)
+	remote, remoteOk := s.remoteCurr.(PreciseSystemClock)
 
-		return status.StatusError
+	if localOk && remoteOk {
+		err := s.syncTimeMilli(local, remote)
+		if !errors.Is(err, status.StatusNotSupported) {
+			return err
+		}
+
+		s.logger.Debug("millisecond time sync isn't supported, fallback to seconds",
+			"err", err)
 	}
 
 	remoteCurrTs, err := s.remoteCurr.GetTimestamp()
@@ -55,20 +112,102 @@ func (s *SystemClockSynchronizer) SyncTime() error {
 	}
 
 	if localTs < remoteCurrTs {
-		LogWrn.Printf(
-			"unable to sync: current remote is ahead of local local=%v remote=%v",
-			localTs, remoteCurrTs)
+		s.logger.Warn("unable to sync time: current remote is ahead of local",
+			"local", localTs, "remote", remoteCurrTs)
 
-		return status.StatusError
+		return ErrRemoteAhead
 	}
 
 	if err := s.remoteCurr.SetTimestamp(localTs); err != nil {
 		return err
 	}
 
-	LogInf.Printf(
-		"system-clock-synchronizer: time synced: local=%v remote_last=%v remote_curr=%v",
-		localTs, remoteLastTs, remoteCurrTs)
+	s.logger.Info("time synced",
+		"local", localTs, "remote_last", remoteLastTs, "remote_curr", remoteCurrTs)
+
+	return nil
+}
+
+func (s *SystemClockSynchronizer) syncTimeMilli(
+	local PreciseSystemClock,
+	remote PreciseSystemClock,
+) error {
+	remoteTs, localTs, rtt, err := readTimestampMilli(local, remote)
+	if err != nil {
+		return err
+	}
+
+	// Sub-second difference is corrected, since the seconds aren't moved back.
+	if remoteTs-localTs >= time.Second.Milliseconds() {
+		s.logger.Warn("unable to sync time: current remote is ahead of local",
+			"local_ms", localTs, "remote_ms", remoteTs)
+
+		return ErrRemoteAhead
+	}
+
+	localTs, err = local.GetTimestampMilli()
+	if err != nil {
+		return err
+	}
+
+	if err := remote.SetTimestampMilli(localTs + rtt/2); err != nil {
+		return err
+	}
+
+	verifiedTs, expectedTs, verifyRtt, err := readTimestampMilli(local, remote)
+	if err != nil {
+		return err
+	}
+
+	// The remote resource ignored the UNIX time in milliseconds.
+	if verifiedTs < 0 {
+		return fmt.Errorf("%w: remote UNIX time in milliseconds isn't set",
+			status.StatusNotSupported)
+	}
+
+	syncErr := verifiedTs - expectedTs
+	if syncErr < 0 {
+		syncErr = -syncErr
+	}
+
+	if syncErr > (rtt+verifyRtt)/2+syncTolerance.Milliseconds() {
+		s.logger.Warn("unable to sync time: remote time isn't verified",
+			"local_ms", expectedTs, "remote_ms", verifiedTs,
+			"rtt", time.Duration(rtt)*time.Millisecond)
+
+		return ErrSyncNotVerified
+	}
+
+	s.logger.Info("time synced",
+		"local_ms", expectedTs, "remote_ms", remoteTs,
+		"rtt", time.Duration(rtt)*time.Millisecond,
+		"error", time.Duration(verifiedTs-expectedTs)*time.Millisecond)
 
 	return nil
 }
+
+// readTimestampMilli reads the remote UNIX time in milliseconds and returns it along
+// with the local UNIX time at the middle of the request and the request round-trip time.
+func readTimestampMilli(
+	local PreciseSystemClock,
+	remote PreciseSystemClock,
+) (int64, int64, int64, error) {
+	startTs, err := local.GetTimestampMilli()
+	if err != nil {
+		return -1, -1, -1, err
+	}
+
+	remoteTs, err := remote.GetTimestampMilli()
+	if err != nil {
+		return -1, -1, -1, err
+	}
+
+	endTs, err := local.GetTimestampMilli()
+	if err != nil {
+		return -1, -1, -1, err
+	}
+
+	rtt := endTs - startTs
+
+	return remoteTs, startTs + rtt/2, rtt, nil
+}
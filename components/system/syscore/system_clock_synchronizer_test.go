@@ -95,7 +95,10 @@ func TestSystemClockSynchronizerSynchronizeRemoteLastAheadOfLocal(t *testing.T)
 	}
 
 	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
-	require.Equal(t, status.StatusError, synchronizer.SyncTime())
+
+	err := synchronizer.SyncTime()
+	require.ErrorIs(t, err, ErrLastRemoteAhead)
+	require.ErrorIs(t, err, status.StatusError)
 }
 
 func TestSystemClockSynchronizerSynchronizeRemoteCurrError(t *testing.T) {
@@ -146,7 +149,10 @@ func TestSystemClockSynchronizerSynchronizeRemoteCurrAheadOfLocal(t *testing.T)
 	}
 
 	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
-	require.Equal(t, status.StatusError, synchronizer.SyncTime())
+
+	err := synchronizer.SyncTime()
+	require.ErrorIs(t, err, ErrRemoteAhead)
+	require.ErrorIs(t, err, status.StatusError)
 }
 
 func TestSystemClockSynchronizerSynchronizeRemoteSetTimestampError(t *testing.T) {
@@ -202,3 +208,170 @@ func TestSystemClockSynchronizerSynchronize(t *testing.T) {
 	require.Equal(t, localTimestamp, local.timestamp)
 	require.Equal(t, localTimestamp, remoteCurr.timestamp)
 }
+
+type testPreciseLocalClock struct {
+	testSystemClock
+
+	now  int64
+	step int64
+}
+
+func (c *testPreciseLocalClock) GetTimestamp() (int64, error) {
+	return c.now / 1000, nil
+}
+
+func (*testPreciseLocalClock) SetTimestampMilli(_ int64) error {
+	return status.StatusNotSupported
+}
+
+// GetTimestampMilli advances the clock by the step to emulate the network delay.
+func (c *testPreciseLocalClock) GetTimestampMilli() (int64, error) {
+	timestamp := c.now
+	c.now += c.step
+
+	return timestamp, nil
+}
+
+type testPreciseRemoteClock struct {
+	local *testPreciseLocalClock
+
+	valid        bool
+	offset       int64
+	notSupported bool
+	ignoreSet    bool
+	setBias      int64
+	timestamp    int64
+}
+
+func (c *testPreciseRemoteClock) GetTimestamp() (int64, error) {
+	if !c.valid {
+		return -1, nil
+	}
+
+	return (c.local.now + c.offset) / 1000, nil
+}
+
+func (c *testPreciseRemoteClock) SetTimestamp(timestamp int64) error {
+	c.timestamp = timestamp
+
+	return nil
+}
+
+func (c *testPreciseRemoteClock) GetTimestampMilli() (int64, error) {
+	if c.notSupported {
+		return -1, status.StatusNotSupported
+	}
+
+	if !c.valid {
+		return -1, nil
+	}
+
+	return c.local.now + c.offset, nil
+}
+
+func (c *testPreciseRemoteClock) SetTimestampMilli(timestamp int64) error {
+	if c.notSupported || c.ignoreSet {
+		return nil
+	}
+
+	c.valid = true
+	c.offset = timestamp + c.setBias - c.local.now
+
+	return nil
+}
+
+func newTestPreciseClocks(step int64) (
+	*testPreciseLocalClock,
+	*testSystemClock,
+	*testPreciseRemoteClock,
+) {
+	local := &testPreciseLocalClock{
+		now:  10_000_000,
+		step: step,
+	}
+
+	remoteLast := &testSystemClock{
+		timestamp: -1,
+	}
+
+	remoteCurr := &testPreciseRemoteClock{
+		local:     local,
+		timestamp: -1,
+	}
+
+	return local, remoteLast, remoteCurr
+}
+
+func TestSystemClockSynchronizerSynchronizeMilli(t *testing.T) {
+	for _, offset := range []int64{-5000, 900} {
+		local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+		remoteCurr.valid = true
+		remoteCurr.offset = offset
+
+		synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+		require.Nil(t, synchronizer.SyncTime())
+
+		// The remote clock is set to the local clock plus half of the round-trip time,
+		// the local clock has advanced by the step since the set request was sent.
+		require.Equal(t, int64(-10), remoteCurr.offset)
+		require.Equal(t, int64(-1), remoteCurr.timestamp)
+	}
+}
+
+func TestSystemClockSynchronizerSynchronizeMilliUnknownRemote(t *testing.T) {
+	local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+	require.Nil(t, synchronizer.SyncTime())
+	require.True(t, remoteCurr.valid)
+	require.Equal(t, int64(-10), remoteCurr.offset)
+	require.Equal(t, int64(-1), remoteCurr.timestamp)
+}
+
+func TestSystemClockSynchronizerSynchronizeMilliRemoteAheadOfLocal(t *testing.T) {
+	local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+	remoteCurr.valid = true
+	remoteCurr.offset = 2000
+
+	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+
+	err := synchronizer.SyncTime()
+	require.ErrorIs(t, err, ErrRemoteAhead)
+	require.Equal(t, int64(2000), remoteCurr.offset)
+}
+
+func TestSystemClockSynchronizerSynchronizeMilliNotSupported(t *testing.T) {
+	local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+	remoteCurr.notSupported = true
+
+	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+	require.Nil(t, synchronizer.SyncTime())
+	require.Equal(t, local.now/1000, remoteCurr.timestamp)
+}
+
+func TestSystemClockSynchronizerSynchronizeMilliSetIgnored(t *testing.T) {
+	local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+	remoteCurr.ignoreSet = true
+
+	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+	require.Nil(t, synchronizer.SyncTime())
+	require.False(t, remoteCurr.valid)
+	require.Equal(t, local.now/1000, remoteCurr.timestamp)
+}
+
+func TestSystemClockSynchronizerSynchronizeMilliNotVerified(t *testing.T) {
+	local, remoteLast, remoteCurr := newTestPreciseClocks(20)
+
+	remoteCurr.setBias = 1000
+
+	synchronizer := NewSystemClockSynchronizer(local, remoteLast, remoteCurr)
+
+	err := synchronizer.SyncTime()
+	require.ErrorIs(t, err, ErrSyncNotVerified)
+	require.ErrorIs(t, err, status.StatusError)
+	require.Equal(t, int64(-1), remoteCurr.timestamp)
+}
@@ -2,6 +2,8 @@ package sysmdns
 
 import "github.com/open-control-systems/device-hub/components/system/syscore"
 
+var fanoutServiceHandlerLogger = syscore.NewLogger("mdns-fanout-service-handler")
+
 // FanoutServiceHandler notifies the underlying handlers about discovered mDNS service.
 type FanoutServiceHandler struct {
 	handlers []ServiceHandler
@@ -11,7 +13,26 @@ type FanoutServiceHandler struct {
 func (h *FanoutServiceHandler) HandleService(service *Service) error {
 	for _, handler := range h.handlers {
 		if err := handler.HandleService(service); err != nil {
-			syscore.LogErr.Printf("failed to handle mDNS service: %v", err)
+			fanoutServiceHandlerLogger.Error("failed to handle mDNS service",
+				"instance", service.Instance, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// HandleServiceRemove notifies the underlying handlers which implement
+// ServiceRemoveHandler about removed mDNS service.
+func (h *FanoutServiceHandler) HandleServiceRemove(service *Service) error {
+	for _, handler := range h.handlers {
+		removeHandler, ok := handler.(ServiceRemoveHandler)
+		if !ok {
+			continue
+		}
+
+		if err := removeHandler.HandleServiceRemove(service); err != nil {
+			fanoutServiceHandlerLogger.Error("failed to handle removed mDNS service",
+				"instance", service.Instance, "err", err)
 		}
 	}
 
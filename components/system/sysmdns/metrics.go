@@ -0,0 +1,50 @@
+package sysmdns
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var (
+	browseTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "mdns",
+			Name:      "browse_total",
+			Help:      "Number of mDNS lookups by result.",
+		},
+		[]string{"service", "result"},
+	)
+
+	browseEntries = promauto.With(sysmetrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "mdns",
+			Name:      "browse_entries",
+			Help:      "Number of mDNS services discovered during the last lookup.",
+		},
+		[]string{"service"},
+	)
+
+	browseHandleFailuresTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "mdns",
+			Name:      "browse_handle_failures_total",
+			Help:      "Number of discovered mDNS services which failed to be handled.",
+		},
+		[]string{"service"},
+	)
+
+	browseRemovedTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "mdns",
+			Name:      "browse_removed_total",
+			Help:      "Number of mDNS services expired after not being seen for a while.",
+		},
+		[]string{"service"},
+	)
+)
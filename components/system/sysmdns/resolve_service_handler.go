@@ -35,3 +35,15 @@ func (h *ResolveServiceHandler) HandleService(service *Service) error {
 
 	return nil
 }
+
+// HandleServiceRemove invalidates the resolved address of the removed mDNS service.
+//
+// Remarks:
+//   - The address is invalidated only if the handler implements sysnet.UnresolveHandler.
+func (h *ResolveServiceHandler) HandleServiceRemove(service *Service) error {
+	if handler, ok := h.handler.(sysnet.UnresolveHandler); ok {
+		handler.HandleUnresolve(strings.TrimSuffix(service.Hostname, "."))
+	}
+
+	return nil
+}
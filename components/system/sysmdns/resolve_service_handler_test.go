@@ -18,6 +18,16 @@ func (h *testResolveServiceHandlerResolveHandler) HandleResolve(host string, add
 	h.addr = addr
 }
 
+type testResolveServiceHandlerUnresolveHandler struct {
+	testResolveServiceHandlerResolveHandler
+
+	unresolved string
+}
+
+func (h *testResolveServiceHandlerUnresolveHandler) HandleUnresolve(host string) {
+	h.unresolved = host
+}
+
 func TestResolveServiceHandlerIPv4(t *testing.T) {
 	resolveHandler := &testResolveServiceHandlerResolveHandler{}
 	serviceHandler := NewResolveServiceHandler(resolveHandler)
@@ -91,3 +101,22 @@ func TestResolveServiceHandlerIPv6FallbackError(t *testing.T) {
 	require.Empty(t, resolveHandler.host)
 	require.Nil(t, resolveHandler.addr)
 }
+
+func TestResolveServiceHandlerRemove(t *testing.T) {
+	resolveHandler := &testResolveServiceHandlerUnresolveHandler{}
+	serviceHandler := NewResolveServiceHandler(resolveHandler)
+
+	require.Nil(t, serviceHandler.HandleServiceRemove(&Service{
+		Hostname: "foo.local.",
+	}))
+	require.Equal(t, "foo.local", resolveHandler.unresolved)
+}
+
+func TestResolveServiceHandlerRemoveNotSupported(t *testing.T) {
+	resolveHandler := &testResolveServiceHandlerResolveHandler{}
+	serviceHandler := NewResolveServiceHandler(resolveHandler)
+
+	require.Nil(t, serviceHandler.HandleServiceRemove(&Service{
+		Hostname: "foo.local.",
+	}))
+}
@@ -5,3 +5,10 @@ type ServiceHandler interface {
 	// HandleService handles the mDNS service discovered over local network.
 	HandleService(service *Service) error
 }
+
+// ServiceRemoveHandler is a handler of the mDNS services removed from the local network.
+type ServiceRemoveHandler interface {
+	// HandleServiceRemove handles the mDNS service which left the local network, i.e.
+	// wasn't seen for a while.
+	HandleServiceRemove(service *Service) error
+}
@@ -14,6 +14,9 @@ type ServiceType int
 const (
 	// ServiceTypeHTTP is used for a HTTP mDNS service type.
 	ServiceTypeHTTP ServiceType = iota
+
+	// ServiceTypeNTP is used for a NTP/SNTP mDNS service type.
+	ServiceTypeNTP
 )
 
 // String returns string representation of the mDNS service type.
@@ -21,6 +24,8 @@ func (t ServiceType) String() string {
 	switch t {
 	case ServiceTypeHTTP:
 		return "_http"
+	case ServiceTypeNTP:
+		return "_ntp"
 	default:
 		return "<none>"
 	}
@@ -32,6 +37,9 @@ type Proto int
 const (
 	// ProtoTCP is used for application protocols that run over TCP.
 	ProtoTCP Proto = iota
+
+	// ProtoUDP is used for application protocols that run over UDP.
+	ProtoUDP
 )
 
 // String returns string representation of the mDNS protocol.
@@ -39,6 +47,8 @@ func (p Proto) String() string {
 	switch p {
 	case ProtoTCP:
 		return "_tcp"
+	case ProtoUDP:
+		return "_udp"
 	default:
 		return "<none>"
 	}
@@ -48,6 +58,7 @@ func (p Proto) String() string {
 //
 // Examples:
 //   - _http._tcp - HTTP service over TCP protocol.
+//   - _ntp._udp - NTP service over UDP protocol.
 func ServiceName(serviceType ServiceType, proto Proto) string {
 	return strings.Join([]string{serviceType.String(), proto.String()}, ".")
 }
@@ -7,8 +7,11 @@ import (
 	"github.com/open-control-systems/zeroconf"
 
 	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
 )
 
+var browserLogger = syscore.NewLogger("mdns-zeroconf-browser")
+
 // ZeroconfBrowserParams represents various options for zeroconf mDNS browser.
 type ZeroconfBrowserParams struct {
 	// Service is a mDNS service to lookup for.
@@ -26,18 +29,35 @@ type ZeroconfBrowserParams struct {
 	// Timeout is a mDNS browsing timeout.
 	Timeout time.Duration
 
+	// ExpireAfter is a number of consecutive lookups the service isn't seen before it's
+	// removed, 0 to disable the expiry.
+	ExpireAfter int
+
 	// Opts is a zeroconf browse configuration options.
 	Opts []zeroconf.ClientOption
 }
 
 // ZeroconfBrowser browses the local network for the mDNS devices.
 //
+// Remarks:
+//   - If the handler implements ServiceRemoveHandler, it's notified when the service
+//     isn't seen for ExpireAfter lookups.
+//   - mDNS goodbye packets (TTL=0) aren't handled, since the zeroconf resolver drops
+//     them before the entries are delivered to the browser.
+//
 // References:
 //   - https://github.com/grandcat/zeroconf
 type ZeroconfBrowser struct {
-	params  ZeroconfBrowserParams
-	ctx     context.Context
-	handler ServiceHandler
+	params   ZeroconfBrowserParams
+	ctx      context.Context
+	handler  ServiceHandler
+	services map[string]*browsedService
+}
+
+type browsedService struct {
+	service *Service
+	seen    bool
+	missed  int
 }
 
 // NewZeroconfBrowser is an initialization of ZeroconfBrowser.
@@ -47,17 +67,29 @@ func NewZeroconfBrowser(
 	params ZeroconfBrowserParams,
 ) *ZeroconfBrowser {
 	return &ZeroconfBrowser{
-		params:  params,
-		ctx:     ctx,
-		handler: handler,
+		params:   params,
+		ctx:      ctx,
+		handler:  handler,
+		services: make(map[string]*browsedService),
 	}
 }
 
 // Run executes a single mDNS lookup operation.
 func (b *ZeroconfBrowser) Run() error {
+	entryCount, err := b.browse()
+
+	browseTotal.WithLabelValues(b.params.Service, sysmetrics.ResultLabel(err)).Inc()
+	if err == nil {
+		browseEntries.WithLabelValues(b.params.Service).Set(float64(entryCount))
+	}
+
+	return err
+}
+
+func (b *ZeroconfBrowser) browse() (int, error) {
 	resolver, err := zeroconf.NewResolver(b.params.Opts...)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	ctx, cancel := context.WithTimeout(b.ctx, b.params.Timeout)
@@ -66,16 +98,22 @@ func (b *ZeroconfBrowser) Run() error {
 	entries := make(chan *zeroconf.ServiceEntry)
 
 	if err := resolver.Browse(ctx, b.params.Service, b.params.Domain, entries); err != nil {
-		return err
+		return 0, err
 	}
 
+	entryCount := 0
+
 	for {
 		select {
 		case entry := <-entries:
+			entryCount++
+
 			b.handleEntry(entry)
 
 		case <-ctx.Done():
-			return nil
+			b.expireServices()
+
+			return entryCount, nil
 		}
 	}
 }
@@ -87,8 +125,8 @@ func (*ZeroconfBrowser) Stop() error {
 
 // HandleError handles browsing errors.
 func (b *ZeroconfBrowser) HandleError(err error) {
-	syscore.LogErr.Printf("browsing failed: service=%s domain=%s: %v",
-		b.params.Service, b.params.Domain, err)
+	browserLogger.Error("browsing failed",
+		"service", b.params.Service, "domain", b.params.Domain, "err", err)
 }
 
 func (b *ZeroconfBrowser) handleEntry(entry *zeroconf.ServiceEntry) {
@@ -102,8 +140,54 @@ func (b *ZeroconfBrowser) handleEntry(entry *zeroconf.ServiceEntry) {
 		AddrsIPv6:  entry.AddrIPv6,
 	}
 
+	if b.params.ExpireAfter > 0 {
+		b.services[entry.ServiceInstanceName()] = &browsedService{
+			service: service,
+			seen:    true,
+		}
+	}
+
 	if err := b.handler.HandleService(service); err != nil {
-		syscore.LogWrn.Printf("failed to handle service: service=%s domain=%s err=%v",
-			b.params.Service, b.params.Domain, err)
+		browseHandleFailuresTotal.WithLabelValues(b.params.Service).Inc()
+
+		browserLogger.Warn("failed to handle service",
+			"service", b.params.Service, "domain", b.params.Domain, "err", err)
+	}
+}
+
+func (b *ZeroconfBrowser) expireServices() {
+	for key, browsed := range b.services {
+		if browsed.seen {
+			browsed.seen = false
+
+			continue
+		}
+
+		browsed.missed++
+
+		if browsed.missed >= b.params.ExpireAfter {
+			delete(b.services, key)
+
+			b.expireService(browsed.service)
+		}
+	}
+}
+
+func (b *ZeroconfBrowser) expireService(service *Service) {
+	browseRemovedTotal.WithLabelValues(b.params.Service).Inc()
+
+	browserLogger.Info("service expired",
+		"instance", service.Instance, "hostname", service.Hostname)
+
+	handler, ok := b.handler.(ServiceRemoveHandler)
+	if !ok {
+		return
+	}
+
+	if err := handler.HandleServiceRemove(service); err != nil {
+		browseHandleFailuresTotal.WithLabelValues(b.params.Service).Inc()
+
+		browserLogger.Warn("failed to handle removed service",
+			"service", b.params.Service, "domain", b.params.Domain, "err", err)
 	}
 }
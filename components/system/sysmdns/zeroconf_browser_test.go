@@ -0,0 +1,142 @@
+package sysmdns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/open-control-systems/zeroconf"
+	"github.com/stretchr/testify/require"
+)
+
+type testZeroconfBrowserHandler struct {
+	services []*Service
+	removed  []*Service
+}
+
+func (h *testZeroconfBrowserHandler) HandleService(service *Service) error {
+	h.services = append(h.services, service)
+
+	return nil
+}
+
+func (h *testZeroconfBrowserHandler) HandleServiceRemove(service *Service) error {
+	h.removed = append(h.removed, service)
+
+	return nil
+}
+
+func newTestZeroconfBrowserEntry(instance string) *zeroconf.ServiceEntry {
+	entry := zeroconf.NewServiceEntry(instance, "_http._tcp", "local")
+	entry.HostName = instance + ".local."
+	entry.AddrIPv4 = []net.IP{net.IPv4(192, 168, 0, 10)}
+	entry.TTL = 120
+
+	return entry
+}
+
+func newTestZeroconfBrowser(
+	handler ServiceHandler,
+	expireAfter int,
+) *ZeroconfBrowser {
+	return NewZeroconfBrowser(context.Background(), handler, ZeroconfBrowserParams{
+		Service:     "_http._tcp",
+		Domain:      "local",
+		ExpireAfter: expireAfter,
+	})
+}
+
+func TestZeroconfBrowserExpire(t *testing.T) {
+	handler := &testZeroconfBrowserHandler{}
+	browser := newTestZeroconfBrowser(handler, 2)
+
+	browser.handleEntry(newTestZeroconfBrowserEntry("foo"))
+	browser.handleEntry(newTestZeroconfBrowserEntry("bar"))
+	browser.expireServices()
+
+	browser.handleEntry(newTestZeroconfBrowserEntry("bar"))
+	browser.expireServices()
+	require.Empty(t, handler.removed)
+
+	// Service is seen again, the missed lookups are reset.
+	browser.handleEntry(newTestZeroconfBrowserEntry("foo"))
+	browser.expireServices()
+	require.Empty(t, handler.removed)
+
+	browser.expireServices()
+	require.Equal(t, 1, len(handler.removed))
+	require.Equal(t, "bar", handler.removed[0].Instance)
+
+	browser.expireServices()
+	require.Equal(t, 2, len(handler.removed))
+	require.Equal(t, "foo", handler.removed[1].Instance)
+
+	browser.expireServices()
+	require.Equal(t, 2, len(handler.removed))
+}
+
+func TestZeroconfBrowserExpireDisabled(t *testing.T) {
+	handler := &testZeroconfBrowserHandler{}
+	browser := newTestZeroconfBrowser(handler, 0)
+
+	browser.handleEntry(newTestZeroconfBrowserEntry("foo"))
+
+	for n := 0; n < 10; n++ {
+		browser.expireServices()
+	}
+
+	require.Empty(t, handler.removed)
+}
+
+func TestZeroconfBrowserBrowse(t *testing.T) {
+	var ifaces []net.Interface
+
+	allIfaces, err := net.Interfaces()
+	require.Nil(t, err)
+
+	for _, iface := range allIfaces {
+		if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagMulticast != 0 {
+			ifaces = append(ifaces, iface)
+		}
+	}
+
+	if len(ifaces) == 0 {
+		t.Skip("no multicast network interfaces")
+	}
+
+	serviceName := "_devhub-test._tcp"
+
+	server := NewZeroconfServer([]*Service{
+		{
+			Instance:   "device-hub-test",
+			Name:       serviceName,
+			Hostname:   "device-hub-test",
+			Port:       8081,
+			TxtRecords: []string{"foo=bar"},
+		},
+	}, ifaces)
+	require.Nil(t, server.Start())
+
+	handler := &testZeroconfBrowserHandler{}
+	browser := NewZeroconfBrowser(context.Background(), handler, ZeroconfBrowserParams{
+		Service:     serviceName,
+		Domain:      "local",
+		Timeout:     time.Second,
+		ExpireAfter: 1,
+		Opts:        []zeroconf.ClientOption{zeroconf.SelectIfaces(ifaces)},
+	})
+
+	require.Nil(t, browser.Run())
+	require.NotEmpty(t, handler.services)
+	require.Equal(t, "device-hub-test", handler.services[0].Instance)
+	require.Equal(t, []string{"foo=bar"}, handler.services[0].TxtRecords)
+	require.Empty(t, handler.removed)
+
+	// Goodbye packets sent on shutdown aren't delivered, the service expires instead.
+	require.Nil(t, server.Stop())
+
+	require.Nil(t, browser.Run())
+	require.Equal(t, 1, len(handler.removed))
+	require.Equal(t, "device-hub-test", handler.removed[0].Instance)
+}
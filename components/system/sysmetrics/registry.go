@@ -0,0 +1,22 @@
+package sysmetrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Namespace is a common prefix for all device-hub metrics.
+const Namespace = "device_hub"
+
+// Registry contains the device-hub self-metrics.
+//
+// Remarks:
+//   - Components register their metrics on initialization, the registry should be
+//     exposed by the application, e.g. over HTTP.
+var Registry = prometheus.NewRegistry()
+
+// ResultLabel returns the label value for the operation result.
+func ResultLabel(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
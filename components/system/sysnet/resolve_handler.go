@@ -7,3 +7,9 @@ type ResolveHandler interface {
 	// HandleResolve handles the resolving result of hostname to addr.
 	HandleResolve(hostname string, addr net.Addr)
 }
+
+// UnresolveHandler to handle the network address which is no longer valid.
+type UnresolveHandler interface {
+	// HandleUnresolve handles the hostname which address is no longer valid.
+	HandleUnresolve(hostname string)
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 )
 
+var resolveStoreLogger = syscore.NewLogger("resolve-store")
+
 // ResolveStore caches the result of hostname resolving.
 type ResolveStore struct {
 	updateCh chan struct{}
 
 	mu            sync.Mutex
-	knownHosts    map[string]struct{}
+	knownHosts    map[string]int
 	resolvedAddrs map[string]net.Addr
 }
 
@@ -22,7 +24,7 @@ type ResolveStore struct {
 func NewResolveStore() *ResolveStore {
 	return &ResolveStore{
 		updateCh:      make(chan struct{}, 1),
-		knownHosts:    make(map[string]struct{}),
+		knownHosts:    make(map[string]int),
 		resolvedAddrs: make(map[string]net.Addr),
 	}
 }
@@ -41,11 +43,11 @@ func (s *ResolveStore) HandleResolve(hostname string, addr net.Addr) {
 
 	ra, ok := s.resolvedAddrs[hostname]
 	if !ok {
-		syscore.LogInf.Printf("addr resolved: hostname=%s: addr=%s", hostname, addr)
+		resolveStoreLogger.Info("addr resolved", "hostname", hostname, "addr", addr)
 
 		s.resolvedAddrs[hostname] = addr
 	} else if ra.String() != addr.String() {
-		syscore.LogInf.Printf("addr changed: hostname=%s: cur=%s new=%s", hostname, ra, addr)
+		resolveStoreLogger.Info("addr changed", "hostname", hostname, "cur", ra, "new", addr)
 
 		s.resolvedAddrs[hostname] = addr
 	}
@@ -56,6 +58,21 @@ func (s *ResolveStore) HandleResolve(hostname string, addr net.Addr) {
 	}
 }
 
+// HandleUnresolve removes the cached address, the hostname is still known.
+func (s *ResolveStore) HandleUnresolve(hostname string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addr, ok := s.resolvedAddrs[hostname]
+	if !ok {
+		return
+	}
+
+	resolveStoreLogger.Info("addr expired", "hostname", hostname, "addr", addr)
+
+	delete(s.resolvedAddrs, hostname)
+}
+
 // Resolve resolves the hostname to the network address.
 //
 // Remarks:
@@ -69,18 +86,31 @@ func (s *ResolveStore) Resolve(ctx context.Context, hostname string) (net.Addr,
 }
 
 // Add adds hostname to the list of known hosts.
+//
+// Remarks:
+//   - Hostname can be added multiple times, it's known until each Add() is paired
+//     with Remove().
 func (s *ResolveStore) Add(hostname string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.knownHosts[hostname] = struct{}{}
+	s.knownHosts[hostname]++
 }
 
 // Remove removes hostname from the list of known hosts.
+//
+// Remarks:
+//   - Cached address is removed when the hostname isn't referenced anymore.
 func (s *ResolveStore) Remove(hostname string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.knownHosts[hostname] > 1 {
+		s.knownHosts[hostname]--
+
+		return
+	}
+
 	delete(s.knownHosts, hostname)
 	delete(s.resolvedAddrs, hostname)
 }
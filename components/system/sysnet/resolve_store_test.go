@@ -120,3 +120,49 @@ func TestResolveStoreResolveAfterRemove(t *testing.T) {
 	require.Equal(t, status.StatusNoData, err)
 	require.Nil(t, addr)
 }
+
+func TestResolveStoreAddMultipleTimes(t *testing.T) {
+	store := NewResolveStore()
+
+	mdnsHostName := "foo.bar.local"
+	netAddr := net.IPAddr{IP: net.IPv4(192, 168, 4, 2)}
+
+	store.Add(mdnsHostName)
+	store.Add(mdnsHostName)
+	store.HandleResolve(mdnsHostName, &netAddr)
+
+	store.Remove(mdnsHostName)
+	addr, err := store.Resolve(context.Background(), mdnsHostName)
+	require.Nil(t, err)
+	require.Equal(t, netAddr.String(), addr.String())
+
+	store.Remove(mdnsHostName)
+	addr, err = store.Resolve(context.Background(), mdnsHostName)
+	require.Equal(t, status.StatusNoData, err)
+	require.Nil(t, addr)
+}
+
+func TestResolveStoreHandleUnresolve(t *testing.T) {
+	store := NewResolveStore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	mdnsHostName := "foo.bar.local"
+	netAddr := net.IPAddr{IP: net.IPv4(192, 168, 4, 2)}
+
+	store.Add(mdnsHostName)
+	store.HandleResolve(mdnsHostName, &netAddr)
+	store.HandleUnresolve(mdnsHostName)
+
+	addr, err := store.Resolve(ctx, mdnsHostName)
+	require.Nil(t, addr)
+	require.NotNil(t, err)
+
+	// Hostname is still known.
+	store.HandleResolve(mdnsHostName, &netAddr)
+
+	addr, err = store.Resolve(ctx, mdnsHostName)
+	require.Nil(t, err)
+	require.Equal(t, netAddr.String(), addr.String())
+}
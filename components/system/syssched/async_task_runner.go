@@ -2,11 +2,15 @@ package syssched
 
 import (
 	"context"
+	"math/rand/v2"
 	"time"
 )
 
 // AsyncTaskRunnerParams represents various configuration options for AsyncTaskRunner.
 type AsyncTaskRunnerParams struct {
+	// Name is used to identify the task in the metrics.
+	Name string
+
 	// UpdateInterval is how often a task should be run.
 	UpdateInterval time.Duration
 
@@ -15,16 +19,45 @@ type AsyncTaskRunnerParams struct {
 
 	// DisableRecoverOnPanic is used to disable automatic panic-recovery mechanism.
 	DisableRecoverOnPanic bool
+
+	// BackoffInitial is how long to wait after the first failed run, the interval
+	// grows with each consecutive failure and is reset after the successful run.
+	// Zero to disable backoff, UpdateInterval is used then.
+	BackoffInitial time.Duration
+
+	// BackoffMax is the maximum interval between the failed runs, zero for no limit.
+	BackoffMax time.Duration
+
+	// BackoffMultiplier is how much the interval grows after each failed run, 2 if unset.
+	BackoffMultiplier float64
+
+	// Jitter randomizes each interval within [interval*(1-Jitter), interval*(1+Jitter)],
+	// should be in the [0, 1] range, zero to disable.
+	Jitter float64
+
+	// FirstRunDelay is the upper bound of the randomized delay before the first run,
+	// zero to run task immediately.
+	FirstRunDelay time.Duration
+
+	// Clock to schedule task runs, LocalClock if unset.
+	Clock Clock
+
+	// Rand to randomize intervals, global random source if unset.
+	//
+	// Remarks:
+	//  - Rand is accessed by a single goroutine at a time.
+	Rand *rand.Rand
 }
 
 // AsyncTaskRunner periodically runs task in the standalone goroutine.
 type AsyncTaskRunner struct {
-	ctx     context.Context
-	doneCh  chan struct{}
-	awakeCh chan struct{}
-	task    Task
-	handler ErrorHandler
-	params  AsyncTaskRunnerParams
+	ctx      context.Context
+	doneCh   chan struct{}
+	awakeCh  chan struct{}
+	task     Task
+	handler  ErrorHandler
+	params   AsyncTaskRunnerParams
+	schedule *taskSchedule
 }
 
 // NewAsyncTaskRunner is an initialization of AsyncTaskRunner.
@@ -34,21 +67,16 @@ func NewAsyncTaskRunner(
 	handler ErrorHandler,
 	params AsyncTaskRunnerParams,
 ) *AsyncTaskRunner {
-	var runnerTask Task
-
-	if params.DisableRecoverOnPanic {
-		runnerTask = task
-	} else {
-		runnerTask = NewCrashTask(task)
-	}
+	schedule := newTaskSchedule(params)
 
 	return &AsyncTaskRunner{
-		ctx:     ctx,
-		doneCh:  make(chan struct{}),
-		awakeCh: make(chan struct{}, 1),
-		task:    runnerTask,
-		handler: handler,
-		params:  params,
+		ctx:      ctx,
+		doneCh:   make(chan struct{}),
+		awakeCh:  make(chan struct{}, 1),
+		task:     makeRunnerTask(task, params),
+		handler:  handler,
+		params:   schedule.params,
+		schedule: schedule,
 	}
 }
 
@@ -74,42 +102,52 @@ func (r *AsyncTaskRunner) Awake() {
 	}
 }
 
+// SetUpdateInterval changes how often a task should be run.
+//
+// Remarks:
+//   - Task is run immediately to apply the new interval, if the interval is changed.
+func (r *AsyncTaskRunner) SetUpdateInterval(interval time.Duration) {
+	if r.schedule.setUpdateInterval(interval) {
+		r.Awake()
+	}
+}
+
 func (r *AsyncTaskRunner) run() {
 	defer close(r.doneCh)
 
-	ticker := time.NewTicker(r.params.UpdateInterval)
-	defer ticker.Stop()
-
-	if r.runTask() {
-		return
+	if delay := r.schedule.firstRunDelay(); delay > 0 {
+		if !r.wait(delay) {
+			return
+		}
 	}
 
 	for {
-		select {
-		case <-ticker.C:
-			if r.runTask() {
-				return
-			}
-
-		case <-r.awakeCh:
-			if r.runTask() {
-				return
-			}
-
-		case <-r.ctx.Done():
+		start := r.params.Clock.Now()
+
+		err := runTask(r.params.Name, r.task, r.handler)
+		if err == nil && r.params.ExitOnSuccess {
+			return
+		}
+
+		r.schedule.update(err)
+
+		delay := r.schedule.nextInterval() - r.params.Clock.Now().Sub(start)
+
+		if !r.wait(delay) {
 			return
 		}
 	}
 }
 
-func (r *AsyncTaskRunner) runTask() bool {
-	if err := r.task.Run(); err != nil {
-		if r.handler != nil {
-			r.handler.HandleError(err)
-		}
+func (r *AsyncTaskRunner) wait(delay time.Duration) bool {
+	select {
+	case <-r.params.Clock.After(delay):
+		return true
+
+	case <-r.awakeCh:
+		return true
 
+	case <-r.ctx.Done():
 		return false
 	}
-
-	return r.params.ExitOnSuccess
 }
@@ -2,12 +2,15 @@ package syssched
 
 import (
 	"context"
+	"math/rand/v2"
 	"sync"
 	"testing"
 	"time"
 
-	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
 )
 
 type testAsyncTaskRunnerTestTask struct {
@@ -62,3 +65,278 @@ func TestAsyncTaskRunnerExitOnSuccess(t *testing.T) {
 	task.setError(nil)
 	require.Nil(t, runner.Stop())
 }
+
+func TestAsyncTaskRunnerMetrics(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{
+		err: status.StatusNotSupported,
+	}
+	ctx := context.Background()
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		Name:           "test-metrics-task",
+		UpdateInterval: time.Millisecond * 10,
+		ExitOnSuccess:  true,
+	})
+	require.Nil(t, runner.Start())
+
+	for task.getCallCount() < 2 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	task.setError(nil)
+	require.Nil(t, runner.Stop())
+
+	failures := testutil.ToFloat64(taskRunTotal.WithLabelValues("test-metrics-task", "failure"))
+	require.Equal(t, float64(task.getCallCount()-1), failures)
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(taskRunTotal.WithLabelValues("test-metrics-task", "success")))
+}
+
+type testAsyncTaskRunnerClock struct {
+	now    time.Time
+	delays chan time.Duration
+	fireCh chan time.Time
+}
+
+func newTestAsyncTaskRunnerClock() *testAsyncTaskRunnerClock {
+	return &testAsyncTaskRunnerClock{
+		now:    time.Now(),
+		delays: make(chan time.Duration),
+		fireCh: make(chan time.Time),
+	}
+}
+
+func (c *testAsyncTaskRunnerClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testAsyncTaskRunnerClock) After(d time.Duration) <-chan time.Time {
+	c.delays <- d
+
+	return c.fireCh
+}
+
+func (c *testAsyncTaskRunnerClock) fire() {
+	c.fireCh <- c.now
+}
+
+func TestAsyncTaskRunnerBackoff(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{
+		err: status.StatusError,
+	}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 100,
+		BackoffInitial: time.Second,
+		BackoffMax:     time.Second * 5,
+		Clock:          clock,
+	})
+	require.Nil(t, runner.Start())
+
+	for _, want := range []time.Duration{
+		time.Second,
+		time.Second * 2,
+		time.Second * 4,
+		time.Second * 5,
+		time.Second * 5,
+	} {
+		require.Equal(t, want, <-clock.delays)
+		clock.fire()
+	}
+
+	require.Equal(t, time.Second*5, <-clock.delays)
+	task.setError(nil)
+	clock.fire()
+
+	require.Equal(t, time.Millisecond*100, <-clock.delays)
+	require.Equal(t, 7, task.getCallCount())
+	task.setError(status.StatusError)
+	clock.fire()
+
+	require.Equal(t, time.Second, <-clock.delays)
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerSkipped(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{
+		err: status.StatusError,
+	}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		Name:           "test-skipped-task",
+		UpdateInterval: time.Millisecond * 100,
+		BackoffInitial: time.Second,
+		BackoffMax:     time.Second * 10,
+		Clock:          clock,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Equal(t, time.Second, <-clock.delays)
+	task.setError(ErrTaskSkipped)
+	clock.fire()
+
+	// Skipped runs don't reset the backoff.
+	require.Equal(t, time.Second, <-clock.delays)
+	clock.fire()
+	require.Equal(t, time.Second, <-clock.delays)
+	task.setError(status.StatusError)
+	clock.fire()
+
+	require.Equal(t, time.Second*2, <-clock.delays)
+
+	cancel()
+	require.Nil(t, runner.Stop())
+
+	require.Equal(t, float64(2), testutil.ToFloat64(
+		taskRunTotal.WithLabelValues("test-skipped-task", "failure")))
+	require.Equal(t, float64(0), testutil.ToFloat64(
+		taskRunTotal.WithLabelValues("test-skipped-task", "success")))
+}
+
+func TestAsyncTaskRunnerBackoffMultiplier(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{
+		err: status.StatusError,
+	}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval:    time.Second,
+		BackoffInitial:    time.Second,
+		BackoffMultiplier: 3,
+		Clock:             clock,
+	})
+	require.Nil(t, runner.Start())
+
+	for _, want := range []time.Duration{
+		time.Second,
+		time.Second * 3,
+		time.Second * 9,
+		time.Second * 27,
+	} {
+		require.Equal(t, want, <-clock.delays)
+		clock.fire()
+	}
+
+	<-clock.delays
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerJitter(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	interval := time.Second
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: interval,
+		Jitter:         0.5,
+		Clock:          clock,
+		Rand:           rand.New(rand.NewPCG(1, 2)),
+	})
+	require.Nil(t, runner.Start())
+
+	delays := make(map[time.Duration]struct{})
+
+	for n := 0; n < 10; n++ {
+		delay := <-clock.delays
+		require.GreaterOrEqual(t, delay, interval/2)
+		require.LessOrEqual(t, delay, interval+interval/2)
+
+		delays[delay] = struct{}{}
+
+		clock.fire()
+	}
+
+	<-clock.delays
+	require.Greater(t, len(delays), 1)
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerFirstRunDelay(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 100,
+		FirstRunDelay:  time.Second * 10,
+		Clock:          clock,
+		Rand:           rand.New(rand.NewPCG(1, 2)),
+	})
+	require.Nil(t, runner.Start())
+
+	delay := <-clock.delays
+	require.Greater(t, delay, time.Duration(0))
+	require.Less(t, delay, time.Second*10)
+	require.Equal(t, 0, task.getCallCount())
+
+	clock.fire()
+
+	require.Equal(t, time.Millisecond*100, <-clock.delays)
+	require.Equal(t, 1, task.getCallCount())
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerFirstRunDelayAwake(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 100,
+		FirstRunDelay:  time.Hour,
+		Clock:          clock,
+	})
+	require.Nil(t, runner.Start())
+
+	<-clock.delays
+	runner.Awake()
+
+	require.Equal(t, time.Millisecond*100, <-clock.delays)
+	require.Equal(t, 1, task.getCallCount())
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerSetUpdateInterval(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{}
+	clock := newTestAsyncTaskRunnerClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Hour,
+		Clock:          clock,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Equal(t, time.Hour, <-clock.delays)
+	runner.SetUpdateInterval(time.Millisecond * 100)
+
+	require.Equal(t, time.Millisecond*100, <-clock.delays)
+	require.Equal(t, 2, task.getCallCount())
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
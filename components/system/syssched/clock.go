@@ -0,0 +1,26 @@
+package syssched
+
+import (
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+// Clock to read the current time and wait for the duration to elapse.
+type Clock interface {
+	syscore.MonotonicClock
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// LocalClock is wrapper around standard time.Time package.
+type LocalClock struct {
+	syscore.LocalMonotonicClock
+}
+
+// After waits for the duration to elapse using the standard timer.
+func (*LocalClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
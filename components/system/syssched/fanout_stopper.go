@@ -2,6 +2,8 @@ package syssched
 
 import "github.com/open-control-systems/device-hub/components/system/syscore"
 
+var fanoutStopperLogger = syscore.NewLogger("fanout-stopper")
+
 // FanoutStopper propagates stop call to the underlying stoppers.
 type FanoutStopper struct {
 	nodes []node
@@ -16,7 +18,7 @@ func (s *FanoutStopper) Add(id string, stopper Stopper) {
 func (s *FanoutStopper) Stop() error {
 	for _, node := range s.nodes {
 		if err := node.s.Stop(); err != nil {
-			syscore.LogErr.Printf("failed to stop: id=%s err=%v", node.id, err)
+			fanoutStopperLogger.Error("failed to stop", "id", node.id, "err", err)
 		}
 	}
 
@@ -0,0 +1,31 @@
+package syssched
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var (
+	taskRunDuration = promauto.With(sysmetrics.Registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "task",
+			Name:      "run_duration_seconds",
+			Help:      "Duration of the single asynchronous task run.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"task"},
+	)
+
+	taskRunTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: sysmetrics.Namespace,
+			Subsystem: "task",
+			Name:      "run_total",
+			Help:      "Number of asynchronous task runs by result.",
+		},
+		[]string{"task", "result"},
+	)
+)
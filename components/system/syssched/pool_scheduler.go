@@ -0,0 +1,358 @@
+package syssched
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// PoolSchedulerParams represents various configuration options for PoolScheduler.
+type PoolSchedulerParams struct {
+	// Workers is a number of goroutines to run tasks concurrently, 1 if unset.
+	Workers int
+
+	// Clock to schedule task runs, LocalClock if unset.
+	Clock Clock
+}
+
+// PoolScheduler periodically runs many tasks on a fixed number of goroutines.
+//
+// Remarks:
+//   - Tasks are ordered by the next run time, the earliest task is run first.
+//   - Task is never run concurrently with itself.
+type PoolScheduler struct {
+	params  PoolSchedulerParams
+	wakeCh  chan struct{}
+	readyCh chan *PoolTask
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue poolTaskQueue
+}
+
+// PoolTask is a task periodically run by PoolScheduler.
+type PoolTask struct {
+	ctx       context.Context
+	scheduler *PoolScheduler
+	task      Task
+	handler   ErrorHandler
+	params    AsyncTaskRunnerParams
+	schedule  *taskSchedule
+
+	// Protected by the scheduler mutex.
+	index     int
+	nextRunAt time.Time
+	started   bool
+	running   bool
+	awake     bool
+	stopped   bool
+}
+
+// NewPoolScheduler is an initialization of PoolScheduler.
+//
+// Parameters:
+//   - params - various configuration options for the scheduler.
+func NewPoolScheduler(params PoolSchedulerParams) *PoolScheduler {
+	if params.Workers < 1 {
+		params.Workers = 1
+	}
+	if params.Clock == nil {
+		params.Clock = &LocalClock{}
+	}
+
+	s := &PoolScheduler{
+		params:  params,
+		wakeCh:  make(chan struct{}, 1),
+		readyCh: make(chan *PoolTask),
+		stopCh:  make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+
+	return s
+}
+
+// Add registers the task to be run periodically.
+//
+// Parameters:
+//   - ctx - task isn't run anymore once the context is canceled.
+//   - task to run.
+//   - handler to handle task errors, can be nil.
+//   - params to configure how often the task is run, Clock is ignored.
+//
+// Remarks:
+//   - Task isn't run until PoolTask.Start() is called.
+func (s *PoolScheduler) Add(
+	ctx context.Context,
+	task Task,
+	handler ErrorHandler,
+	params AsyncTaskRunnerParams,
+) *PoolTask {
+	params.Clock = s.params.Clock
+	schedule := newTaskSchedule(params)
+
+	return &PoolTask{
+		ctx:       ctx,
+		scheduler: s,
+		task:      makeRunnerTask(task, params),
+		handler:   handler,
+		params:    schedule.params,
+		schedule:  schedule,
+		index:     -1,
+	}
+}
+
+// Start starts the scheduler goroutines.
+func (s *PoolScheduler) Start() error {
+	s.wg.Add(1)
+	go s.dispatch()
+
+	for n := 0; n < s.params.Workers; n++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	return nil
+}
+
+// Stop stops the scheduler goroutines.
+//
+// Remarks:
+//   - Waits for the currently running tasks to finish.
+func (s *PoolScheduler) Stop() error {
+	close(s.stopCh)
+	s.wg.Wait()
+
+	return nil
+}
+
+// Start schedules the first task run.
+func (t *PoolTask) Start() error {
+	s := t.scheduler
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.started || t.stopped {
+		return nil
+	}
+
+	t.started = true
+	t.push(s.params.Clock.Now().Add(t.schedule.firstRunDelay()))
+
+	return nil
+}
+
+// Stop removes the task from the scheduler.
+//
+// Remarks:
+//   - Waits for the currently running task to finish.
+func (t *PoolTask) Stop() error {
+	s := t.scheduler
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.stopped = true
+
+	if t.index >= 0 {
+		heap.Remove(&s.queue, t.index)
+	}
+
+	for t.running {
+		s.cond.Wait()
+	}
+
+	return nil
+}
+
+// Awake runs the task as soon as possible.
+func (t *PoolTask) Awake() {
+	s := t.scheduler
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.running {
+		t.awake = true
+
+		return
+	}
+
+	if t.index < 0 {
+		return
+	}
+
+	t.nextRunAt = s.params.Clock.Now()
+	heap.Fix(&s.queue, t.index)
+
+	s.wake()
+}
+
+func (t *PoolTask) push(nextRunAt time.Time) {
+	t.nextRunAt = nextRunAt
+	heap.Push(&t.scheduler.queue, t)
+
+	t.scheduler.wake()
+}
+
+func (t *PoolTask) run() {
+	s := t.scheduler
+
+	s.mu.Lock()
+	canRun := !t.stopped && t.ctx.Err() == nil
+	s.mu.Unlock()
+
+	var (
+		start = s.params.Clock.Now()
+		err   error
+		exit  = !canRun
+	)
+
+	if canRun {
+		err = runTask(t.params.Name, t.task, t.handler)
+		exit = err == nil && t.params.ExitOnSuccess
+
+		t.schedule.update(err)
+	}
+
+	delay := t.schedule.nextInterval() - s.params.Clock.Now().Sub(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.running = false
+	s.cond.Broadcast()
+
+	if exit || t.stopped || t.ctx.Err() != nil {
+		return
+	}
+
+	if t.awake {
+		t.awake = false
+		delay = 0
+	}
+
+	t.push(s.params.Clock.Now().Add(delay))
+}
+
+func (s *PoolScheduler) wake() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *PoolScheduler) dispatch() {
+	defer s.wg.Done()
+
+	for {
+		task, delay := s.next()
+
+		if task != nil {
+			select {
+			case s.readyCh <- task:
+			case <-s.stopCh:
+				s.release(task)
+
+				return
+			}
+
+			continue
+		}
+
+		var afterCh <-chan time.Time
+		if delay >= 0 {
+			afterCh = s.params.Clock.After(delay)
+		}
+
+		select {
+		case <-afterCh:
+		case <-s.wakeCh:
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// next returns the task ready to run, or how long to wait for the next task,
+// negative delay if there are no tasks.
+func (s *PoolScheduler) next() (*PoolTask, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil, -1
+	}
+
+	task := s.queue[0]
+
+	delay := task.nextRunAt.Sub(s.params.Clock.Now())
+	if delay > 0 {
+		return nil, delay
+	}
+
+	heap.Pop(&s.queue)
+	task.running = true
+
+	return task, 0
+}
+
+func (s *PoolScheduler) release(task *PoolTask) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task.running = false
+	s.cond.Broadcast()
+}
+
+func (s *PoolScheduler) work() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case task := <-s.readyCh:
+			task.run()
+
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+type poolTaskQueue []*PoolTask
+
+func (q poolTaskQueue) Len() int {
+	return len(q)
+}
+
+func (q poolTaskQueue) Less(i, j int) bool {
+	return q[i].nextRunAt.Before(q[j].nextRunAt)
+}
+
+func (q poolTaskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *poolTaskQueue) Push(x any) {
+	task := x.(*PoolTask)
+	task.index = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *poolTaskQueue) Pop() any {
+	old := *q
+	n := len(old)
+
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+
+	*q = old[:n-1]
+
+	return task
+}
@@ -0,0 +1,295 @@
+package syssched
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+type testPoolSchedulerCounter struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (c *testPoolSchedulerCounter) inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running++
+	c.maxRunning = max(c.maxRunning, c.running)
+}
+
+func (c *testPoolSchedulerCounter) dec() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running--
+}
+
+func (c *testPoolSchedulerCounter) getRunning() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.running
+}
+
+func (c *testPoolSchedulerCounter) getMaxRunning() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.maxRunning
+}
+
+type testPoolSchedulerTask struct {
+	mu        sync.Mutex
+	err       error
+	callCount int
+	blockCh   chan struct{}
+	counter   *testPoolSchedulerCounter
+}
+
+func (t *testPoolSchedulerTask) Run() error {
+	if t.counter != nil {
+		t.counter.inc()
+		defer t.counter.dec()
+	}
+
+	if t.blockCh != nil {
+		<-t.blockCh
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.callCount++
+
+	return t.err
+}
+
+func (t *testPoolSchedulerTask) getCallCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.callCount
+}
+
+func (t *testPoolSchedulerTask) setError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.err = err
+}
+
+func TestPoolSchedulerRun(t *testing.T) {
+	scheduler := NewPoolScheduler(PoolSchedulerParams{Workers: 2})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	var tasks []*testPoolSchedulerTask
+	var poolTasks []*PoolTask
+
+	for n := 0; n < 10; n++ {
+		task := &testPoolSchedulerTask{}
+		tasks = append(tasks, task)
+
+		poolTask := scheduler.Add(context.Background(), task, nil, AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond * time.Duration(10+n),
+		})
+		poolTasks = append(poolTasks, poolTask)
+
+		require.Nil(t, poolTask.Start())
+	}
+
+	for _, task := range tasks {
+		for task.getCallCount() < 3 {
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+
+	for n, poolTask := range poolTasks {
+		require.Nil(t, poolTask.Stop())
+
+		callCount := tasks[n].getCallCount()
+		time.Sleep(time.Millisecond * 30)
+		require.Equal(t, callCount, tasks[n].getCallCount())
+	}
+}
+
+func TestPoolSchedulerWorkers(t *testing.T) {
+	workers := 3
+
+	scheduler := NewPoolScheduler(PoolSchedulerParams{Workers: workers})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	counter := &testPoolSchedulerCounter{}
+	blockCh := make(chan struct{})
+
+	var poolTasks []*PoolTask
+
+	for n := 0; n < workers*2; n++ {
+		task := &testPoolSchedulerTask{
+			blockCh: blockCh,
+			counter: counter,
+		}
+
+		poolTask := scheduler.Add(context.Background(), task, nil, AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond,
+		})
+		poolTasks = append(poolTasks, poolTask)
+
+		require.Nil(t, poolTask.Start())
+	}
+
+	for counter.getRunning() < workers {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// Remaining tasks are waiting for the available worker.
+	time.Sleep(time.Millisecond * 50)
+	require.Equal(t, workers, counter.getRunning())
+
+	close(blockCh)
+
+	for _, poolTask := range poolTasks {
+		require.Nil(t, poolTask.Stop())
+	}
+
+	require.Equal(t, workers, counter.getMaxRunning())
+}
+
+func TestPoolSchedulerAwake(t *testing.T) {
+	scheduler := NewPoolScheduler(PoolSchedulerParams{})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	task := &testPoolSchedulerTask{}
+
+	poolTask := scheduler.Add(context.Background(), task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Hour,
+	})
+
+	poolTask.Awake()
+	time.Sleep(time.Millisecond * 20)
+	require.Equal(t, 0, task.getCallCount())
+
+	require.Nil(t, poolTask.Start())
+
+	for task.getCallCount() < 1 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	poolTask.Awake()
+
+	for task.getCallCount() < 2 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	require.Nil(t, poolTask.Stop())
+}
+
+func TestPoolSchedulerExitOnSuccess(t *testing.T) {
+	scheduler := NewPoolScheduler(PoolSchedulerParams{})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	task := &testPoolSchedulerTask{
+		err: status.StatusNotSupported,
+	}
+
+	poolTask := scheduler.Add(context.Background(), task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 10,
+		ExitOnSuccess:  true,
+	})
+	require.Nil(t, poolTask.Start())
+
+	for task.getCallCount() < 2 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	task.setError(nil)
+	time.Sleep(time.Millisecond * 50)
+
+	callCount := task.getCallCount()
+	time.Sleep(time.Millisecond * 50)
+	require.Equal(t, callCount, task.getCallCount())
+
+	require.Nil(t, poolTask.Stop())
+}
+
+func TestPoolSchedulerContextCanceled(t *testing.T) {
+	scheduler := NewPoolScheduler(PoolSchedulerParams{})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	task := &testPoolSchedulerTask{}
+
+	poolTask := scheduler.Add(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 10,
+	})
+	require.Nil(t, poolTask.Start())
+
+	for task.getCallCount() < 2 {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	cancel()
+
+	time.Sleep(time.Millisecond * 20)
+	callCount := task.getCallCount()
+
+	time.Sleep(time.Millisecond * 50)
+	require.Equal(t, callCount, task.getCallCount())
+
+	require.Nil(t, poolTask.Stop())
+}
+
+func TestPoolSchedulerStopRunningTask(t *testing.T) {
+	scheduler := NewPoolScheduler(PoolSchedulerParams{})
+	require.Nil(t, scheduler.Start())
+
+	blockCh := make(chan struct{})
+	task := &testPoolSchedulerTask{
+		blockCh: blockCh,
+	}
+
+	poolTask := scheduler.Add(context.Background(), task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond,
+	})
+	require.Nil(t, poolTask.Start())
+
+	blockCh <- struct{}{}
+
+	stopCh := make(chan struct{})
+	go func() {
+		require.Nil(t, poolTask.Stop())
+		close(stopCh)
+	}()
+
+	close(blockCh)
+	<-stopCh
+
+	callCount := task.getCallCount()
+	time.Sleep(time.Millisecond * 20)
+	require.Equal(t, callCount, task.getCallCount())
+
+	require.Nil(t, scheduler.Stop())
+}
@@ -1,5 +1,15 @@
 package syssched
 
+import "errors"
+
+// ErrTaskSkipped is returned by Task.Run() if the run is skipped, e.g. the task isn't
+// due yet.
+//
+// Remarks:
+//   - Skipped run isn't treated as success or failure: the schedule isn't changed,
+//     the run isn't counted in the metrics and isn't passed to the error handler.
+var ErrTaskSkipped = errors.New("task skipped")
+
 // Task represents an entity of the execution.
 type Task interface {
 	// Run executes a single operational loop.
@@ -0,0 +1,131 @@
+package syssched
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+// taskSchedule calculates the interval between the task runs.
+type taskSchedule struct {
+	params   AsyncTaskRunnerParams
+	failures int
+
+	mu             sync.Mutex
+	updateInterval time.Duration
+}
+
+func newTaskSchedule(params AsyncTaskRunnerParams) *taskSchedule {
+	if params.Clock == nil {
+		params.Clock = &LocalClock{}
+	}
+	if params.BackoffMultiplier == 0 {
+		params.BackoffMultiplier = 2
+	}
+
+	return &taskSchedule{
+		params:         params,
+		updateInterval: params.UpdateInterval,
+	}
+}
+
+func (s *taskSchedule) setUpdateInterval(interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.updateInterval == interval {
+		return false
+	}
+
+	s.updateInterval = interval
+
+	return true
+}
+
+func (s *taskSchedule) getUpdateInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateInterval
+}
+
+func (s *taskSchedule) firstRunDelay() time.Duration {
+	if s.params.FirstRunDelay <= 0 {
+		return 0
+	}
+
+	return time.Duration(s.random() * float64(s.params.FirstRunDelay))
+}
+
+func (s *taskSchedule) update(err error) {
+	if errors.Is(err, ErrTaskSkipped) {
+		return
+	}
+
+	if err != nil {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+}
+
+func (s *taskSchedule) nextInterval() time.Duration {
+	interval := float64(s.getUpdateInterval())
+
+	if s.failures > 0 && s.params.BackoffInitial > 0 {
+		interval = float64(s.params.BackoffInitial) *
+			math.Pow(s.params.BackoffMultiplier, float64(s.failures-1))
+
+		if s.params.BackoffMax > 0 {
+			interval = math.Min(interval, float64(s.params.BackoffMax))
+		}
+	}
+
+	if s.params.Jitter > 0 {
+		interval *= 1 + s.params.Jitter*(2*s.random()-1)
+	}
+
+	if interval >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(interval)
+}
+
+func (s *taskSchedule) random() float64 {
+	if s.params.Rand != nil {
+		return s.params.Rand.Float64()
+	}
+
+	return rand.Float64()
+}
+
+func makeRunnerTask(task Task, params AsyncTaskRunnerParams) Task {
+	if params.DisableRecoverOnPanic {
+		return task
+	}
+
+	return NewCrashTask(task)
+}
+
+func runTask(name string, task Task, handler ErrorHandler) error {
+	start := time.Now()
+	err := task.Run()
+
+	if errors.Is(err, ErrTaskSkipped) {
+		return err
+	}
+
+	taskRunDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	taskRunTotal.WithLabelValues(name, sysmetrics.ResultLabel(err)).Inc()
+
+	if err != nil && handler != nil {
+		handler.HandleError(err)
+	}
+
+	return err
+}
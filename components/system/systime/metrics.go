@@ -0,0 +1,53 @@
+package systime
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
+)
+
+var sntpRequestsTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: sysmetrics.Namespace,
+		Subsystem: "sntp",
+		Name:      "requests_total",
+		Help:      "Number of SNTP requests by result.",
+	},
+	[]string{"result"},
+)
+
+var sntpClientQueriesTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: sysmetrics.Namespace,
+		Subsystem: "sntp_client",
+		Name:      "queries_total",
+		Help:      "Number of SNTP client queries by result.",
+	},
+	[]string{"result"},
+)
+
+var sntpClientAdjustmentsTotal = promauto.With(sysmetrics.Registry).NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: sysmetrics.Namespace,
+		Subsystem: "sntp_client",
+		Name:      "adjustments_total",
+		Help:      "Number of local clock adjustments by type.",
+	},
+	[]string{"type"},
+)
+
+var sntpClientOffset = promauto.With(sysmetrics.Registry).NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: sysmetrics.Namespace,
+		Subsystem: "sntp_client",
+		Name:      "offset_seconds",
+		Help:      "Last measured offset of the local clock, positive if it's behind.",
+	},
+)
+
+const (
+	sntpResultSuccess = "success"
+	sntpResultFailure = "failure"
+	sntpResultInvalid = "invalid"
+)
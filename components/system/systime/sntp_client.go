@@ -0,0 +1,343 @@
+package systime
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var sntpClientLogger = syscore.NewLogger("sntp-client")
+
+const (
+	sntpDefaultPort = "123"
+
+	sntpAdjustmentSlew = "slew"
+	sntpAdjustmentStep = "step"
+)
+
+// SNTPClientParams represents various configuration options for the SNTP client.
+type SNTPClientParams struct {
+	// Servers to query, "host" or "host:port", port 123 is used if omitted.
+	Servers []string
+
+	// Timeout - how long to wait for the reply from a single server.
+	Timeout time.Duration
+
+	// StepThreshold - the clock is stepped if the offset exceeds this value, otherwise
+	// the clock is slewed.
+	StepThreshold time.Duration
+
+	// OutlierThreshold - the server reply is rejected if its offset differs from the
+	// median offset of all replies by more than this value.
+	OutlierThreshold time.Duration
+}
+
+// SNTPClientStatus contains the results of the local clock synchronization.
+//
+// Remarks:
+//   - Time is formatted according to RFC1123, empty if the event hasn't happened yet.
+type SNTPClientStatus struct {
+	// Server - the server whose reply was used for the last adjustment.
+	Server string `json:"server"`
+
+	// OffsetMs - the last measured offset of the local clock, in milliseconds,
+	// positive if the local clock is behind.
+	OffsetMs int64 `json:"offset_ms"`
+
+	// DelayMs - the round-trip delay of the used reply, in milliseconds.
+	DelayMs int64 `json:"delay_ms"`
+
+	// Adjustment - how the local clock was adjusted, "slew" or "step".
+	Adjustment string `json:"adjustment"`
+
+	// Accepted - number of the accepted replies during the last attempt.
+	Accepted int `json:"accepted"`
+
+	// Rejected - number of the failed queries and rejected outliers during the last
+	// attempt.
+	Rejected int `json:"rejected"`
+
+	// LastAttemptAt - when the synchronization was attempted last time.
+	LastAttemptAt string `json:"last_attempt_at"`
+
+	// LastSuccessAt - when the local clock was adjusted last time.
+	LastSuccessAt string `json:"last_success_at"`
+
+	// LastError - error of the last attempt, empty on success.
+	LastError string `json:"last_error"`
+}
+
+// SNTPClient synchronizes the local clock with the upstream SNTP servers.
+//
+// Remarks:
+//   - All servers are queried on each run, the outliers are rejected, and the reply
+//     with the lowest round-trip delay is used to adjust the local clock.
+//   - The majority of the replies should agree on the offset, otherwise the local
+//     clock isn't adjusted.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc4330
+type SNTPClient struct {
+	clock    syscore.MonotonicClock
+	adjuster syscore.AdjustableSystemClock
+	params   SNTPClientParams
+
+	mu     sync.Mutex
+	status SNTPClientStatus
+}
+
+// NewSNTPClient is an initialization of SNTPClient.
+//
+// Parameters:
+//   - clock to read the local time, it should report the wall clock time.
+//   - adjuster to slew or step the local clock.
+//   - params - various configuration options for the SNTP client.
+func NewSNTPClient(
+	clock syscore.MonotonicClock,
+	adjuster syscore.AdjustableSystemClock,
+	params SNTPClientParams,
+) *SNTPClient {
+	return &SNTPClient{
+		clock:    clock,
+		adjuster: adjuster,
+		params:   params,
+	}
+}
+
+// GetStatus returns the results of the local clock synchronization.
+func (c *SNTPClient) GetStatus() SNTPClientStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.status
+}
+
+// Run queries the servers and adjusts the local clock.
+func (c *SNTPClient) Run() error {
+	var samples []sntpSample
+
+	for _, server := range c.params.Servers {
+		sample, err := c.query(server)
+		if err != nil {
+			sntpClientQueriesTotal.WithLabelValues(sntpResultFailure).Inc()
+
+			sntpClientLogger.Debug("query failed", "server", server, "err", err)
+
+			continue
+		}
+
+		sntpClientQueriesTotal.WithLabelValues(sntpResultSuccess).Inc()
+
+		samples = append(samples, sample)
+	}
+
+	sample, accepted, err := selectSNTPSample(samples, c.params.OutlierThreshold)
+	if err != nil {
+		c.updateStatus(sample, "", accepted, err)
+
+		return err
+	}
+
+	adjustment, err := c.adjust(sample.offset)
+	if err != nil {
+		err = fmt.Errorf("failed to adjust local clock: %w", err)
+	}
+
+	c.updateStatus(sample, adjustment, accepted, err)
+
+	if err != nil {
+		return err
+	}
+
+	sntpClientOffset.Set(sample.offset.Seconds())
+	sntpClientAdjustmentsTotal.WithLabelValues(adjustment).Inc()
+
+	sntpClientLogger.Info("local clock adjusted",
+		"server", sample.server, "offset", sample.offset, "delay", sample.delay,
+		"adjustment", adjustment)
+
+	return nil
+}
+
+// HandleError handles error from the Run() call.
+func (*SNTPClient) HandleError(err error) {
+	sntpClientLogger.Warn("failed to synchronize local clock", "err", err)
+}
+
+func (c *SNTPClient) adjust(offset time.Duration) (string, error) {
+	if offset.Abs() > c.params.StepThreshold {
+		timestamp := c.clock.Now().Add(offset).UnixMilli()
+
+		return sntpAdjustmentStep, c.adjuster.SetTimestampMilli(timestamp)
+	}
+
+	return sntpAdjustmentSlew, c.adjuster.AdjustTimestamp(offset)
+}
+
+func (c *SNTPClient) updateStatus(
+	sample sntpSample,
+	adjustment string,
+	accepted int,
+	err error,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &c.status
+
+	s.Accepted = accepted
+	s.Rejected = len(c.params.Servers) - accepted
+	s.LastAttemptAt = time.Now().Format(time.RFC1123)
+
+	if err != nil {
+		s.LastError = err.Error()
+
+		return
+	}
+
+	s.Server = sample.server
+	s.OffsetMs = sample.offset.Round(time.Millisecond).Milliseconds()
+	s.DelayMs = sample.delay.Round(time.Millisecond).Milliseconds()
+	s.Adjustment = adjustment
+	s.LastSuccessAt = s.LastAttemptAt
+	s.LastError = ""
+}
+
+func (c *SNTPClient) query(server string) (sntpSample, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, sntpDefaultPort)
+	}
+
+	conn, err := net.DialTimeout("udp", addr, c.params.Timeout)
+	if err != nil {
+		return sntpSample{}, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.params.Timeout)); err != nil {
+		return sntpSample{}, err
+	}
+
+	originTime := c.clock.Now()
+
+	request := sntpPacket{
+		version:      sntpVersionMax,
+		mode:         sntpModeClient,
+		transmitTime: toNTPTime(originTime),
+	}
+
+	if _, err := conn.Write(request.marshal()); err != nil {
+		return sntpSample{}, err
+	}
+
+	buf := make([]byte, 512)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		return sntpSample{}, err
+	}
+
+	destinationTime := c.clock.Now()
+
+	var reply sntpPacket
+	if err := reply.unmarshal(buf[:n]); err != nil {
+		return sntpSample{}, err
+	}
+
+	if err := validateSNTPReply(&request, &reply); err != nil {
+		return sntpSample{}, err
+	}
+
+	receiveTime := fromNTPTime(reply.receiveTime)
+	transmitTime := fromNTPTime(reply.transmitTime)
+
+	return sntpSample{
+		server: server,
+		offset: (receiveTime.Sub(originTime) + transmitTime.Sub(destinationTime)) / 2,
+		delay:  destinationTime.Sub(originTime) - transmitTime.Sub(receiveTime),
+	}, nil
+}
+
+func validateSNTPReply(request *sntpPacket, reply *sntpPacket) error {
+	if reply.mode != sntpModeServer {
+		return fmt.Errorf("%w: unexpected mode: %d", status.StatusInvalidArg, reply.mode)
+	}
+
+	// Protects from the stale and spoofed replies.
+	if reply.originateTime != request.transmitTime {
+		return fmt.Errorf("%w: originate time mismatch", status.StatusInvalidArg)
+	}
+
+	if reply.leap == sntpLeapAlarm || reply.stratum == 0 ||
+		reply.stratum >= sntpStratumUnsynchronized {
+		return fmt.Errorf("%w: server clock isn't synchronized: leap=%d stratum=%d",
+			status.StatusInvalidState, reply.leap, reply.stratum)
+	}
+
+	if reply.transmitTime == 0 {
+		return fmt.Errorf("%w: transmit time is zero", status.StatusInvalidArg)
+	}
+
+	return nil
+}
+
+type sntpSample struct {
+	server string
+	offset time.Duration
+	delay  time.Duration
+}
+
+// selectSNTPSample rejects the samples with the offset too far from the median offset
+// and returns the accepted sample with the lowest delay, and the number of accepted
+// samples.
+func selectSNTPSample(samples []sntpSample, threshold time.Duration) (sntpSample, int, error) {
+	if len(samples) == 0 {
+		return sntpSample{}, 0, fmt.Errorf("%w: no valid replies", status.StatusNoData)
+	}
+
+	offsets := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		offsets = append(offsets, sample.offset)
+	}
+
+	slices.Sort(offsets)
+
+	median := offsets[len(offsets)/2]
+	if len(offsets)%2 == 0 {
+		median = (offsets[len(offsets)/2-1] + median) / 2
+	}
+
+	var (
+		best     sntpSample
+		accepted int
+	)
+
+	for _, sample := range samples {
+		if (sample.offset - median).Abs() > threshold {
+			sntpClientLogger.Debug("outlier rejected", "server", sample.server,
+				"offset", sample.offset, "median", median)
+
+			continue
+		}
+
+		if accepted == 0 || sample.delay < best.delay {
+			best = sample
+		}
+
+		accepted++
+	}
+
+	if accepted <= len(samples)/2 {
+		return sntpSample{}, accepted, fmt.Errorf(
+			"%w: replies don't agree on the offset: accepted=%d total=%d",
+			status.StatusInvalidState, accepted, len(samples))
+	}
+
+	return best, accepted, nil
+}
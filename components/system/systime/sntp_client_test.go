@@ -0,0 +1,256 @@
+package systime
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+type testSNTPClientClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	steps []int64
+	slews []time.Duration
+}
+
+func (c *testSNTPClientClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testSNTPClientClock) SetTimestampMilli(timestamp int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.steps = append(c.steps, timestamp)
+
+	return nil
+}
+
+func (c *testSNTPClientClock) GetTimestampMilli() (int64, error) {
+	return c.Now().UnixMilli(), nil
+}
+
+func (c *testSNTPClientClock) AdjustTimestamp(offset time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.slews = append(c.slews, offset)
+
+	return nil
+}
+
+func startTestSNTPClientServer(t *testing.T, now time.Time) string {
+	server, err := NewSNTPServer(&testSNTPServerClock{now: now}, SNTPServerParams{
+		Host:       "127.0.0.1",
+		Stratum:    2,
+		ValidSince: time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, server.Start())
+	t.Cleanup(func() {
+		require.Nil(t, server.Stop())
+	})
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(server.Port()))
+}
+
+// requireTestSNTPOffset allows the error of the NTP timestamp fraction rounding.
+func requireTestSNTPOffset(t *testing.T, expected, actual time.Duration) {
+	require.InDelta(t, float64(expected), float64(actual), float64(time.Microsecond))
+}
+
+func newTestSNTPClient(clock *testSNTPClientClock, servers ...string) *SNTPClient {
+	return NewSNTPClient(clock, clock, SNTPClientParams{
+		Servers:          servers,
+		Timeout:          200 * time.Millisecond,
+		StepThreshold:    128 * time.Millisecond,
+		OutlierThreshold: 100 * time.Millisecond,
+	})
+}
+
+func TestSNTPClientSlew(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 0, time.UTC)
+	clock := &testSNTPClientClock{now: now}
+
+	server := startTestSNTPClientServer(t, now.Add(50*time.Millisecond))
+
+	client := newTestSNTPClient(clock, server)
+	require.Nil(t, client.Run())
+
+	require.Empty(t, clock.steps)
+	require.Equal(t, 1, len(clock.slews))
+	requireTestSNTPOffset(t, 50*time.Millisecond, clock.slews[0])
+
+	status := client.GetStatus()
+	require.Equal(t, server, status.Server)
+	require.Equal(t, int64(50), status.OffsetMs)
+	require.Equal(t, int64(0), status.DelayMs)
+	require.Equal(t, sntpAdjustmentSlew, status.Adjustment)
+	require.Equal(t, 1, status.Accepted)
+	require.Equal(t, 0, status.Rejected)
+	require.NotEmpty(t, status.LastSuccessAt)
+	require.Empty(t, status.LastError)
+}
+
+func TestSNTPClientStep(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 0, time.UTC)
+	clock := &testSNTPClientClock{now: now}
+
+	server := startTestSNTPClientServer(t, now.Add(-time.Hour))
+
+	client := newTestSNTPClient(clock, server)
+	require.Nil(t, client.Run())
+
+	require.Empty(t, clock.slews)
+	require.Equal(t, 1, len(clock.steps))
+	require.InDelta(t, now.Add(-time.Hour).UnixMilli(), clock.steps[0], 1)
+
+	status := client.GetStatus()
+	require.Equal(t, (-time.Hour).Milliseconds(), status.OffsetMs)
+	require.Equal(t, sntpAdjustmentStep, status.Adjustment)
+}
+
+func TestSNTPClientRejectOutlier(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 0, time.UTC)
+	clock := &testSNTPClientClock{now: now}
+
+	client := newTestSNTPClient(clock,
+		startTestSNTPClientServer(t, now.Add(50*time.Millisecond)),
+		startTestSNTPClientServer(t, now.Add(time.Hour)),
+		startTestSNTPClientServer(t, now.Add(60*time.Millisecond)),
+	)
+	require.Nil(t, client.Run())
+
+	require.Empty(t, clock.steps)
+	require.Equal(t, 1, len(clock.slews))
+	requireTestSNTPOffset(t, 50*time.Millisecond, clock.slews[0])
+
+	status := client.GetStatus()
+	require.Equal(t, 2, status.Accepted)
+	require.Equal(t, 1, status.Rejected)
+}
+
+func TestSNTPClientNoMajority(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 0, time.UTC)
+	clock := &testSNTPClientClock{now: now}
+
+	client := newTestSNTPClient(clock,
+		startTestSNTPClientServer(t, now.Add(50*time.Millisecond)),
+		startTestSNTPClientServer(t, now.Add(time.Hour)),
+	)
+	require.ErrorIs(t, client.Run(), status.StatusInvalidState)
+
+	require.Empty(t, clock.steps)
+	require.Empty(t, clock.slews)
+
+	clientStatus := client.GetStatus()
+	require.Equal(t, 0, clientStatus.Accepted)
+	require.Equal(t, 2, clientStatus.Rejected)
+	require.Empty(t, clientStatus.LastSuccessAt)
+	require.NotEmpty(t, clientStatus.LastError)
+}
+
+func TestSNTPClientNoValidReplies(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 0, time.UTC)
+	clock := &testSNTPClientClock{now: now}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.Nil(t, err)
+	defer conn.Close()
+
+	client := newTestSNTPClient(clock,
+		// Unsynchronized server clock.
+		startTestSNTPClientServer(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		// Server doesn't reply.
+		conn.LocalAddr().String(),
+	)
+	require.ErrorIs(t, client.Run(), status.StatusNoData)
+
+	require.Empty(t, clock.steps)
+	require.Empty(t, clock.slews)
+
+	clientStatus := client.GetStatus()
+	require.Equal(t, 0, clientStatus.Accepted)
+	require.Equal(t, 2, clientStatus.Rejected)
+	require.NotEmpty(t, clientStatus.LastError)
+}
+
+func TestSNTPClientSelectSample(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		samples  []sntpSample
+		server   string
+		accepted int
+		err      error
+	}{
+		{
+			name: "lowest delay",
+			samples: []sntpSample{
+				{server: "a", offset: 10 * time.Millisecond, delay: 30 * time.Millisecond},
+				{server: "b", offset: 20 * time.Millisecond, delay: 10 * time.Millisecond},
+				{server: "c", offset: 30 * time.Millisecond, delay: 20 * time.Millisecond},
+			},
+			server:   "b",
+			accepted: 3,
+		},
+		{
+			name: "outlier with lowest delay",
+			samples: []sntpSample{
+				{server: "a", offset: 10 * time.Millisecond, delay: 30 * time.Millisecond},
+				{server: "b", offset: time.Second, delay: 10 * time.Millisecond},
+				{server: "c", offset: 30 * time.Millisecond, delay: 20 * time.Millisecond},
+			},
+			server:   "c",
+			accepted: 2,
+		},
+		{
+			name: "even number of samples",
+			samples: []sntpSample{
+				{server: "a", offset: -time.Second, delay: 10 * time.Millisecond},
+				{server: "b", offset: 10 * time.Millisecond, delay: 20 * time.Millisecond},
+				{server: "c", offset: 20 * time.Millisecond, delay: 30 * time.Millisecond},
+				{server: "d", offset: 30 * time.Millisecond, delay: 40 * time.Millisecond},
+			},
+			server:   "b",
+			accepted: 3,
+		},
+		{
+			name:     "single sample",
+			samples:  []sntpSample{{server: "a", offset: time.Hour}},
+			server:   "a",
+			accepted: 1,
+		},
+		{
+			name: "no majority",
+			samples: []sntpSample{
+				{server: "a", offset: 0},
+				{server: "b", offset: time.Second},
+			},
+			err: status.StatusInvalidState,
+		},
+		{
+			name: "no samples",
+			err:  status.StatusNoData,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sample, accepted, err := selectSNTPSample(tc.samples, 100*time.Millisecond)
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err == nil {
+				require.Equal(t, tc.server, sample.server)
+				require.Equal(t, tc.accepted, accepted)
+			}
+		})
+	}
+}
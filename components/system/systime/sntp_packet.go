@@ -0,0 +1,119 @@
+package systime
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
+)
+
+const (
+	// sntpPacketSize is the size of the SNTP packet without the optional
+	// authenticator fields.
+	sntpPacketSize = 48
+
+	sntpLeapNoWarning = 0
+	sntpLeapAlarm     = 3
+
+	sntpModeClient = 3
+	sntpModeServer = 4
+
+	sntpVersionMin = 1
+	sntpVersionMax = 4
+
+	// sntpStratumUnsynchronized is the stratum of the server with the unsynchronized
+	// clock, the clients discard such replies.
+	sntpStratumUnsynchronized = 16
+
+	// sntpEraOffset is the number of seconds between the NTP epoch (1900) and the
+	// UNIX epoch (1970).
+	sntpEraOffset = 2208988800
+)
+
+// sntpPacket is the NTP packet format used by SNTP.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc4330#section-4
+type sntpPacket struct {
+	leap           uint8
+	version        uint8
+	mode           uint8
+	stratum        uint8
+	poll           int8
+	precision      int8
+	rootDelay      uint32
+	rootDispersion uint32
+	referenceID    [4]byte
+	referenceTime  uint64
+	originateTime  uint64
+	receiveTime    uint64
+	transmitTime   uint64
+}
+
+func (p *sntpPacket) unmarshal(buf []byte) error {
+	if len(buf) < sntpPacketSize {
+		return fmt.Errorf("%w: packet is too short: size=%d", status.StatusInvalidArg,
+			len(buf))
+	}
+
+	p.leap = buf[0] >> 6
+	p.version = (buf[0] >> 3) & 0x7
+	p.mode = buf[0] & 0x7
+	p.stratum = buf[1]
+	p.poll = int8(buf[2])
+	p.precision = int8(buf[3])
+	p.rootDelay = binary.BigEndian.Uint32(buf[4:8])
+	p.rootDispersion = binary.BigEndian.Uint32(buf[8:12])
+	copy(p.referenceID[:], buf[12:16])
+	p.referenceTime = binary.BigEndian.Uint64(buf[16:24])
+	p.originateTime = binary.BigEndian.Uint64(buf[24:32])
+	p.receiveTime = binary.BigEndian.Uint64(buf[32:40])
+	p.transmitTime = binary.BigEndian.Uint64(buf[40:48])
+
+	return nil
+}
+
+func (p *sntpPacket) marshal() []byte {
+	buf := make([]byte, sntpPacketSize)
+
+	buf[0] = p.leap<<6 | (p.version&0x7)<<3 | p.mode&0x7
+	buf[1] = p.stratum
+	buf[2] = byte(p.poll)
+	buf[3] = byte(p.precision)
+	binary.BigEndian.PutUint32(buf[4:8], p.rootDelay)
+	binary.BigEndian.PutUint32(buf[8:12], p.rootDispersion)
+	copy(buf[12:16], p.referenceID[:])
+	binary.BigEndian.PutUint64(buf[16:24], p.referenceTime)
+	binary.BigEndian.PutUint64(buf[24:32], p.originateTime)
+	binary.BigEndian.PutUint64(buf[32:40], p.receiveTime)
+	binary.BigEndian.PutUint64(buf[40:48], p.transmitTime)
+
+	return buf
+}
+
+// toNTPTime converts the time to the 64-bit NTP timestamp: seconds since 1900
+// in the upper 32 bits and the fraction of the second in the lower 32 bits.
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + sntpEraOffset)
+	fraction := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+
+	return seconds<<32 | fraction
+}
+
+// fromNTPTime converts the 64-bit NTP timestamp to the time.
+//
+// Remarks:
+//   - Timestamps with the most significant bit set to 0 are in the 2036-2104 range,
+//     see RFC 4330, section 3.
+func fromNTPTime(ts uint64) time.Time {
+	seconds := int64(ts >> 32)
+	if seconds&0x80000000 == 0 {
+		seconds += 1 << 32
+	}
+
+	seconds -= sntpEraOffset
+	nanoseconds := ((ts & 0xffffffff) * uint64(time.Second)) >> 32
+
+	return time.Unix(seconds, int64(nanoseconds))
+}
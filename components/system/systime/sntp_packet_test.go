@@ -0,0 +1,55 @@
+package systime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSNTPPacketMarshal(t *testing.T) {
+	packet := sntpPacket{
+		leap:           sntpLeapAlarm,
+		version:        4,
+		mode:           sntpModeServer,
+		stratum:        2,
+		poll:           6,
+		precision:      -20,
+		rootDelay:      1,
+		rootDispersion: 2,
+		referenceID:    [4]byte{'L', 'O', 'C', 'L'},
+		referenceTime:  3,
+		originateTime:  4,
+		receiveTime:    5,
+		transmitTime:   6,
+	}
+
+	buf := packet.marshal()
+	require.Equal(t, sntpPacketSize, len(buf))
+	require.Equal(t, byte(0xe4), buf[0])
+
+	var parsed sntpPacket
+	require.Nil(t, parsed.unmarshal(buf))
+	require.Equal(t, packet, parsed)
+
+	require.NotNil(t, parsed.unmarshal(buf[:sntpPacketSize-1]))
+}
+
+func TestSNTPTimeConversion(t *testing.T) {
+	for _, ts := range []time.Time{
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 3, 10, 20, 30, 500*int(time.Millisecond), time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC),
+		time.Date(2040, 1, 1, 0, 0, 0, 250*int(time.Millisecond), time.UTC),
+	} {
+		converted := fromNTPTime(toNTPTime(ts))
+
+		require.True(t, converted.Sub(ts).Abs() < time.Microsecond, ts)
+	}
+
+	// 1900 epoch.
+	require.Equal(t, uint64(sntpEraOffset)<<32, toNTPTime(time.Unix(0, 0)))
+
+	// NTP era 0 ends, the timestamp wraps.
+	require.Equal(t, uint64(0), toNTPTime(time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC)))
+}
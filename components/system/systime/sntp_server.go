@@ -0,0 +1,183 @@
+package systime
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/status"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+)
+
+var sntpServerLogger = syscore.NewLogger("sntp-server")
+
+// SNTPServerParams represents various configuration options for the SNTP server.
+type SNTPServerParams struct {
+	// Host to listen on, "0.0.0.0" is used if empty.
+	Host string
+
+	// Port to listen on, a random free port is chosen if zero.
+	Port int
+
+	// Stratum of the local clock, reported to the clients.
+	Stratum uint8
+
+	// ValidSince - the local clock is considered unsynchronized if it's before this
+	// point, the clients discard the replies in that case.
+	ValidSince time.Time
+}
+
+// SNTPServer serves the local UNIX time over SNTP, so the devices can synchronize
+// their clocks with sub-second accuracy.
+//
+// Remarks:
+//   - The server is stateless, each request is replied immediately.
+//
+// References:
+//   - https://datatracker.ietf.org/doc/html/rfc4330
+type SNTPServer struct {
+	clock  syscore.MonotonicClock
+	params SNTPServerParams
+	conn   *net.UDPConn
+	doneCh chan struct{}
+	port   int
+}
+
+// NewSNTPServer is an initialization of SNTPServer.
+//
+// Parameters:
+//   - clock to read the local time, it should report the wall clock time.
+//   - params - various configuration options for the SNTP server.
+//
+// Remarks:
+//   - The UDP socket is bound immediately, the requests are served after Start().
+func NewSNTPServer(
+	clock syscore.MonotonicClock,
+	params SNTPServerParams,
+) (*SNTPServer, error) {
+	if params.Host == "" {
+		params.Host = "0.0.0.0"
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(params.Host,
+		strconv.Itoa(params.Port)))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP(addr.Network(), addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SNTPServer{
+		clock:  clock,
+		params: params,
+		conn:   conn,
+		doneCh: make(chan struct{}),
+		port:   conn.LocalAddr().(*net.UDPAddr).Port,
+	}, nil
+}
+
+// Port returns the port to which the server socket is bound.
+func (s *SNTPServer) Port() int {
+	return s.port
+}
+
+// Start starts serving the SNTP requests.
+func (s *SNTPServer) Start() error {
+	go s.run()
+
+	return nil
+}
+
+// Stop stops the server and waits until it finishes.
+func (s *SNTPServer) Stop() error {
+	err := s.conn.Close()
+
+	<-s.doneCh
+
+	return err
+}
+
+func (s *SNTPServer) run() {
+	defer close(s.doneCh)
+
+	buf := make([]byte, 512)
+
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			sntpServerLogger.Error("failed to read request", "err", err)
+
+			continue
+		}
+
+		receiveTime := s.clock.Now()
+
+		reply, err := s.handleRequest(buf[:n], receiveTime)
+		if err != nil {
+			sntpRequestsTotal.WithLabelValues(sntpResultInvalid).Inc()
+
+			sntpServerLogger.Debug("invalid request", "addr", addr, "err", err)
+
+			continue
+		}
+
+		if _, err := s.conn.WriteToUDP(reply, addr); err != nil {
+			sntpRequestsTotal.WithLabelValues(sntpResultFailure).Inc()
+
+			sntpServerLogger.Warn("failed to send reply", "addr", addr, "err", err)
+
+			continue
+		}
+
+		sntpRequestsTotal.WithLabelValues(sntpResultSuccess).Inc()
+	}
+}
+
+func (s *SNTPServer) handleRequest(buf []byte, receiveTime time.Time) ([]byte, error) {
+	var request sntpPacket
+	if err := request.unmarshal(buf); err != nil {
+		return nil, err
+	}
+
+	if request.mode != sntpModeClient {
+		return nil, fmt.Errorf("%w: unsupported mode: %d", status.StatusInvalidArg,
+			request.mode)
+	}
+
+	if request.version < sntpVersionMin || request.version > sntpVersionMax {
+		return nil, fmt.Errorf("%w: unsupported version: %d", status.StatusInvalidArg,
+			request.version)
+	}
+
+	reply := sntpPacket{
+		leap:    sntpLeapNoWarning,
+		version: request.version,
+		mode:    sntpModeServer,
+		stratum: s.params.Stratum,
+		poll:    request.poll,
+		// ~1us, the local clock precision.
+		precision:     -20,
+		referenceID:   [4]byte{'L', 'O', 'C', 'L'},
+		referenceTime: toNTPTime(receiveTime),
+		originateTime: request.transmitTime,
+		receiveTime:   toNTPTime(receiveTime),
+	}
+
+	if receiveTime.Before(s.params.ValidSince) {
+		reply.leap = sntpLeapAlarm
+		reply.stratum = sntpStratumUnsynchronized
+	}
+
+	reply.transmitTime = toNTPTime(s.clock.Now())
+
+	return reply.marshal(), nil
+}
@@ -0,0 +1,141 @@
+package systime
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSNTPServerClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testSNTPServerClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testSNTPServerClock) set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func startTestSNTPServer(t *testing.T, clock *testSNTPServerClock) *net.UDPConn {
+	server, err := NewSNTPServer(clock, SNTPServerParams{
+		Host:       "127.0.0.1",
+		Stratum:    3,
+		ValidSince: time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, err)
+	require.NotEqual(t, 0, server.Port())
+
+	require.Nil(t, server.Start())
+	t.Cleanup(func() {
+		require.Nil(t, server.Stop())
+	})
+
+	conn, err := net.Dial("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(server.Port())))
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn.(*net.UDPConn)
+}
+
+func exchangeTestSNTPPacket(t *testing.T, conn *net.UDPConn, buf []byte) (sntpPacket, error) {
+	_, err := conn.Write(buf)
+	require.Nil(t, err)
+
+	require.Nil(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+	reply := make([]byte, 512)
+
+	n, err := conn.Read(reply)
+	if err != nil {
+		return sntpPacket{}, err
+	}
+
+	var packet sntpPacket
+	require.Nil(t, packet.unmarshal(reply[:n]))
+
+	return packet, nil
+}
+
+func TestSNTPServerReply(t *testing.T) {
+	now := time.Date(2025, 1, 14, 7, 40, 11, 125*int(time.Millisecond), time.UTC)
+
+	clock := &testSNTPServerClock{now: now}
+	conn := startTestSNTPServer(t, clock)
+
+	request := sntpPacket{
+		version:      3,
+		mode:         sntpModeClient,
+		poll:         6,
+		transmitTime: 0x0102030405060708,
+	}
+
+	reply, err := exchangeTestSNTPPacket(t, conn, request.marshal())
+	require.Nil(t, err)
+
+	require.Equal(t, uint8(sntpLeapNoWarning), reply.leap)
+	require.Equal(t, uint8(3), reply.version)
+	require.Equal(t, uint8(sntpModeServer), reply.mode)
+	require.Equal(t, uint8(3), reply.stratum)
+	require.Equal(t, int8(6), reply.poll)
+	require.Equal(t, request.transmitTime, reply.originateTime)
+	require.Equal(t, toNTPTime(now), reply.receiveTime)
+	require.Equal(t, toNTPTime(now), reply.transmitTime)
+	require.True(t, fromNTPTime(reply.transmitTime).Equal(now))
+}
+
+func TestSNTPServerUnsynchronizedClock(t *testing.T) {
+	clock := &testSNTPServerClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	conn := startTestSNTPServer(t, clock)
+
+	request := sntpPacket{
+		version: 4,
+		mode:    sntpModeClient,
+	}
+
+	reply, err := exchangeTestSNTPPacket(t, conn, request.marshal())
+	require.Nil(t, err)
+	require.Equal(t, uint8(sntpLeapAlarm), reply.leap)
+	require.Equal(t, uint8(sntpStratumUnsynchronized), reply.stratum)
+
+	clock.set(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	reply, err = exchangeTestSNTPPacket(t, conn, request.marshal())
+	require.Nil(t, err)
+	require.Equal(t, uint8(sntpLeapNoWarning), reply.leap)
+	require.Equal(t, uint8(3), reply.stratum)
+}
+
+func TestSNTPServerInvalidRequest(t *testing.T) {
+	clock := &testSNTPServerClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	conn := startTestSNTPServer(t, clock)
+
+	for _, request := range [][]byte{
+		[]byte("foo"),
+		(&sntpPacket{version: 4, mode: sntpModeServer}).marshal(),
+		(&sntpPacket{version: 0, mode: sntpModeClient}).marshal(),
+		(&sntpPacket{version: 5, mode: sntpModeClient}).marshal(),
+	} {
+		_, err := exchangeTestSNTPPacket(t, conn, request)
+		require.NotNil(t, err)
+	}
+
+	// Server is still running.
+	_, err := exchangeTestSNTPPacket(t, conn,
+		(&sntpPacket{version: 4, mode: sntpModeClient}).marshal())
+	require.Nil(t, err)
+}
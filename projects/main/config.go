@@ -0,0 +1,676 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-control-systems/device-hub/components/device/devstore"
+	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/storage/stinfluxdb"
+	"github.com/open-control-systems/device-hub/components/system/syscore"
+	"github.com/open-control-systems/device-hub/components/system/sysnet"
+	"github.com/open-control-systems/device-hub/components/system/systime"
+)
+
+type appOptions struct {
+	config   string
+	logDir   string
+	cacheDir string
+	port     int
+
+	log struct {
+		level    string
+		format   string
+		maxSize  int
+		maxAge   string
+		maxFiles int
+		compress bool
+	}
+
+	storage struct {
+		backend string
+
+		file struct {
+			path string
+		}
+
+		influxdb stinfluxdb.DBParams
+
+		influxdbQueue struct {
+			disable        bool
+			maxSize        int
+			maxAge         string
+			dropPolicy     string
+			replayInterval string
+			batchSize      int
+		}
+	}
+
+	device struct {
+		profileDir string
+
+		http struct {
+			fetchTimeout            string
+			fetchInterval           string
+			offlineMaxFetchInterval string
+			errorMaxFetchInterval   string
+			fetchJitter             float64
+			workers                 int
+		}
+
+		monitor struct {
+			inactive struct {
+				disable           bool
+				maxInterval       string
+				updateInterval    string
+				policy            string
+				retentionInterval string
+			}
+		}
+
+		timeSync struct {
+			disable          bool
+			maxDriftInterval string
+			correction       string
+		}
+	}
+
+	mdns struct {
+		browse struct {
+			interval    string
+			timeout     string
+			iface       string
+			expireAfter int
+		}
+
+		autodiscovery struct {
+			disable bool
+		}
+
+		server struct {
+			disable  bool
+			hostname string
+			iface    string
+		}
+	}
+
+	sntp struct {
+		server struct {
+			enable  bool
+			port    int
+			stratum int
+		}
+
+		client struct {
+			enable           bool
+			servers          string
+			interval         string
+			timeout          string
+			stepThreshold    string
+			outlierThreshold string
+		}
+	}
+
+	fakeHwclock struct {
+		enable   bool
+		interval string
+	}
+}
+
+// appConfig contains options parsed and validated from appOptions.
+type appConfig struct {
+	influxdbQueue stinfluxdb.QueueParams
+	cacheStore    devstore.CacheStoreParams
+	httpWorkers   int
+
+	log struct {
+		level  slog.Level
+		format syscore.LogFormat
+		file   syscore.LogFileParams
+	}
+
+	monitor struct {
+		disable        bool
+		updateInterval time.Duration
+		params         devstore.StoreAliveMonitorParams
+	}
+
+	mdns struct {
+		browse struct {
+			interval    time.Duration
+			timeout     time.Duration
+			ifaces      []net.Interface
+			expireAfter int
+		}
+
+		server struct {
+			ifaces []net.Interface
+		}
+	}
+
+	sntp struct {
+		server struct {
+			enable bool
+			params systime.SNTPServerParams
+		}
+
+		client struct {
+			enable   bool
+			interval time.Duration
+			params   systime.SNTPClientParams
+		}
+	}
+
+	fakeHwclock struct {
+		enable   bool
+		interval time.Duration
+	}
+}
+
+// parseAppConfig parses and validates all options, nothing is started.
+func parseAppConfig(opts *appOptions) (*appConfig, error) {
+	cfg := &appConfig{}
+
+	if err := parseLogConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if opts.storage.backend == "influxdb" {
+		if err := parseInfluxdbQueueConfig(cfg, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parseCacheStoreConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if err := parseMonitorConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if err := parseMdnsConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if err := parseSNTPConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if err := parseSNTPClientConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	if err := parseFakeHwclockConfig(cfg, opts); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func parseLogConfig(cfg *appConfig, opts *appOptions) error {
+	level, err := syscore.ParseLogLevel(opts.log.level)
+	if err != nil {
+		return err
+	}
+
+	format, err := syscore.ParseLogFormat(opts.log.format)
+	if err != nil {
+		return err
+	}
+
+	if opts.log.maxSize < 0 {
+		return errors.New("--log-max-size can't be negative")
+	}
+	if opts.log.maxFiles < 0 {
+		return errors.New("--log-max-files can't be negative")
+	}
+
+	maxAge, err := time.ParseDuration(opts.log.maxAge)
+	if err != nil {
+		return err
+	}
+	if maxAge < 0 {
+		return errors.New("--log-max-age can't be negative")
+	}
+
+	cfg.log.level = level
+	cfg.log.format = format
+	cfg.log.file = syscore.LogFileParams{
+		MaxSize:  int64(opts.log.maxSize) * 1024 * 1024,
+		MaxAge:   maxAge,
+		MaxFiles: opts.log.maxFiles,
+		Compress: opts.log.compress,
+	}
+
+	return nil
+}
+
+func parseInfluxdbQueueConfig(cfg *appConfig, opts *appOptions) error {
+	cfg.influxdbQueue.Disable = opts.storage.influxdbQueue.disable
+
+	if cfg.influxdbQueue.Disable {
+		return nil
+	}
+
+	if opts.storage.influxdbQueue.maxSize < 1 {
+		return errors.New("influxdb queue size can't be less than 1")
+	}
+	if opts.storage.influxdbQueue.batchSize < 1 {
+		return errors.New("influxdb queue batch size can't be less than 1")
+	}
+
+	maxAge, err := time.ParseDuration(opts.storage.influxdbQueue.maxAge)
+	if err != nil {
+		return err
+	}
+	if maxAge < 0 {
+		return errors.New("influxdb queue max age can't be negative")
+	}
+
+	replayInterval, err := time.ParseDuration(opts.storage.influxdbQueue.replayInterval)
+	if err != nil {
+		return err
+	}
+	if replayInterval < time.Millisecond {
+		return errors.New("influxdb queue replay interval can't be less than 1ms")
+	}
+
+	dropPolicy, err := stcore.ParseQueueDropPolicy(opts.storage.influxdbQueue.dropPolicy)
+	if err != nil {
+		return err
+	}
+
+	cfg.influxdbQueue.MaxSize = opts.storage.influxdbQueue.maxSize
+	cfg.influxdbQueue.MaxAge = maxAge
+	cfg.influxdbQueue.DropPolicy = dropPolicy
+	cfg.influxdbQueue.ReplayInterval = replayInterval
+	cfg.influxdbQueue.BatchSize = opts.storage.influxdbQueue.batchSize
+
+	return nil
+}
+
+func parseCacheStoreConfig(cfg *appConfig, opts *appOptions) error {
+	fetchInterval, err := time.ParseDuration(opts.device.http.fetchInterval)
+	if err != nil {
+		return err
+	}
+	if fetchInterval < time.Millisecond {
+		return errors.New("HTTP device fetch interval can't be less than 1ms")
+	}
+
+	fetchTimeout, err := time.ParseDuration(opts.device.http.fetchTimeout)
+	if err != nil {
+		return err
+	}
+	if fetchTimeout < time.Millisecond {
+		return errors.New("HTTP device fetch timeout can't be less than 1ms")
+	}
+
+	offlineMaxFetchInterval, err :=
+		time.ParseDuration(opts.device.http.offlineMaxFetchInterval)
+	if err != nil {
+		return err
+	}
+
+	errorMaxFetchInterval, err := time.ParseDuration(opts.device.http.errorMaxFetchInterval)
+	if err != nil {
+		return err
+	}
+
+	if opts.device.http.fetchJitter < 0 || opts.device.http.fetchJitter > 1 {
+		return errors.New("--device-http-fetch-jitter should be in the [0, 1] range")
+	}
+
+	if opts.device.http.workers < 0 {
+		return errors.New("--device-http-workers can't be negative")
+	}
+
+	var maxDriftInterval time.Duration
+
+	if opts.device.timeSync.maxDriftInterval != "" {
+		interval, err := time.ParseDuration(opts.device.timeSync.maxDriftInterval)
+		if err != nil {
+			return err
+		}
+		if interval < time.Second {
+			return errors.New("--device-time-sync-drift-interval can't be less than 1s")
+		}
+
+		maxDriftInterval = interval
+	}
+
+	timeCorrection, err := devstore.ParseTimeCorrection(opts.device.timeSync.correction)
+	if err != nil {
+		return err
+	}
+
+	cfg.cacheStore.HTTP.FetchInterval = fetchInterval
+	cfg.cacheStore.HTTP.FetchTimeout = fetchTimeout
+	cfg.cacheStore.HTTP.OfflineMaxFetchInterval = offlineMaxFetchInterval
+	cfg.cacheStore.HTTP.ErrorMaxFetchInterval = errorMaxFetchInterval
+	cfg.cacheStore.HTTP.FetchJitter = opts.device.http.fetchJitter
+	cfg.cacheStore.TimeSync.MaxDriftInterval = maxDriftInterval
+	cfg.cacheStore.TimeSync.Disable = opts.device.timeSync.disable
+	cfg.cacheStore.TimeSync.Correction = timeCorrection
+
+	cfg.httpWorkers = opts.device.http.workers
+
+	if opts.device.profileDir != "" {
+		profiles := devstore.NewDeviceProfileRegistry()
+		if err := profiles.LoadDir(opts.device.profileDir); err != nil {
+			return err
+		}
+
+		cfg.cacheStore.Profiles = profiles
+	}
+
+	return nil
+}
+
+func parseMonitorConfig(cfg *appConfig, opts *appOptions) error {
+	cfg.monitor.disable = opts.device.monitor.inactive.disable
+
+	if cfg.monitor.disable {
+		return nil
+	}
+
+	inactiveMaxInterval, err :=
+		time.ParseDuration(opts.device.monitor.inactive.maxInterval)
+	if err != nil {
+		return err
+	}
+
+	if inactiveMaxInterval < time.Millisecond {
+		return errors.New("device-monitor-inactive-max-interval can't be" +
+			" less than 1ms")
+	}
+
+	inactiveUpdateInterval, err :=
+		time.ParseDuration(opts.device.monitor.inactive.updateInterval)
+	if err != nil {
+		return err
+	}
+
+	if inactiveUpdateInterval < time.Millisecond {
+		return errors.New("device-monitor-inactive-update-interval can't be" +
+			" less than 1ms")
+	}
+
+	inactivePolicy, err := devstore.ParseStoreAliveMonitorPolicy(
+		opts.device.monitor.inactive.policy)
+	if err != nil {
+		return err
+	}
+
+	inactiveRetentionInterval, err :=
+		time.ParseDuration(opts.device.monitor.inactive.retentionInterval)
+	if err != nil {
+		return err
+	}
+
+	if inactivePolicy == devstore.StoreAliveMonitorPolicyOffline &&
+		inactiveRetentionInterval <= inactiveMaxInterval {
+		return errors.New("device-monitor-inactive-retention-interval should be" +
+			" greater than device-monitor-inactive-max-interval")
+	}
+
+	cfg.monitor.updateInterval = inactiveUpdateInterval
+	cfg.monitor.params = devstore.StoreAliveMonitorParams{
+		MaxInactiveInterval: inactiveMaxInterval,
+		Policy:              inactivePolicy,
+		RetentionInterval:   inactiveRetentionInterval,
+	}
+
+	return nil
+}
+
+func parseMdnsConfig(cfg *appConfig, opts *appOptions) error {
+	mdnsBrowseInterval, err := time.ParseDuration(opts.mdns.browse.interval)
+	if err != nil {
+		return err
+	}
+	if mdnsBrowseInterval < time.Second {
+		return errors.New("mDNS browse interval can't be less than 1s")
+	}
+
+	mdnsBrowseTimeout, err := time.ParseDuration(opts.mdns.browse.timeout)
+	if err != nil {
+		return err
+	}
+	if mdnsBrowseTimeout < time.Second {
+		return errors.New("mDNS browse timeout can't be less than 1s")
+	}
+
+	browseIfaces, err := parseIfaceOption(opts.mdns.browse.iface)
+	if err != nil {
+		return err
+	}
+
+	if opts.mdns.browse.expireAfter < 0 {
+		return errors.New("--mdns-browse-expire-after can't be negative")
+	}
+
+	cfg.mdns.browse.interval = mdnsBrowseInterval
+	cfg.mdns.browse.timeout = mdnsBrowseTimeout
+	cfg.mdns.browse.ifaces = browseIfaces
+	cfg.mdns.browse.expireAfter = opts.mdns.browse.expireAfter
+
+	if !opts.mdns.server.disable {
+		serverIfaces, err := parseIfaceOption(opts.mdns.server.iface)
+		if err != nil {
+			return err
+		}
+
+		cfg.mdns.server.ifaces = serverIfaces
+	}
+
+	return nil
+}
+
+func parseSNTPConfig(cfg *appConfig, opts *appOptions) error {
+	cfg.sntp.server.enable = opts.sntp.server.enable
+
+	if !cfg.sntp.server.enable {
+		return nil
+	}
+
+	if opts.sntp.server.port < 0 || opts.sntp.server.port > 65535 {
+		return errors.New("--sntp-server-port should be in the [0, 65535] range")
+	}
+	if opts.sntp.server.stratum < 1 || opts.sntp.server.stratum > 15 {
+		return errors.New("--sntp-server-stratum should be in the [1, 15] range")
+	}
+
+	cfg.sntp.server.params = systime.SNTPServerParams{
+		Port:       opts.sntp.server.port,
+		Stratum:    uint8(opts.sntp.server.stratum),
+		ValidSince: validTimeSince,
+	}
+
+	return nil
+}
+
+func parseSNTPClientConfig(cfg *appConfig, opts *appOptions) error {
+	cfg.sntp.client.enable = opts.sntp.client.enable
+
+	if !cfg.sntp.client.enable {
+		return nil
+	}
+
+	var servers []string
+	for _, server := range strings.Split(opts.sntp.client.servers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) == 0 {
+		return errors.New("--sntp-client-servers can't be empty")
+	}
+
+	interval, err := time.ParseDuration(opts.sntp.client.interval)
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return errors.New("--sntp-client-interval should be positive")
+	}
+
+	timeout, err := time.ParseDuration(opts.sntp.client.timeout)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return errors.New("--sntp-client-timeout should be positive")
+	}
+
+	stepThreshold, err := time.ParseDuration(opts.sntp.client.stepThreshold)
+	if err != nil {
+		return err
+	}
+	if stepThreshold < 0 {
+		return errors.New("--sntp-client-step-threshold can't be negative")
+	}
+
+	outlierThreshold, err := time.ParseDuration(opts.sntp.client.outlierThreshold)
+	if err != nil {
+		return err
+	}
+	if outlierThreshold < 0 {
+		return errors.New("--sntp-client-outlier-threshold can't be negative")
+	}
+
+	cfg.sntp.client.interval = interval
+	cfg.sntp.client.params = systime.SNTPClientParams{
+		Servers:          servers,
+		Timeout:          timeout,
+		StepThreshold:    stepThreshold,
+		OutlierThreshold: outlierThreshold,
+	}
+
+	return nil
+}
+
+func parseFakeHwclockConfig(cfg *appConfig, opts *appOptions) error {
+	cfg.fakeHwclock.enable = opts.fakeHwclock.enable
+
+	if !cfg.fakeHwclock.enable {
+		return nil
+	}
+
+	if opts.cacheDir == "" {
+		return errors.New("--fake-hwclock requires --cache-dir")
+	}
+
+	interval, err := time.ParseDuration(opts.fakeHwclock.interval)
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return errors.New("--fake-hwclock-interval should be positive")
+	}
+
+	cfg.fakeHwclock.interval = interval
+
+	return nil
+}
+
+// validateOptions checks the options without changing the environment.
+func validateOptions(opts *appOptions) error {
+	storages := newAppPipeline().newStorageRegistry(opts, &appConfig{}).Names()
+	if !slices.Contains(storages, opts.storage.backend) {
+		return fmt.Errorf("unknown storage: %q, should be one of: %s",
+			opts.storage.backend, strings.Join(storages, "|"))
+	}
+
+	switch opts.storage.backend {
+	case "influxdb":
+		if opts.storage.influxdb.URL == "" {
+			return fmt.Errorf("influxdb URL is required")
+		}
+		if opts.storage.influxdb.Org == "" {
+			return fmt.Errorf("influxdb org is required")
+		}
+		if opts.storage.influxdb.Bucket == "" {
+			return fmt.Errorf("influxdb bucket is required")
+		}
+		if opts.storage.influxdb.Token == "" {
+			return fmt.Errorf("influxdb token is required")
+		}
+
+	case "file":
+		if opts.storage.file.path == "" {
+			return fmt.Errorf("file storage path is required")
+		}
+	}
+
+	if opts.cacheDir != "" {
+		fi, err := os.Stat(opts.cacheDir)
+		if err != nil {
+			return err
+		}
+
+		if !fi.Mode().IsDir() {
+			return errors.New("cache path should be a directory")
+		}
+	}
+
+	if opts.logDir == "" {
+		return fmt.Errorf("log directory is required")
+	}
+	fi, err := os.Stat(opts.logDir)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsDir() {
+		return errors.New("log path should be a directory")
+	}
+
+	if !opts.mdns.server.disable {
+		if opts.mdns.server.hostname == "" {
+			return errors.New("mDNS server hostname can't be empty")
+		}
+	}
+
+	return nil
+}
+
+func parseIfaceOption(opt string) ([]net.Interface, error) {
+	var allowedIfaces []string
+
+	if opt != "" {
+		allowedIfaces = strings.Split(opt, ",")
+		if len(allowedIfaces) < 1 {
+			return nil, errors.New("mDNS network interface list has invalid format")
+		}
+	}
+
+	filteredIfaces, err := sysnet.FilterInterfaces(func(iface net.Interface) bool {
+		if iface.Flags&net.FlagMulticast == 0 {
+			return false
+		}
+
+		if allowedIfaces == nil {
+			return true
+		}
+
+		for _, allowedIface := range allowedIfaces {
+			if allowedIface == iface.Name {
+				return true
+			}
+		}
+
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return filteredIfaces, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func runTestCheckConfig(t *testing.T, args ...string) (string, error) {
+	cmd := newRootCommand(newAppPipeline(), &appOptions{})
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(append([]string{"check-config", "--log-dir", t.TempDir()}, args...))
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestCheckConfigStorage(t *testing.T) {
+	out, err := runTestCheckConfig(t, "--storage", "none")
+	require.Nil(t, err)
+	require.Contains(t, out, "none")
+}
+
+func TestCheckConfigUnknownStorage(t *testing.T) {
+	out, err := runTestCheckConfig(t, "--storage", "bogus")
+	require.ErrorContains(t, err, "unknown storage")
+	require.Empty(t, out)
+}
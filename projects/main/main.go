@@ -2,117 +2,141 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
-	"strings"
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.etcd.io/bbolt"
 
 	"github.com/open-control-systems/zeroconf"
 
+	"github.com/open-control-systems/device-hub/components/device/devcore"
 	"github.com/open-control-systems/device-hub/components/device/devstore"
 	"github.com/open-control-systems/device-hub/components/http/htcore"
 	"github.com/open-control-systems/device-hub/components/http/hthandler"
 	"github.com/open-control-systems/device-hub/components/storage/stcore"
+	"github.com/open-control-systems/device-hub/components/storage/stfile"
 	"github.com/open-control-systems/device-hub/components/storage/stinfluxdb"
+	"github.com/open-control-systems/device-hub/components/storage/stprometheus"
+	"github.com/open-control-systems/device-hub/components/system/sysconfig"
 	"github.com/open-control-systems/device-hub/components/system/syscore"
 	"github.com/open-control-systems/device-hub/components/system/sysmdns"
+	"github.com/open-control-systems/device-hub/components/system/sysmetrics"
 	"github.com/open-control-systems/device-hub/components/system/sysnet"
 	"github.com/open-control-systems/device-hub/components/system/syssched"
+	"github.com/open-control-systems/device-hub/components/system/systime"
 )
 
-type appOptions struct {
-	logDir   string
-	cacheDir string
-	port     int
+var appLogger = syscore.NewLogger("app")
 
-	storage struct {
-		influxdb stinfluxdb.DBParams
-	}
-
-	device struct {
-		http struct {
-			fetchTimeout  string
-			fetchInterval string
-		}
-
-		monitor struct {
-			inactive struct {
-				disable        bool
-				maxInterval    string
-				updateInterval string
-			}
-		}
-
-		timeSync struct {
-			disable          bool
-			maxDriftInterval string
-		}
-	}
-
-	mdns struct {
-		browse struct {
-			interval string
-			timeout  string
-			iface    string
-		}
-
-		autodiscovery struct {
-			disable bool
-		}
-
-		server struct {
-			disable  bool
-			hostname string
-			iface    string
-		}
-	}
-}
+// validTimeSince is the point since which the local UNIX time is considered valid,
+// 2024/12/03.
+var validTimeSince = time.Unix(1733215816, 0)
 
 type appPipeline struct {
 	stopper     *syssched.FanoutStopper
 	starter     *syssched.FanoutStarter
 	systemClock syscore.SystemClock
+	bboltDB     *bbolt.DB
+
+	// Components reconfigured on reload.
+	cacheStore         *devstore.CacheStore
+	aliveMonitor       *devstore.StoreAliveMonitor
+	aliveMonitorRunner *syssched.AsyncTaskRunner
+	mdnsBrowserRunner  *syssched.AsyncTaskRunner
+	logFileParams      syscore.LogFileParams
 }
 
-func (p *appPipeline) start(opts *appOptions) error {
+func (p *appPipeline) start(opts *appOptions, loader *sysconfig.FlagLoader) error {
+	cfg, err := parseAppConfig(opts)
+	if err != nil {
+		return err
+	}
+
 	appContext, cancelFunc := signal.NotifyContext(context.Background(),
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
 	defer cancelFunc()
 
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
+
+	logPath := getLogPath(opts)
+	p.logFileParams = cfg.log.file
+
 	resolveStore := sysnet.NewResolveStore()
 	resolveServiceHandler := sysmdns.NewResolveServiceHandler(resolveStore)
 
 	fanoutServiceHandler := &sysmdns.FanoutServiceHandler{}
 	fanoutServiceHandler.Add(resolveServiceHandler)
 
-	mdnsBrowseAwakener, err := p.createMdnsBrowser(appContext, fanoutServiceHandler, opts)
+	mdnsBrowseAwakener, err := p.createMdnsBrowser(appContext, fanoutServiceHandler, cfg)
 	if err != nil {
 		return err
 	}
 
-	deviceStore, err := p.createDeviceStore(appContext, resolveStore, mdnsBrowseAwakener, opts)
+	if err := p.openDB(opts); err != nil {
+		return err
+	}
+
+	storagePipeline, err := p.createStoragePipeline(appContext, opts, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.fakeHwclock.enable {
+		p.createFakeHwclock(appContext, storagePipeline.GetSystemClock(), cfg)
+	}
+
+	prometheusDataHandler := stprometheus.NewDataHandler()
+
+	fanoutDataHandler := &devcore.FanoutDataHandler{}
+	fanoutDataHandler.Add(storagePipeline.GetDataHandler())
+	fanoutDataHandler.Add(prometheusDataHandler)
+
+	cacheStore, err := p.createCacheStore(
+		appContext,
+		resolveStore,
+		storagePipeline.GetSystemClock(),
+		fanoutDataHandler,
+		cfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	deviceStore, err := p.createDeviceStore(appContext, cacheStore, mdnsBrowseAwakener, cfg)
 	if err != nil {
 		return err
 	}
 
 	if !opts.mdns.autodiscovery.disable {
 		storeMdnsHandler := devstore.NewStoreMdnsHandler(deviceStore)
+		if p.aliveMonitor != nil {
+			storeMdnsHandler.SetOfflineHandler(p.aliveMonitor)
+		}
 		fanoutServiceHandler.Add(storeMdnsHandler)
 	}
 
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(
+		stprometheus.NewCollector(prometheusDataHandler, deviceStore),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
 	mux := http.NewServeMux()
 	crashHandler := hthandler.NewCrashHandler(mux)
 
@@ -125,15 +149,45 @@ func (p *appPipeline) start(opts *appOptions) error {
 	p.stopper.Add("http-server", server)
 	p.starter.Add(server)
 
+	timeHandler := hthandler.NewSystemTimeHandler(p.systemClock, validTimeSince)
+
+	if cfg.sntp.client.enable {
+		timeHandler.SetSNTPStatusProvider(p.createSNTPClient(appContext, cfg))
+	}
+
 	registerHTTPRoutes(
 		mux,
-		// Time valid since 2024/12/03.
-		hthandler.NewSystemTimeHandler(p.systemClock, time.Unix(1733215816, 0)),
+		timeHandler,
 		devstore.NewStoreHTTPHandler(deviceStore),
+		devstore.NewStoreHTTPHandlerV2(deviceStore, 64*1024),
+		devstore.NewPushHTTPHandler(cacheStore, 64*1024),
+		promhttp.HandlerFor(
+			prometheus.Gatherers{sysmetrics.Registry, metricsRegistry},
+			promhttp.HandlerOpts{},
+		),
 	)
 
+	if influxdbPipeline, ok := storagePipeline.(*stinfluxdb.Pipeline); ok {
+		if queueWriter := influxdbPipeline.GetQueueWriter(); queueWriter != nil {
+			mux.Handle("/api/v1/storage/influxdb/queue",
+				stinfluxdb.NewQueueHTTPHandler(queueWriter))
+		}
+	}
+
+	var sntpServer *systime.SNTPServer
+
+	if cfg.sntp.server.enable {
+		sntpServer, err = systime.NewSNTPServer(
+			&syscore.LocalMonotonicClock{}, cfg.sntp.server.params)
+		if err != nil {
+			return fmt.Errorf("failed to create SNTP server: %w", err)
+		}
+		p.stopper.Add("sntp-server", sntpServer)
+		p.starter.Add(sntpServer)
+	}
+
 	if !opts.mdns.server.disable {
-		if err := p.configureMdnsServer(server, opts); err != nil {
+		if err := p.configureMdnsServer(server, sntpServer, opts, cfg); err != nil {
 			return err
 		}
 	}
@@ -142,111 +196,128 @@ func (p *appPipeline) start(opts *appOptions) error {
 		return err
 	}
 
-	<-appContext.Done()
+	for {
+		select {
+		case <-reloadCh:
+			p.reload(opts, loader, logPath)
 
-	return nil
-}
-
-func (p *appPipeline) stop() error {
-	return p.stopper.Stop()
+		case <-appContext.Done():
+			return nil
+		}
+	}
 }
 
-func (p *appPipeline) createDeviceStore(
-	ctx context.Context,
-	resolveStore *sysnet.ResolveStore,
-	awakener syssched.Awakener,
+// reload reopens the log file and applies the re-read configuration to the running
+// components, the current configuration is kept if the new one is invalid.
+func (p *appPipeline) reload(
 	opts *appOptions,
-) (devstore.Store, error) {
-	cacheStore, err := p.createCacheStore(ctx, resolveStore, opts)
-	if err != nil {
-		return nil, err
-	}
+	loader *sysconfig.FlagLoader,
+	logPath string,
+) {
+	appLogger.Info("reloading configuration")
 
-	awakeStore := devstore.NewAwakeStore(awakener, cacheStore)
+	cfg, err := reloadConfig(opts, loader)
+	if err == nil {
+		p.logFileParams = cfg.log.file
+	}
 
-	if opts.device.monitor.inactive.disable {
-		return awakeStore, nil
+	// The log file is reopened even if the new configuration is invalid, so it can
+	// still be rotated by the external tools.
+	if err := syscore.SetLogFile(logPath, p.logFileParams); err != nil {
+		appLogger.Error("failed to reopen log file", "path", logPath, "err", err)
 	}
 
-	inactiveMaxInterval, err :=
-		time.ParseDuration(opts.device.monitor.inactive.maxInterval)
 	if err != nil {
-		return nil, err
+		appLogger.Error("failed to reload configuration", "err", err)
+
+		return
 	}
 
-	if inactiveMaxInterval < time.Millisecond {
-		return nil, errors.New("device-monitor-inactive-max-interval can't be" +
-			" less than 1ms")
+	syscore.SetLogLevel(cfg.log.level)
+	syscore.SetLogFormat(cfg.log.format)
+
+	p.cacheStore.SetParams(cfg.cacheStore)
+
+	if p.aliveMonitor != nil && !cfg.monitor.disable {
+		p.aliveMonitor.SetParams(cfg.monitor.params)
+		p.aliveMonitorRunner.SetUpdateInterval(cfg.monitor.updateInterval)
 	}
 
-	inactiveUpdateInterval, err :=
-		time.ParseDuration(opts.device.monitor.inactive.updateInterval)
-	if err != nil {
+	p.mdnsBrowserRunner.SetUpdateInterval(cfg.mdns.browse.interval)
+
+	appLogger.Info("configuration reloaded")
+}
+
+func reloadConfig(opts *appOptions, loader *sysconfig.FlagLoader) (*appConfig, error) {
+	if err := loader.Reload(); err != nil {
 		return nil, err
 	}
 
-	if inactiveUpdateInterval < time.Millisecond {
-		return nil, errors.New("device-monitor-inactive-update-interval can't be" +
-			" less than 1ms")
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	return parseAppConfig(opts)
+}
+
+func (p *appPipeline) stop() error {
+	return p.stopper.Stop()
+}
+
+func (p *appPipeline) createDeviceStore(
+	ctx context.Context,
+	cacheStore *devstore.CacheStore,
+	awakener syssched.Awakener,
+	cfg *appConfig,
+) (devstore.Store, error) {
+	awakeStore := devstore.NewAwakeStore(awakener, cacheStore)
+
+	if cfg.monitor.disable {
+		return awakeStore, nil
 	}
 
 	aliveMonitor := devstore.NewStoreAliveMonitor(
 		&syscore.LocalMonotonicClock{},
 		awakeStore,
-		inactiveMaxInterval,
+		cfg.monitor.params,
 	)
 	cacheStore.SetAliveMonitor(aliveMonitor)
 
+	p.aliveMonitor = aliveMonitor
+
 	aliveMonitorRunner := syssched.NewAsyncTaskRunner(
 		ctx,
 		aliveMonitor,
 		aliveMonitor,
 		syssched.AsyncTaskRunnerParams{
-			UpdateInterval: inactiveUpdateInterval,
+			Name:           "device-alive-monitor",
+			UpdateInterval: cfg.monitor.updateInterval,
 		},
 	)
 
 	p.stopper.Add("device-alive-monitor-runner", aliveMonitorRunner)
 	p.starter.Add(aliveMonitorRunner)
 
+	p.aliveMonitorRunner = aliveMonitorRunner
+
 	return aliveMonitor, nil
 }
 
 func (p *appPipeline) createMdnsBrowser(
 	ctx context.Context,
 	fanoutServiceHandler *sysmdns.FanoutServiceHandler,
-	opts *appOptions,
+	cfg *appConfig,
 ) (syssched.Awakener, error) {
-	mdnsBrowseInterval, err := time.ParseDuration(opts.mdns.browse.interval)
-	if err != nil {
-		return nil, err
-	}
-	if mdnsBrowseInterval < time.Second {
-		return nil, errors.New("mDNS browse interval can't be less than 1s")
-	}
-
-	mdnsBrowseTimeout, err := time.ParseDuration(opts.mdns.browse.timeout)
-	if err != nil {
-		return nil, err
-	}
-	if mdnsBrowseTimeout < time.Second {
-		return nil, errors.New("mDNS browse timeout can't be less than 1s")
-	}
-
-	filteredIfaces, err := parseIfaceOption(opts.mdns.browse.iface)
-	if err != nil {
-		return nil, err
-	}
-
 	mdnsBrowser := sysmdns.NewZeroconfBrowser(
 		ctx,
 		fanoutServiceHandler,
 		sysmdns.ZeroconfBrowserParams{
-			Service: sysmdns.ServiceName(sysmdns.ServiceTypeHTTP, sysmdns.ProtoTCP),
-			Domain:  "local",
-			Timeout: mdnsBrowseTimeout,
+			Service:     sysmdns.ServiceName(sysmdns.ServiceTypeHTTP, sysmdns.ProtoTCP),
+			Domain:      "local",
+			Timeout:     cfg.mdns.browse.timeout,
+			ExpireAfter: cfg.mdns.browse.expireAfter,
 			Opts: []zeroconf.ClientOption{
-				zeroconf.SelectIfaces(filteredIfaces),
+				zeroconf.SelectIfaces(cfg.mdns.browse.ifaces),
 			},
 		},
 	)
@@ -257,102 +328,203 @@ func (p *appPipeline) createMdnsBrowser(
 		mdnsBrowser,
 		mdnsBrowser,
 		syssched.AsyncTaskRunnerParams{
-			UpdateInterval: mdnsBrowseInterval,
+			Name:           "mdns-zeroconf-browser",
+			UpdateInterval: cfg.mdns.browse.interval,
 		},
 	)
 	p.stopper.Add("mdns-zeroconf-browser-runner", mdnsBrowserRunner)
 	p.starter.Add(mdnsBrowserRunner)
 
+	p.mdnsBrowserRunner = mdnsBrowserRunner
+
 	return mdnsBrowserRunner, nil
 }
 
 func (p *appPipeline) createCacheStore(
 	ctx context.Context,
 	resolveStore *sysnet.ResolveStore,
-	opts *appOptions,
+	remoteLastClock syscore.SystemClock,
+	dataHandler devcore.DataHandler,
+	cfg *appConfig,
 ) (*devstore.CacheStore, error) {
-	fetchInterval, err := time.ParseDuration(opts.device.http.fetchInterval)
-	if err != nil {
-		return nil, err
-	}
-	if fetchInterval < time.Millisecond {
-		return nil, errors.New("HTTP device fetch interval can't be less than 1ms")
-	}
+	cacheStoreParams := cfg.cacheStore
 
-	fetchTimeout, err := time.ParseDuration(opts.device.http.fetchTimeout)
-	if err != nil {
-		return nil, err
-	}
-	if fetchTimeout < time.Millisecond {
-		return nil, errors.New("HTTP device fetch timeout can't be less than 1ms")
-	}
+	var scheduler *syssched.PoolScheduler
 
-	var maxDriftInterval time.Duration
+	if cfg.httpWorkers > 0 {
+		scheduler = syssched.NewPoolScheduler(syssched.PoolSchedulerParams{
+			Workers: cfg.httpWorkers,
+		})
 
-	if opts.device.timeSync.maxDriftInterval != "" {
-		interval, err := time.ParseDuration(opts.device.timeSync.maxDriftInterval)
-		if err != nil {
-			return nil, err
-		}
-		if interval < time.Second {
-			return nil, errors.New("--device-time-sync-drift-interval can't be less than 1s")
-		}
-
-		maxDriftInterval = interval
+		cacheStoreParams.Scheduler = scheduler
 	}
 
-	cacheStoreParams := devstore.CacheStoreParams{}
-	cacheStoreParams.HTTP.FetchInterval = fetchInterval
-	cacheStoreParams.HTTP.FetchTimeout = fetchTimeout
-	cacheStoreParams.TimeSync.MaxDriftInterval = maxDriftInterval
-	cacheStoreParams.TimeSync.Disable = opts.device.timeSync.disable
-
-	db, err := p.createDB(opts)
-	if err != nil {
-		return nil, err
-	}
-
-	storagePipeline := stinfluxdb.NewPipeline(ctx, opts.storage.influxdb)
-	p.stopper.Add("storage-influxdb-pipeline", storagePipeline)
-	p.starter.Add(storagePipeline)
-
 	cacheStore := devstore.NewCacheStore(
 		ctx,
 		p.systemClock,
-		storagePipeline.GetSystemClock(),
-		storagePipeline.GetDataHandler(),
-		db,
+		remoteLastClock,
+		dataHandler,
+		p.createDB("device_bucket"),
 		resolveStore,
 		cacheStoreParams,
 	)
 	p.stopper.Add("device-cache-store", cacheStore)
 	p.starter.Add(cacheStore)
 
+	p.cacheStore = cacheStore
+
+	if scheduler != nil {
+		p.stopper.Add("device-http-scheduler", scheduler)
+		p.starter.Add(scheduler)
+	}
+
 	return cacheStore, nil
 }
 
-func (p *appPipeline) createDB(opts *appOptions) (stcore.DB, error) {
-	if opts.cacheDir == "" {
-		return &stcore.NoopDB{}, nil
+func (p *appPipeline) createStoragePipeline(
+	ctx context.Context,
+	opts *appOptions,
+	cfg *appConfig,
+) (stcore.Pipeline, error) {
+	registry := p.newStorageRegistry(opts, cfg)
+
+	storagePipeline, err := registry.Create(ctx, opts.storage.backend)
+	if err != nil {
+		return nil, err
 	}
+	p.stopper.Add("storage-"+opts.storage.backend+"-pipeline", storagePipeline)
+	p.starter.Add(storagePipeline)
+
+	return storagePipeline, nil
+}
 
-	opts.cacheDir = path.Join(opts.cacheDir, "bbolt.db")
+// newStorageRegistry returns the registry of all supported storages.
+//
+// Remarks:
+//   - Storage pipelines are created only on the registry Create() call.
+func (p *appPipeline) newStorageRegistry(
+	opts *appOptions,
+	cfg *appConfig,
+) *stcore.PipelineRegistry {
+	registry := stcore.NewPipelineRegistry()
+
+	registry.Register("none", func(_ context.Context) (stcore.Pipeline, error) {
+		return &stcore.NoopPipeline{}, nil
+	})
+	registry.Register("file", func(ctx context.Context) (stcore.Pipeline, error) {
+		return stfile.NewPipeline(ctx, opts.storage.file.path)
+	})
+	registry.Register("influxdb", func(ctx context.Context) (stcore.Pipeline, error) {
+		return p.createInfluxdbPipeline(ctx, opts, cfg)
+	})
 
-	bboltDB, err := stcore.NewBboltDB(opts.cacheDir, &bbolt.Options{
+	return registry
+}
+
+func (p *appPipeline) createInfluxdbPipeline(
+	ctx context.Context,
+	opts *appOptions,
+	cfg *appConfig,
+) (*stinfluxdb.Pipeline, error) {
+	return stinfluxdb.NewPipeline(
+		ctx,
+		opts.storage.influxdb,
+		p.createDB("influxdb_queue_bucket"),
+		cfg.influxdbQueue,
+	)
+}
+
+func (p *appPipeline) openDB(opts *appOptions) error {
+	if opts.cacheDir == "" {
+		return nil
+	}
+
+	bboltDB, err := stcore.NewBboltDB(path.Join(opts.cacheDir, "bbolt.db"), &bbolt.Options{
 		Timeout: time.Second * 5,
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	p.stopper.Add("bbolt-database", syssched.FuncStopper(func() error {
 		return bboltDB.Close()
 	}))
 
-	return stcore.NewBboltDBBucket(bboltDB, "device_bucket"), nil
+	p.bboltDB = bboltDB
+
+	return nil
 }
 
-func (p *appPipeline) configureMdnsServer(server *htcore.Server, opts *appOptions) error {
+func (p *appPipeline) createSNTPClient(
+	ctx context.Context,
+	cfg *appConfig,
+) *systime.SNTPClient {
+	client := systime.NewSNTPClient(
+		&syscore.LocalMonotonicClock{},
+		&syscore.LocalSystemClock{},
+		cfg.sntp.client.params,
+	)
+
+	runner := syssched.NewAsyncTaskRunner(
+		ctx,
+		client,
+		client,
+		syssched.AsyncTaskRunnerParams{
+			Name:           "sntp-client",
+			UpdateInterval: cfg.sntp.client.interval,
+		},
+	)
+	p.stopper.Add("sntp-client", runner)
+	p.starter.Add(runner)
+
+	return client
+}
+
+// createFakeHwclock moves the local UNIX time forward to the saved one before any
+// component relying on the local UNIX time is started.
+//
+// Remarks:
+//   - Restoring errors aren't fatal, e.g. the process may lack the permission to
+//     change the local UNIX time.
+func (p *appPipeline) createFakeHwclock(
+	ctx context.Context,
+	storageClock syscore.SystemClock,
+	cfg *appConfig,
+) {
+	hwclock := stcore.NewFakeHardwareClock(
+		p.systemClock, storageClock, p.createDB("fake_hwclock_bucket"))
+
+	if err := hwclock.Restore(); err != nil {
+		appLogger.Error("failed to restore local UNIX time", "err", err)
+	}
+
+	runner := syssched.NewAsyncTaskRunner(
+		ctx,
+		hwclock,
+		hwclock,
+		syssched.AsyncTaskRunnerParams{
+			Name:           "fake-hwclock",
+			UpdateInterval: cfg.fakeHwclock.interval,
+		},
+	)
+	p.stopper.Add("fake-hwclock", runner)
+	p.starter.Add(runner)
+}
+
+func (p *appPipeline) createDB(bucket string) stcore.DB {
+	if p.bboltDB == nil {
+		return &stcore.NoopDB{}
+	}
+
+	return stcore.NewBboltDBBucket(p.bboltDB, bucket)
+}
+
+func (p *appPipeline) configureMdnsServer(
+	server *htcore.Server,
+	sntpServer *systime.SNTPServer,
+	opts *appOptions,
+	cfg *appConfig,
+) error {
 	services := []*sysmdns.Service{
 		{
 			Instance:   "Device Hub HTTP Service",
@@ -363,62 +535,44 @@ func (p *appPipeline) configureMdnsServer(server *htcore.Server, opts *appOption
 		},
 	}
 
-	filteredIfaces, err := parseIfaceOption(opts.mdns.server.iface)
-	if err != nil {
-		return err
+	if sntpServer != nil {
+		services = append(services, &sysmdns.Service{
+			Instance: "Device Hub SNTP Service",
+			Name:     sysmdns.ServiceName(sysmdns.ServiceTypeNTP, sysmdns.ProtoUDP),
+			Hostname: opts.mdns.server.hostname,
+			Port:     sntpServer.Port(),
+		})
 	}
 
-	zeroconfServer := sysmdns.NewZeroconfServer(services, filteredIfaces)
+	zeroconfServer := sysmdns.NewZeroconfServer(services, cfg.mdns.server.ifaces)
 	p.stopper.Add("mdns-server", zeroconfServer)
 	p.starter.Add(zeroconfServer)
 
 	return nil
 }
 
-func parseIfaceOption(opt string) ([]net.Interface, error) {
-	var allowedIfaces []string
-
-	if opt != "" {
-		allowedIfaces = strings.Split(opt, ",")
-		if len(allowedIfaces) < 1 {
-			return nil, errors.New("mDNS network interface list has invalid format")
-		}
-	}
-
-	filteredIfaces, err := sysnet.FilterInterfaces(func(iface net.Interface) bool {
-		if iface.Flags&net.FlagMulticast == 0 {
-			return false
-		}
-
-		if allowedIfaces == nil {
-			return true
-		}
-
-		for _, allowedIface := range allowedIfaces {
-			if allowedIface == iface.Name {
-				return true
-			}
-		}
-
-		return false
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return filteredIfaces, nil
-}
-
 func registerHTTPRoutes(
 	mux *http.ServeMux,
 	timeHandler http.Handler,
 	storeHTTPHandler *devstore.StoreHTTPHandler,
+	storeHTTPHandlerV2 *devstore.StoreHTTPHandlerV2,
+	pushHTTPHandler *devstore.PushHTTPHandler,
+	metricsHandler http.Handler,
 ) {
 	mux.Handle("/api/v1/system/time", timeHandler)
 
 	mux.HandleFunc("/api/v1/device/add", storeHTTPHandler.HandleAdd)
 	mux.HandleFunc("/api/v1/device/remove", storeHTTPHandler.HandleRemove)
 	mux.HandleFunc("/api/v1/device/list", storeHTTPHandler.HandleList)
+	mux.HandleFunc("/api/v1/device/get", storeHTTPHandler.HandleGet)
+
+	mux.HandleFunc("/api/v1/device/{id}/registration", pushHTTPHandler.HandleRegistration)
+	mux.HandleFunc("/api/v1/device/{id}/telemetry", pushHTTPHandler.HandleTelemetry)
+
+	mux.HandleFunc("/api/v2/devices", storeHTTPHandlerV2.HandleDevices)
+	mux.HandleFunc("/api/v2/devices/{id}", storeHTTPHandlerV2.HandleDevice)
+
+	mux.Handle("/metrics", metricsHandler)
 }
 
 func newAppPipeline() *appPipeline {
@@ -430,56 +584,41 @@ func newAppPipeline() *appPipeline {
 }
 
 func prepareEnvironment(opts *appOptions) error {
-	if opts.storage.influxdb.URL == "" {
-		return fmt.Errorf("influxdb URL is required")
-	}
-	if opts.storage.influxdb.Org == "" {
-		return fmt.Errorf("influxdb org is required")
-	}
-	if opts.storage.influxdb.Bucket == "" {
-		return fmt.Errorf("influxdb bucket is required")
-	}
-	if opts.storage.influxdb.Token == "" {
-		return fmt.Errorf("influxdb token is required")
-	}
-
-	if opts.cacheDir != "" {
-		fi, err := os.Stat(opts.cacheDir)
-		if err != nil {
-			return err
-		}
-
-		if !fi.Mode().IsDir() {
-			return errors.New("cache path should be a directory")
-		}
-	}
-
-	if opts.logDir == "" {
-		return fmt.Errorf("log directory is required")
-	}
-	fi, err := os.Stat(opts.logDir)
-	if err != nil {
+	if err := validateOptions(opts); err != nil {
 		return err
 	}
-	if !fi.Mode().IsDir() {
-		return errors.New("log path should be a directory")
-	}
-	if err := syscore.SetLogFile(filepath.Join(opts.logDir, "app.log")); err != nil {
+
+	cfg := &appConfig{}
+	if err := parseLogConfig(cfg, opts); err != nil {
 		return err
 	}
 
-	if !opts.mdns.server.disable {
-		if opts.mdns.server.hostname == "" {
-			return errors.New("mDNS server hostname can't be empty")
-		}
-	}
+	syscore.SetLogLevel(cfg.log.level)
+	syscore.SetLogFormat(cfg.log.format)
 
-	return nil
+	return syscore.SetLogFile(getLogPath(opts), cfg.log.file)
 }
 
-func main() {
-	pipeline := newAppPipeline()
-	options := &appOptions{}
+func getLogPath(opts *appOptions) string {
+	return filepath.Join(opts.logDir, "app.log")
+}
+
+func newConfigLoader(flags *pflag.FlagSet) *sysconfig.FlagLoader {
+	return sysconfig.NewFlagLoader(flags, sysconfig.FlagLoaderParams{
+		ConfigFlag: "config",
+		EnvPrefix:  "DEVICE_HUB_",
+		Aliases: map[string]string{
+			"storage-backend": "storage",
+		},
+		SecretFlags: []string{
+			"storage-influxdb-api-token",
+		},
+	})
+}
+
+// newRootCommand returns the device-hub CLI command with all subcommands and options.
+func newRootCommand(pipeline *appPipeline, options *appOptions) *cobra.Command {
+	var loader *sysconfig.FlagLoader
 
 	cmd := &cobra.Command{
 		Use:           "device-hub",
@@ -487,11 +626,16 @@ func main() {
 		Long:          "device-hub collects and stores various data from IoT devices",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(c *cobra.Command, _ []string) error {
+			loader = newConfigLoader(c.Flags())
+
+			return loader.Load()
+		},
 		PreRunE: func(_ *cobra.Command, _ []string) error {
 			return prepareEnvironment(options)
 		},
 		RunE: func(_ *cobra.Command, _ []string) error {
-			if err := pipeline.start(options); err != nil {
+			if err := pipeline.start(options, loader); err != nil {
 				return err
 			}
 
@@ -499,107 +643,299 @@ func main() {
 		},
 	}
 
-	cmd.Flags().IntVar(&options.port, "http-port", 0,
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check-config",
+		Short: "Validate configuration and print effective options",
+		Long: "Validate configuration and print effective options in the configuration" +
+			" file format, nothing is started",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			if err := validateOptions(options); err != nil {
+				return err
+			}
+
+			if _, err := parseAppConfig(options); err != nil {
+				return err
+			}
+
+			return loader.Write(c.OutOrStdout())
+		},
+	})
+
+	cmd.PersistentFlags().StringVar(&options.config, "config", "",
+		"Configuration file (YAML or TOML), options are overridden by environment"+
+			" variables and flags")
+
+	cmd.PersistentFlags().IntVar(&options.port, "http-port", 0,
 		"HTTP server port (0 for random port)")
 
-	cmd.Flags().StringVar(&options.cacheDir, "cache-dir", "", "cache directory")
-	cmd.Flags().StringVar(&options.logDir, "log-dir", "", "log directory")
+	cmd.PersistentFlags().StringVar(&options.cacheDir, "cache-dir", "", "cache directory")
+	cmd.PersistentFlags().StringVar(&options.logDir, "log-dir", "", "log directory")
+	cmd.PersistentFlags().StringVar(&options.log.level, "log-level", "info",
+		"Minimum level of the logged records (debug|info|warn|error)")
+	cmd.PersistentFlags().StringVar(&options.log.format, "log-format", "text",
+		"Log records format (text|json), text is logfmt key=value pairs")
+	cmd.PersistentFlags().IntVar(&options.log.maxSize, "log-max-size", 0,
+		"Maximum size of the log file in megabytes before it's rotated (0 to disable)")
+	cmd.PersistentFlags().StringVar(&options.log.maxAge, "log-max-age", "0",
+		"Maximum time the log file is written before it's rotated (0 to disable)")
+	cmd.PersistentFlags().IntVar(&options.log.maxFiles, "log-max-files", 5,
+		"Maximum number of retained rotated log files")
+	cmd.PersistentFlags().BoolVar(&options.log.compress, "log-compress", false,
+		"Compress rotated log files with gzip")
+
+	cmd.PersistentFlags().StringVar(&options.storage.backend, "storage", "influxdb",
+		"Device data storage (influxdb|file|none)")
+
+	cmd.PersistentFlags().StringVar(&options.storage.file.path, "storage-file-path", "",
+		"File to append device data to, one JSON record per line")
+
+	cmd.PersistentFlags().StringVar(&options.storage.influxdb.URL,
+		"storage-influxdb-url", "", "influxdb URL")
+	cmd.PersistentFlags().StringVar(&options.storage.influxdb.Org,
+		"storage-influxdb-org", "", "influxdb Org")
+	cmd.PersistentFlags().StringVar(&options.storage.influxdb.Token,
+		"storage-influxdb-api-token", "", "influxdb API token")
+	cmd.PersistentFlags().StringVar(&options.storage.influxdb.Bucket,
+		"storage-influxdb-bucket", "", "influxdb bucket")
+
+	cmd.PersistentFlags().BoolVar(
+		&options.storage.influxdbQueue.disable,
+		"storage-influxdb-queue-disable", false,
+		"Disable influxdb write queue, data is lost if influxdb is unavailable",
+	)
+	cmd.PersistentFlags().IntVar(
+		&options.storage.influxdbQueue.maxSize,
+		"storage-influxdb-queue-size", 100000,
+		"Maximum number of data points in the influxdb write queue",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.storage.influxdbQueue.maxAge,
+		"storage-influxdb-queue-max-age", "168h",
+		"How long a data point is allowed to stay in the influxdb write queue (0 to disable)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.storage.influxdbQueue.dropPolicy,
+		"storage-influxdb-queue-drop-policy", "oldest",
+		"Which data points are dropped when the influxdb write queue is full (oldest|newest)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.storage.influxdbQueue.replayInterval,
+		"storage-influxdb-queue-replay-interval", "10s",
+		"How often to replay the queued data points to influxdb",
+	)
+	cmd.PersistentFlags().IntVar(
+		&options.storage.influxdbQueue.batchSize,
+		"storage-influxdb-queue-batch-size", 500,
+		"Maximum number of data points replayed to influxdb in a single write",
+	)
 
-	cmd.Flags().StringVar(&options.storage.influxdb.URL, "storage-influxdb-url", "",
-		"influxdb URL")
-	cmd.Flags().StringVar(&options.storage.influxdb.Org, "storage-influxdb-org", "",
-		"influxdb Org")
-	cmd.Flags().StringVar(&options.storage.influxdb.Token, "storage-influxdb-api-token", "",
-		"influxdb API token")
-	cmd.Flags().StringVar(&options.storage.influxdb.Bucket, "storage-influxdb-bucket", "",
-		"influxdb bucket")
+	cmd.PersistentFlags().StringVar(
+		&options.device.profileDir,
+		"device-profile-dir", "",
+		"Directory with JSON/YAML profiles of the HTTP devices (empty to use defaults)",
+	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.device.http.fetchInterval,
 		"device-http-fetch-interval", "5s",
 		"HTTP device data fetch interval",
 	)
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.device.http.fetchTimeout,
 		"device-http-fetch-timeout", "5s",
 		"HTTP device data fetch timeout",
 	)
+	cmd.PersistentFlags().StringVar(
+		&options.device.http.offlineMaxFetchInterval,
+		"device-http-offline-max-fetch-interval", "5m",
+		"Maximum HTTP data fetch interval for an offline device (0 to disable backoff)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.device.http.errorMaxFetchInterval,
+		"device-http-error-max-fetch-interval", "0",
+		"Maximum HTTP data fetch interval after consecutive failures (0 to disable backoff)",
+	)
+	cmd.PersistentFlags().Float64Var(
+		&options.device.http.fetchJitter,
+		"device-http-fetch-jitter", 0,
+		"How much the HTTP data fetch interval is randomized, in the [0, 1] range"+
+			" (0 to disable)",
+	)
+	cmd.PersistentFlags().IntVar(
+		&options.device.http.workers,
+		"device-http-workers", 0,
+		"Number of goroutines shared by all HTTP devices (0 for a goroutine per device)",
+	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.device.monitor.inactive.maxInterval,
 		"device-monitor-inactive-max-interval", "2m",
 		"How long it's allowed for a device to be inactive",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.device.monitor.inactive.updateInterval,
 		"device-monitor-inactive-update-interval", "10s",
 		"How often to check for a device inactivity",
 	)
 
-	cmd.Flags().BoolVar(
+	cmd.PersistentFlags().BoolVar(
 		&options.device.monitor.inactive.disable,
 		"device-monitor-inactive-disable", false,
 		"Disable device inactivity monitoring",
 	)
 
-	cmd.Flags().BoolVar(
+	cmd.PersistentFlags().StringVar(
+		&options.device.monitor.inactive.policy,
+		"device-monitor-inactive-policy", "remove",
+		"How to handle an inactive device (remove|offline)",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&options.device.monitor.inactive.retentionInterval,
+		"device-monitor-inactive-retention-interval", "168h",
+		"How long an offline device is kept before it's removed",
+	)
+
+	cmd.PersistentFlags().BoolVar(
 		&options.device.timeSync.disable,
 		"device-time-sync-disable", false,
 		"Disable automatic device time synchronization",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.device.timeSync.maxDriftInterval,
 		"device-time-sync-drift-interval", "5s",
 		"Maximum allowed time drift between local and device UNIX time"+
 			" (empty to disable drift check)",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
+		&options.device.timeSync.correction,
+		"device-time-correction", "none",
+		"How to handle device data with invalid timestamp: none to reject the data,"+
+			" receive to use the local receive time, offset to adjust the timestamp by"+
+			" the offset between local and device UNIX time (none|receive|offset)",
+	)
+
+	cmd.PersistentFlags().StringVar(
 		&options.mdns.browse.interval,
 		"mdns-browse-interval", "40s",
 		"How often to perform mDNS lookup over local network",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.mdns.browse.timeout,
 		"mdns-browse-timeout", "10s",
 		"How long to perform a single mDNS lookup over local network",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.mdns.browse.iface,
 		"mdns-browse-iface", "",
 		"Comma-separated list of network interfaces for the mDNS lookup"+
 			" (empty for all interfaces)",
 	)
 
-	cmd.Flags().BoolVar(
+	cmd.PersistentFlags().IntVar(
+		&options.mdns.browse.expireAfter,
+		"mdns-browse-expire-after", 3,
+		"Number of consecutive mDNS lookups a service isn't seen before it's removed"+
+			" (0 to disable)",
+	)
+
+	cmd.PersistentFlags().BoolVar(
 		&options.mdns.autodiscovery.disable,
 		"mdns-autodiscovery-disable", false,
 		"Disable automatic device discovery on the local network",
 	)
 
-	cmd.Flags().BoolVar(
+	cmd.PersistentFlags().BoolVar(
 		&options.mdns.server.disable,
 		"mdns-server-disable", false,
 		"Disable mDNS server",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.mdns.server.hostname,
 		"mdns-server-hostname", "device-hub",
 		"mDNS server hostname",
 	)
 
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&options.mdns.server.iface,
 		"mdns-server-iface", "",
 		"Comma-separated list of network interfaces for the mDNS server"+
 			" (empty for all interfaces)",
 	)
 
+	cmd.PersistentFlags().BoolVar(
+		&options.sntp.server.enable,
+		"sntp-server-enable", false,
+		"Enable SNTP server, so the devices can synchronize their clocks with the hub",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&options.sntp.server.port,
+		"sntp-server-port", 123,
+		"SNTP server UDP port (0 for random port)",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&options.sntp.server.stratum,
+		"sntp-server-stratum", 10,
+		"Stratum of the hub clock reported by the SNTP server, in the [1, 15] range",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&options.sntp.client.enable,
+		"sntp-client-enable", false,
+		"Enable SNTP client, so the hub synchronizes its clock with the upstream servers",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.sntp.client.servers,
+		"sntp-client-servers", "pool.ntp.org",
+		"Comma-separated list of the upstream SNTP servers, host or host:port",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.sntp.client.interval,
+		"sntp-client-interval", "10m",
+		"How often to synchronize the hub clock with the upstream SNTP servers",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.sntp.client.timeout,
+		"sntp-client-timeout", "5s",
+		"How long to wait for the reply from a single upstream SNTP server",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.sntp.client.stepThreshold,
+		"sntp-client-step-threshold", "128ms",
+		"The hub clock is stepped if its offset exceeds this value, otherwise it's slewed",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.sntp.client.outlierThreshold,
+		"sntp-client-outlier-threshold", "100ms",
+		"Maximum difference between the server offset and the median offset of all servers",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&options.fakeHwclock.enable,
+		"fake-hwclock", false,
+		"Save the local UNIX time to the cache and move the clock forward on startup",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.fakeHwclock.interval,
+		"fake-hwclock-interval", "1m",
+		"How often to save the local UNIX time to the cache",
+	)
+
+	return cmd
+}
+
+func main() {
+	cmd := newRootCommand(newAppPipeline(), &appOptions{})
+
 	if err := cmd.Execute(); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "error: failed to execute command: %v", err)
 		os.Exit(1)